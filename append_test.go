@@ -0,0 +1,68 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+// AllocsPerRun can not be called in parallel tests.
+func TestAppendFormat(t *testing.T) {
+	var (
+		bc  = infounit.ByteCount(123456789)
+		bic = infounit.BitCount(123456789)
+		bd  = infounit.ByteDelta(-123456789)
+		bid = infounit.BitDelta(-123456789)
+		br  = infounit.BitRate(-123456789.5)
+		byr = infounit.ByteRate(123456789.5)
+	)
+	tc := []struct {
+		name   string
+		append func([]byte, *infounit.Formatter) []byte
+		text   func([]byte) ([]byte, error)
+		format func(*infounit.Formatter) string
+		str    string
+	}{
+		{"ByteCount", bc.AppendFormat, bc.AppendText, func(f *infounit.Formatter) string { return f.FormatByteCount(bc) }, bc.String()},
+		{"BitCount", bic.AppendFormat, bic.AppendText, func(f *infounit.Formatter) string { return f.FormatBitCount(bic) }, bic.String()},
+		{"ByteDelta", bd.AppendFormat, bd.AppendText, func(f *infounit.Formatter) string { return f.FormatByteDelta(bd) }, bd.String()},
+		{"BitDelta", bid.AppendFormat, bid.AppendText, func(f *infounit.Formatter) string { return f.FormatBitDelta(bid) }, bid.String()},
+		{"BitRate", br.AppendFormat, br.AppendText, func(f *infounit.Formatter) string { return f.FormatBitRate(br) }, br.String()},
+		{"ByteRate", byr.AppendFormat, byr.AppendText, func(f *infounit.Formatter) string { return f.FormatByteRate(byr) }, byr.String()},
+	}
+	fmts := []*infounit.Formatter{
+		nil,
+		{},
+		{Precision: -1},
+		{Prefixes: infounit.BinaryPrefixes, Precision: 2, Separator: " ", LongNames: true},
+		{Precision: 2, MaxPrefix: infounit.PrefixKilo, PlusSign: true},
+		{SignificantDigits: 3, Number: infounit.NumberFormatGerman},
+	}
+	buf := make([]byte, 0, 128)
+	for _, c := range tc {
+		for _, f := range fmts {
+			want := c.str
+			if f != nil {
+				want = c.format(f)
+			}
+			buf = append(buf[:0], "x="...)
+			if s := string(c.append(buf, f)); s != "x="+want {
+				t.Errorf(`%s: %+v: want: "x=%s", got: "%s"`, c.name, f, want, s)
+			}
+			if n := testing.AllocsPerRun(100, func() {
+				buf = c.append(buf[:2], f)
+			}); n != 0 {
+				t.Errorf("%s: %+v: unexpected allocations: %v", c.name, f, n)
+			}
+		}
+		if n := testing.AllocsPerRun(100, func() {
+			buf, _ = c.text(buf[:2])
+		}); n != 0 {
+			t.Errorf("%s: AppendText: unexpected allocations: %v", c.name, n)
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"regexp"
+	"math/bits"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -47,7 +47,44 @@ const (
 // String returns the human-readable string representing the bit count using SI
 // prefix. This implements the Stringer interface in the package fmt.
 func (bc BitCount) String() string {
-	return fmt.Sprintf("% .1s", bc)
+	var buf [fmtBufSize]byte
+	return string(bc.AppendFormat(buf[:0], nil))
+}
+
+// FormatIn returns the string representation of the bit count in the unit, such
+// as Kilobit, with prec digits after the decimal point, e.g. "1500.0 kbit" for
+// 1.5 Mbit in Kilobit. Unlike String, the prefix is never selected
+// automatically, so that all the values in a column of a table can be formatted
+// in the same unit. If unit is not Bit or one of the constants with a prefix,
+// ErrInvalidUnit is returned. See Formatter.FormatBitCountIn for the other
+// options such as the long unit names.
+func (bc BitCount) FormatIn(unit BitCount, prec int) (string, error) {
+	f := Formatter{Precision: prec, Separator: " "}
+	return f.FormatBitCountIn(bc, unit)
+}
+
+// BestBitCountUnit returns the unit in which the largest of the values is
+// formatted with the prefix system ps and prec digits after the decimal point,
+// e.g. Gigabit for the SI prefixes if it is 12.5 Gbit. See BestByteCountUnit
+// for details. It returns Bit if vs is empty.
+func BestBitCountUnit(vs []BitCount, ps PrefixSystem, prec int) BitCount {
+	var max BitCount
+	for _, v := range vs {
+		if max < v {
+			max = v
+		}
+	}
+	f := Formatter{Prefixes: ps, Precision: prec}
+	return BitCount(f.uintUnit(uint64(max)))
+}
+
+// AppendFormat appends the human-readable string representation of the
+// BitCount value formatted by f to dst and returns the extended buffer. If f is
+// nil, the default format "% .1s", the same as String, is used. Unlike String
+// and the Printf family functions, this does not allocate as long as dst has
+// enough capacity.
+func (bc BitCount) AppendFormat(dst []byte, f *Formatter) []byte {
+	return f.orDefault().appendUint(dst, uint64(bc), unitBit)
 }
 
 // GoString returns a string representation of the BitCount value in Go syntax
@@ -59,7 +96,168 @@ func (bc BitCount) GoString() string {
 // ByteCount returns the value converted to the number of bytes and the
 // number of remaining bits.
 func (bc BitCount) ByteCount() (ByteCount, BitCount) {
-	return ByteCount(uint64(bc) >> 3), bc & 0x7
+	v, _ := bc.Div(8) // never fails, since the divisor is not zero
+	return ByteCount(v), bc - v*8
+}
+
+// Delta returns the signed difference bc-u as a BitDelta. If the difference
+// exceeds the range of BitDelta, an ErrOutOfRange will be returned.
+func (bc BitCount) Delta(u BitCount) (BitDelta, error) {
+	if u <= bc {
+		v, err := signInt64(uint64(bc-u), false)
+		return BitDelta(v), err
+	}
+	v, err := signInt64(uint64(u-bc), true)
+	return BitDelta(v), err
+}
+
+// AddDelta returns bc+d. If the result is negative or exceeds the range of
+// BitCount, an ErrOutOfRange will be returned.
+func (bc BitCount) AddDelta(d BitDelta) (BitCount, error) {
+	m := BitCount(absInt64(int64(d)))
+	switch {
+	case d < 0 && bc < m, 0 < d && ^bc < m:
+		return 0, ErrOutOfRange
+	case d < 0:
+		return bc - m, nil
+	}
+	return bc + m, nil
+}
+
+// Add returns bc+u. If the result exceeds the range of BitCount, an
+// ErrOutOfRange will be returned instead of a wrapped around value.
+func (bc BitCount) Add(u BitCount) (BitCount, error) {
+	v, carry := bits.Add64(uint64(bc), uint64(u), 0)
+	if carry != 0 {
+		return 0, ErrOutOfRange
+	}
+	return BitCount(v), nil
+}
+
+// Sub returns bc-u. If u is greater than bc, an ErrOutOfRange will be returned
+// instead of a wrapped around value.
+func (bc BitCount) Sub(u BitCount) (BitCount, error) {
+	if bc < u {
+		return 0, ErrOutOfRange
+	}
+	return bc - u, nil
+}
+
+// Mul returns bc*n. If the result exceeds the range of BitCount, an
+// ErrOutOfRange will be returned instead of a wrapped around value.
+func (bc BitCount) Mul(n uint64) (BitCount, error) {
+	v, err := mulUint64(uint64(bc), n)
+	return BitCount(v), err
+}
+
+// MulFloat returns bc*f rounded to the nearest integer. Since the calculation
+// is done in float64, the result may not be exact for large values. If the
+// result is negative, NaN, or exceeds the range of BitCount, an ErrOutOfRange
+// will be returned.
+func (bc BitCount) MulFloat(f float64) (BitCount, error) {
+	v := math.Round(float64(bc) * f)
+	if !(0 <= v && v < 0x1p64) {
+		return 0, ErrOutOfRange
+	}
+	return BitCount(v), nil
+}
+
+// Div returns bc/n, truncated toward zero. If n is zero, an ErrDivZero will be
+// returned.
+func (bc BitCount) Div(n uint64) (BitCount, error) {
+	if n == 0 {
+		return 0, ErrDivZero
+	}
+	return bc / BitCount(n), nil
+}
+
+// SaturatingAdd returns bc+u. If the result exceeds the range of BitCount, the
+// maximum value of BitCount is returned.
+func (bc BitCount) SaturatingAdd(u BitCount) BitCount {
+	v, err := bc.Add(u)
+	if err != nil {
+		return math.MaxUint64
+	}
+	return v
+}
+
+// SaturatingSub returns bc-u. If u is greater than bc, zero is returned.
+func (bc BitCount) SaturatingSub(u BitCount) BitCount {
+	v, err := bc.Sub(u)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// Truncate returns the result of rounding bc toward zero to a multiple of m.
+// If m is zero, Truncate returns bc unchanged.
+func (bc BitCount) Truncate(m BitCount) BitCount {
+	if m == 0 {
+		return bc
+	}
+	return bc - bc%m
+}
+
+// Round returns the result of rounding bc to the nearest multiple of m. The
+// rounding behavior for halfway values is to round away from zero. If the
+// result exceeds the maximum value that can be stored in a BitCount, Round
+// returns the maximum value. If m is zero, Round returns bc unchanged.
+func (bc BitCount) Round(m BitCount) BitCount {
+	if m == 0 {
+		return bc
+	}
+	r := bc % m
+	if r < m-r {
+		return bc - r
+	}
+	return (bc - r).SaturatingAdd(m)
+}
+
+// AlignUp returns the smallest multiple of m that is greater than or equal to
+// bc. If the result exceeds the range of BitCount, an ErrOutOfRange will be
+// returned. If m is zero, AlignUp returns bc unchanged.
+func (bc BitCount) AlignUp(m BitCount) (BitCount, error) {
+	if m == 0 {
+		return bc, nil
+	}
+	r := bc % m
+	if r == 0 {
+		return bc, nil
+	}
+	return (bc - r).Add(m)
+}
+
+// AlignDown returns the largest multiple of m that is less than or equal to
+// bc. This is the same as Truncate. If m is zero, AlignDown returns bc
+// unchanged.
+func (bc BitCount) AlignDown(m BitCount) BitCount {
+	return bc.Truncate(m)
+}
+
+// IsAligned returns whether bc is a multiple of m. If m is zero, IsAligned
+// returns true.
+func (bc BitCount) IsAligned(m BitCount) bool {
+	return m == 0 || bc%m == 0
+}
+
+// AlignUpToByte returns bc rounded up to the next byte boundary, that is, a
+// multiple of 8 bits. If the result exceeds the range of BitCount, an
+// ErrOutOfRange will be returned.
+func (bc BitCount) AlignUpToByte() (BitCount, error) {
+	return bc.AlignUp(8)
+}
+
+// AlignDownToByte returns bc rounded down to the byte boundary, that is, a
+// multiple of 8 bits.
+func (bc BitCount) AlignDownToByte() BitCount {
+	return bc.AlignDown(8)
+}
+
+// IsByteAligned returns whether bc is on a byte boundary, that is, a multiple
+// of 8 bits.
+func (bc BitCount) IsByteAligned() bool {
+	return bc.IsAligned(8)
 }
 
 // Convert converts the bit count to a float value in the specified unit. If the
@@ -165,18 +363,25 @@ func (bc *BitCount) UnmarshalBinary(data []byte) error {
 // the result. This implements the TextMarshaler interface in the
 // package encoding.
 func (bc *BitCount) MarshalText() ([]byte, error) {
-	v := uint64(AtomicLoadBitCount(bc))
-	return ([]byte)(fmt.Sprintf("%d bit", v)), nil
+	return bc.AppendText(make([]byte, 0, 32))
+}
+
+// AppendText appends the UTF-8-encoded text form of the BitCount value, the same
+// as MarshalText, to dst and returns the extended buffer. This implements the
+// TextAppender interface in the package encoding.
+func (bc *BitCount) AppendText(dst []byte) ([]byte, error) {
+	dst = strconv.AppendUint(dst, uint64(AtomicLoadBitCount(bc)), 10)
+	return append(dst, " bit"...), nil
 }
 
 // UnmarshalText decodes the BitCount value from a UTF-8-encoded text form. This
 // implements the TextUnmarshaler interface in the package encoding.
 func (bc *BitCount) UnmarshalText(text []byte) error {
-	var val BitCount
-	if _, err := fmt.Sscanf(string(text), "%s", &val); err != nil {
+	val, err := unmarshalParser.ParseBitCount(string(text))
+	if err != nil {
 		return err
 	}
-	AtomicStoreBitCount(bc, val)
+	AtomicStoreBitCount(bc, BitCount(val))
 	return nil
 }
 
@@ -187,6 +392,15 @@ func (bc *BitCount) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML decodes the BitCount value from a YAML field.
 func (bc *BitCount) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if unmarshal(&s) == nil {
+		// checked first, since the yaml package silently wraps around
+		// numbers that are too large to be decoded into uint64
+		if err := checkUintExpr(s); err != nil {
+			return rangeError("BitCount", s, false)
+		}
+	}
+
 	var u64 uint64
 	if unmarshal(&u64) == nil {
 		AtomicStoreBitCount(bc, BitCount(u64))
@@ -194,18 +408,17 @@ func (bc *BitCount) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return nil
 	}
 
-	var s string
 	if unmarshal(&s) == nil {
-		v, err := ParseBitCount(s)
+		v, err := unmarshalParser.ParseBitCount(s)
 		if err != nil {
-			return fmt.Errorf("%q: %w: %v", s, ErrMalformedRepresentation, err)
+			return err
 		}
 		AtomicStoreBitCount(bc, v)
 
 		return nil
 	}
 
-	return fmt.Errorf("%w: unexpected type", ErrMalformedRepresentation)
+	return typeError("BitCount", yamlInput(unmarshal))
 }
 
 // IsZero returns whether the BitCount value is zero.
@@ -231,25 +444,26 @@ func (bc *BitCount) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
+	if err := checkUintExpr(string(b)); err != nil {
+		return rangeError("BitCount", string(b), false)
+	}
+
 	var s string
 	if json.Unmarshal(b, &s) == nil {
-		v, err := ParseBitCount(s)
+		v, err := unmarshalParser.ParseBitCount(s)
 		if err != nil {
-			return fmt.Errorf("%q: %w: %v", s, ErrMalformedRepresentation, err)
+			return err
 		}
 		AtomicStoreBitCount(bc, v)
 
 		return nil
 	}
 
-	return fmt.Errorf("%w: unexpected type", ErrMalformedRepresentation)
+	return typeError("BitCount", string(b))
 }
 
 //
-const (
-	unitBitFull = "bit"
-	unitBitAbbr = unitBitFull
-)
+var unitBit = &unitName{abbr: "bit", full: "bit"}
 
 // Format implements the Formatter interface in the package fmt to format
 // BitCount values. This gives the ability to format BitCount values in
@@ -257,10 +471,12 @@ const (
 // package fmt; fmt.Printf, fmt.Fprintf, fmt.Sprintf, fmt.Errorf, and functions
 // derived from them.
 //
-// For BitCount type, two custom 'verbs' are implemented:
+// For BitCount type, four custom 'verbs' are implemented:
 //
 // 	%s	human-readable format with SI prefix
 // 	%S	human-readable format with binary prefix
+// 	%h	human-readable format with SI prefix and significant digits
+// 	%H	human-readable format with binary prefix and significant digits
 //
 // Width and precision can be specified to both %s and %S:
 //
@@ -273,6 +489,17 @@ const (
 // Regardless of the precision specified, while the unit is bit,
 // no decimal parts are printed.
 //
+// With a precision, the value is formatted exactly with integer arithmetic, and
+// the last digit is rounded half to even. The prefix is selected after
+// rounding, so "%.1s" prints 999960 as "1.0Mbit", not as "1000.0kbit". With the
+// default precision, the shortest representation of the value as float64 is
+// printed, e.g. "941.900559425354" rather than all the 23 decimal places.
+//
+// The verbs %h and %H are the same as %s and %S, except that the precision is
+// the number of significant digits, which is 3 by default, like "ls -h"; e.g.
+// "1.23Gbit", "12.3Gbit" and "123Gbit". The prefix is selected after
+// rounding, so "%h" prints 999600 as "1.00Mbit", not as "1000kbit".
+//
 // The following flags are also available for both %s and %S:
 //
 // 	' '	(space) print a space between digits and unit; e.g. "12.3 kbit"
@@ -297,39 +524,18 @@ const (
 // See the package fmt documentation for details.
 func (bc BitCount) Format(s fmt.State, verb rune) {
 	switch verb {
-	case 's', 'S':
-		tFmt := "%"
-		if s.Flag(int('-')) {
-			tFmt += "-"
-		}
-		if s.Flag(int('0')) {
-			tFmt += "0"
-		}
-		if wid, ok := s.Width(); ok {
-			tFmt += strconv.FormatInt(int64(wid), 10)
-		}
-		tFmt += "s"
-		prec, ok := s.Precision()
-		if !ok {
-			prec = -1
-		}
-		full, space := s.Flag(int('#')), s.Flag(int(' '))
-		var pfx *prefix
-		switch verb {
-		case 's':
-			pfx = siPrefix
-		case 'S':
-			pfx = binPrefix
-		}
-		expr := pfx.formatUint(uint64(bc), prec, full, space, unitBitAbbr, unitBitFull)
-		fmt.Fprintf(s, tFmt, expr)
+	case 's', 'S', 'h', 'H':
+		f := verbFormatter(s, verb)
+		var buf [fmtBufSize]byte
+		writePadded(s, bc.AppendFormat(buf[:0], &f))
 
 	case 'v':
 		if s.Flag(int('#')) {
 			fmt.Fprint(s, bc.GoString())
 			break
 		}
-		fmt.Fprint(s, bc.String())
+		var buf [fmtBufSize]byte
+		_, _ = s.Write(bc.AppendFormat(buf[:0], nil))
 
 	case 'b', 'd', 'o', 'x', 'X':
 		tFmt := "%"
@@ -352,46 +558,23 @@ func (bc BitCount) Format(s fmt.State, verb rune) {
 	}
 }
 
-//
-type bitCountScanUnitEnt struct {
-	re  *regexp.Regexp
-	bcs uint64
-	bcb uint64
-}
-
-var (
-	bitCountScanTokenRe []*regexp.Regexp
-	bitCountScanUnitRe  []bitCountScanUnitEnt
-)
-
-//
-func init() {
-	ent := func(s string, bcs, bcb BitCount) bitCountScanUnitEnt {
-		return bitCountScanUnitEnt{
-			re:  regexp.MustCompile(`(?i)^` + s + `$`),
-			bcs: uint64(bcs),
-			bcb: uint64(bcb),
-		}
-	}
-	bitCountScanUnitRe = []bitCountScanUnitEnt{
-		ent("bits?", Bit, Bit),
-		ent("k(ilo)?bits?", Kilobit, Kibibit),
-		ent("m(ega)?bits?", Megabit, Mebibit),
-		ent("g(iga)?bits?", Gigabit, Gibibit),
-		ent("t(era)?bits?", Terabit, Tebibit),
-		ent("p(eta)?bits?", Petabit, Pebibit),
-		ent("e(xa)?bits?", Exabit, Exbibit),
-		ent("(ki|kibi)bits?", Kibibit, Kibibit),
-		ent("(mi|mebi)bits?", Mebibit, Mebibit),
-		ent("(gi|gibi)bits?", Gibibit, Gibibit),
-		ent("(ti|tebi)bits?", Tebibit, Tebibit),
-		ent("(pi|pebi)bits?", Pebibit, Pebibit),
-		ent("(ei|exbi)bits?", Exbibit, Exbibit),
-	}
-	bitCountScanTokenRe = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)^(([0-9]*)(\.[0-9]+)?)([a-z]*)$`), // 1:num, 2:int, 3:frac, 4:unit
-		regexp.MustCompile(`(?i)^([a-z]*)$`),                      // 1:unit
-	}
+// bitCountUnits is the table of the unit suffixes of BitCount, which are also
+// the first words of the long unit names of BitRate, e.g. "kilobits per
+// second".
+var bitCountUnits = []scanUnit{
+	{"bit", -1, false, false}, {"bits", -1, false, true},
+	{"kbit", 0, false, false}, {"kbits", 0, false, false}, {"kilobit", 0, false, true}, {"kilobits", 0, false, true},
+	{"mbit", 1, false, false}, {"mbits", 1, false, false}, {"megabit", 1, false, true}, {"megabits", 1, false, true},
+	{"gbit", 2, false, false}, {"gbits", 2, false, false}, {"gigabit", 2, false, true}, {"gigabits", 2, false, true},
+	{"tbit", 3, false, false}, {"tbits", 3, false, false}, {"terabit", 3, false, true}, {"terabits", 3, false, true},
+	{"pbit", 4, false, false}, {"pbits", 4, false, false}, {"petabit", 4, false, true}, {"petabits", 4, false, true},
+	{"ebit", 5, false, false}, {"ebits", 5, false, false}, {"exabit", 5, false, true}, {"exabits", 5, false, true},
+	{"kibit", 0, true, true}, {"kibits", 0, true, true}, {"kibibit", 0, true, true}, {"kibibits", 0, true, true},
+	{"mibit", 1, true, true}, {"mibits", 1, true, true}, {"mebibit", 1, true, true}, {"mebibits", 1, true, true},
+	{"gibit", 2, true, true}, {"gibits", 2, true, true}, {"gibibit", 2, true, true}, {"gibibits", 2, true, true},
+	{"tibit", 3, true, true}, {"tibits", 3, true, true}, {"tebibit", 3, true, true}, {"tebibits", 3, true, true},
+	{"pibit", 4, true, true}, {"pibits", 4, true, true}, {"pebibit", 4, true, true}, {"pebibits", 4, true, true},
+	{"eibit", 5, true, true}, {"eibits", 5, true, true}, {"exbibit", 5, true, true}, {"exbibits", 5, true, true},
 }
 
 // Scan implements the Scanner interface in the package fmt to scan BitCount
@@ -419,6 +602,14 @@ func init() {
 // They assume that if the token consists only of digits, it is the number of
 // bits.
 //
+// If the scanned value exceeds the range of BitCount, an error wrapping
+// ErrOutOfRange is returned instead of a wrapped around value.
+//
+// Decimal numbers with a unit suffix are converted exactly, without being
+// rounded to float64. If the result is not a whole number of bits, it is
+// rounded to the nearest integer, with halves rounded away from zero. Use
+// ParseBitCountRound for the other rounding modes.
+//
 // The following verbs are compatible with uint64 and scans integers without a
 // unit suffix. If it is clear that there is absolutely no unit suffix in the
 // input, the use of these is recommended:
@@ -430,7 +621,6 @@ func init() {
 //
 // See the package fmt documentation for details.
 func (bc *BitCount) Scan(state fmt.ScanState, verb rune) error {
-	// fmt.Printf("**scan[%c]**\n", verb)
 	switch verb {
 	case 'b', 'd', 'o', 'x', 'X':
 		tFmt := "%"
@@ -444,116 +634,12 @@ func (bc *BitCount) Scan(state fmt.ScanState, verb rune) error {
 		}
 
 	case 's', 'S', 'u', 'U':
-		token1Bytes, err := state.Token(true, nil)
-		switch {
-		case err != nil:
-			return fmt.Errorf("%%%c: %w", verb, err)
-		case len(token1Bytes) < 1:
-			return fmt.Errorf("%%%c: no input", verb)
-		}
-		token1Str := string(token1Bytes)
-		token1 := bitCountScanTokenRe[0].FindStringSubmatch(token1Str)
-		if token1 == nil {
-			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
-		}
-		// fmt.Printf("[SCAN] TOKEN1: %+v\n", token1)
-
-		numExpr, unitExpr := token1[1], token1[4]
-		isInt := 0 < len(token1[2]) && len(token1[3]) < 1
-
-		if len(numExpr) < 1 {
-			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
-		}
-
-		if unitExpr == "" { // no unit suffix within the first token
-			switch verb {
-			case 'u', 'U':
-				// does not read the second token, assumed to be bits
-				unitExpr = "bit"
-			case 's', 'S':
-				sp, n, err := state.ReadRune()
-				switch {
-				case err != nil:
-					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
-				case n != 1:
-					return fmt.Errorf("%%%c: no unit suffix", verb)
-				case sp != ' ':
-					return fmt.Errorf("%%%c: no space after digits: [%c]", verb, sp)
-				}
-				token2Bytes, err := state.Token(false, nil)
-				switch {
-				case err != nil:
-					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
-				case len(token2Bytes) < 1:
-					return fmt.Errorf("%%%c: no unit suffix", verb)
-				}
-				token2Str := string(token2Bytes)
-				token2 := bitCountScanTokenRe[1].FindStringSubmatch(token2Str)
-				if token2 == nil {
-					return fmt.Errorf("%%%c: invalid unit expr: %s", verb, token2Str)
-				}
-				// fmt.Printf("[SCAN] TOKEN2: %+v\n", token2)
-
-				unitExpr = token2[1]
-				if unitExpr == "" {
-					return fmt.Errorf("%%%c: no unit suffix", verb)
-				}
-			}
-		}
-
-		// fmt.Printf("[SCAN] LAST [%s] [%s]\n", numExpr, unitExpr)
-
-		ptr := (*uint64)(bc)
-
-		// unit is bit
-		if bitCountScanUnitRe[0].re.MatchString(unitExpr) {
-			if !isInt {
-				return fmt.Errorf("%%%c: non-integer bit count: %s", verb, numExpr)
-			}
-			numVal, err := strconv.ParseUint(numExpr, 10, 64)
-			if err != nil {
-				return fmt.Errorf("%%%c: invalid bit count: %s: %w", verb, numExpr, err)
-			}
-			*ptr = numVal
-			return nil
-		}
-
-		if isInt { // integer
-			numVal, err := strconv.ParseUint(numExpr, 10, 64)
-			if err != nil {
-				return fmt.Errorf("%%%c: invalid bit count: %s: %w", verb, numExpr, err)
-			}
-			for _, unit := range bitCountScanUnitRe {
-				if unit.re.MatchString(unitExpr) {
-					switch verb {
-					case 's', 'u':
-						*ptr = numVal * unit.bcs
-					case 'S', 'U':
-						*ptr = numVal * unit.bcb
-					}
-					return nil
-				}
-			}
-			return fmt.Errorf("%%%c: unknown unit: %s", verb, unitExpr)
-		}
-
-		// float
-		numVal, err := strconv.ParseFloat(numExpr, 64)
+		lx := lexer{state: state}
+		v, err := lx.scanUint(verb, RoundNearest, bitCountUnits, "bit count")
 		if err != nil {
-			return fmt.Errorf("%%%c: invalid bit count: %s: %w", verb, numExpr, err)
-		}
-		for _, unit := range bitCountScanUnitRe {
-			if unit.re.MatchString(unitExpr) {
-				switch verb {
-				case 's', 'u':
-					*ptr = uint64(math.Round(numVal * float64(unit.bcs)))
-				case 'S', 'U':
-					*ptr = uint64(math.Round(numVal * float64(unit.bcb)))
-				}
-				return nil
-			}
+			return err
 		}
-		return fmt.Errorf("%%%c: unknown unit: %s", verb, unitExpr)
+		*bc = BitCount(v)
 
 	default:
 		return fmt.Errorf("unknown verb for BitCount: %%%c", verb)
@@ -564,21 +650,36 @@ func (bc *BitCount) Scan(state fmt.ScanState, verb rune) error {
 // ParseBitCount converts a human-readable string representation into a BitCount
 // value. The human-readable string is a decimal number with a unit suffix. SI
 // and binary prefixes are correctly recognized.
+// If the value exceeds the range of BitCount, the returned error wraps
+// ErrOutOfRange.
 func ParseBitCount(s string) (BitCount, error) {
-	var v BitCount
-	if _, err := fmt.Sscanf(s, "%s", &v); err != nil {
-		return 0, fmt.Errorf("invalid bit count: %s: %w", s, err)
+	v, err := parseUint(s, "BitCount", 's', RoundNearest, bitCountUnits, "bit count")
+	if err != nil {
+		return 0, err
 	}
-	return v, nil
+	return BitCount(v), nil
 }
 
 // ParseBitCountBinary is the same as ParseBitCount except that it treats the SI
 // prefixes as binary prefixes. That is, it parses "100 kbit" as 100 Kibit
 // (=102400 bit).
 func ParseBitCountBinary(s string) (BitCount, error) {
-	var v BitCount
-	if _, err := fmt.Sscanf(s, "%S", &v); err != nil {
-		return 0, fmt.Errorf("invalid bit count: %s: %w", s, err)
+	v, err := parseUint(s, "BitCount", 'S', RoundNearest, bitCountUnits, "bit count")
+	if err != nil {
+		return 0, err
+	}
+	return BitCount(v), nil
+}
+
+// ParseBitCountRound is the same as ParseBitCount except that it uses the
+// specified rounding mode when the value is not a whole number of bits, e.g.
+// "0.3 kibibit". The conversion is exact; the decimal number is never
+// rounded before it is multiplied by the unit. With RoundExact, the returned
+// error wraps ErrFractional if the value is not a whole number of bits.
+func ParseBitCountRound(s string, mode RoundingMode) (BitCount, error) {
+	v, err := parseUint(s, "BitCount", 's', mode, bitCountUnits, "bit count")
+	if err != nil {
+		return 0, err
 	}
-	return v, nil
+	return BitCount(v), nil
 }
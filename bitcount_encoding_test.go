@@ -6,6 +6,7 @@ package infounit_test
 
 import (
 	"bytes"
+	"errors"
 	"encoding/hex"
 	"encoding/json"
 	"strings"
@@ -320,3 +321,51 @@ func TestBitCount_UnmarshalJSON(t *testing.T) {
 		}
 	}
 }
+
+//
+func TestBitCount_Unmarshal_outOfRange(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"18446744073709551616", "-1", "99999999999999999999999", "1e20", "-0.5"} {
+		var v infounit.BitCount
+		if err := json.Unmarshal([]byte(s), &v); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`JSON %s: ErrOutOfRange expected, got: %v, %v`, s, v, err)
+		}
+		if err := yaml.Unmarshal([]byte("- "+s), &[]infounit.BitCount{v}); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`YAML %s: ErrOutOfRange expected, got: %v`, s, err)
+		}
+	}
+	for _, s := range []string{"20 Ebit", "18446744073709551616 bit"} {
+		var v infounit.BitCount
+		if err := v.UnmarshalText([]byte(s)); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`Text %s: ErrOutOfRange expected, got: %v, %v`, s, v, err)
+		}
+		if err := json.Unmarshal([]byte(`"`+s+`"`), &v); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`JSON %q: ErrOutOfRange expected, got: %v, %v`, s, v, err)
+		}
+		if err := yaml.Unmarshal([]byte("- "+s), &[]infounit.BitCount{v}); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`YAML %q: ErrOutOfRange expected, got: %v`, s, err)
+		}
+	}
+}
+
+//
+func TestBitCount_AppendText(t *testing.T) {
+	t.Parallel()
+
+	v := infounit.BitCount(123456789)
+	txt, err := v.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ex := string(txt), "123456789 bit"; s != ex {
+		t.Errorf(`MarshalText: want: "%s", got: "%s"`, ex, s)
+	}
+	app, err := v.AppendText([]byte("x="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ex := string(app), "x="+string(txt); s != ex {
+		t.Errorf(`AppendText: want: "%s", got: "%s"`, ex, s)
+	}
+}
@@ -131,3 +131,104 @@ func TestBitCount_Format_2(t *testing.T) {
 		}
 	}
 }
+
+//
+func TestBitCount_Format_exact(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v uint64
+		f string
+		s string
+	}{
+		{18446744073709551615, "%s", "18.446744073709553Ebit"},
+		{18446744073709551615, "%.18s", "18.446744073709551615Ebit"},
+		{18446744073709551615, "%.20s", "18.44674407370955161500Ebit"},
+		{18446744073709551615, "%.17s", "18.44674407370955162Ebit"},
+		{18446744073709551615, "%.3S", "16.000Eibit"},
+		{18446744073709551615, "%.18S", "15.999999999999999999Eibit"},
+		{18446744073709551615, "%.19S", "15.9999999999999999991Eibit"},
+		{9007199254740993, "%s", "9.007199254740993Pbit"},
+		{9007199254740993, "%.15s", "9.007199254740993Pbit"},
+		{9007199254740993, "%.14s", "9.00719925474099Pbit"},
+		{1152921504606846977, "%S", "1Eibit"},
+		{1025, "%S", "1.0009765625Kibit"},
+		{1152921504606846977, "%.60S", "1.000000000000000000867361737988403547205962240695953369140625Eibit"},
+		{1500, "%.0s", "2kbit"},
+		{2500, "%.0s", "2kbit"},
+		{2501, "%.0s", "3kbit"},
+		{1005, "%.2s", "1.00kbit"},
+		{1015, "%.2s", "1.02kbit"},
+		{1536, "%.0S", "2Kibit"},
+		{2560, "%.0S", "2Kibit"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.BitCount(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %d: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestBitCount_Format_carry(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v uint64
+		f string
+		s string
+	}{
+		{999, "%.1s", "999bit"},
+		{1023, "%.1S", "1023bit"},
+		{999949, "%.1s", "999.9kbit"},
+		{999950, "%.1s", "1.0Mbit"},
+		{999960, "%.1s", "1.0Mbit"},
+		{999960, "%# .1s", "1.0 megabits"},
+		{999960, "%.1S", "976.5Kibit"},
+		{999499, "%.0s", "999kbit"},
+		{999500, "%.0s", "1Mbit"},
+		{1048535, "%.1S", "1.0Mibit"},
+		{1048575, "%.2S", "1.00Mibit"},
+		{1048575, "%.3S", "1023.999Kibit"},
+		{999999999999999999, "%.1s", "1.0Ebit"},
+		{1152921504606846975, "%.1S", "1.0Eibit"},
+		{18446744073709551615, "%.0S", "16Eibit"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.BitCount(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %d: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func BenchmarkBitCount_AppendFormat(b *testing.B) {
+	v := infounit.BitCount(123456789)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = v.AppendFormat(buf[:0], nil)
+	}
+}
+
+//
+func BenchmarkBitCount_String(b *testing.B) {
+	v := infounit.BitCount(123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = v.String()
+	}
+}
+
+//
+func BenchmarkBitCount_Sprintf(b *testing.B) {
+	v := infounit.BitCount(123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%# .2S", v)
+	}
+}
@@ -109,7 +109,7 @@ func TestBitCount_Scan_1(t *testing.T) {
 		{"611 petabit", "%s", infounit.Petabit * 611, ""},
 		{"611 petaBITs", "%S", infounit.Pebibit * 611, ""},
 		{"18446 Pbit", "%s", infounit.Petabit * 18446, ""},
-		{"18.446 Ebit", "%s", infounit.BitCount(float64(infounit.Exabit) * 18.446), ""},
+		{"18.446 Ebit", "%s", infounit.Petabit * 18446, ""},
 
 		{"11Ebit", "%s", infounit.Exabit * 11, ""},
 		{"11ebit", "%S", infounit.Exbibit * 11, ""},
@@ -195,6 +195,15 @@ func TestBitCount_Scan_1(t *testing.T) {
 		{"1.21 jigowatts", "%u", 0, "%u: non-integer bit count: 1.21"}, // %u does not read "jigowatts"
 		{"1.21 jigowatts", "%U", 0, "%U: non-integer bit count: 1.21"}, // %U does not read "jigowatts"
 
+		{"18446744073709551616 bit", "%s", 0, "%s: invalid bit count: 18446744073709551616: out of range"},
+		{"18446744073709551616", "%u", 0, "%u: invalid bit count: 18446744073709551616: out of range"},
+		{"20 Ebit", "%s", 0, "%s: 20 Ebit: out of range"},
+		{"16 Eibit", "%s", 0, "%s: 16 Eibit: out of range"},
+		{"16Ebit", "%U", 0, "%U: 16Ebit: out of range"},
+		{"99999999999 Tbit", "%s", 0, "%s: 99999999999 Tbit: out of range"},
+		{"18.5 Ebit", "%s", 0, "%s: 18.5 Ebit: out of range"},
+		{"16.0001 Eibit", "%s", 0, "%s: 16.0001 Eibit: out of range"},
+
 		{"0", "%d", infounit.Bit * 0, ""},
 		{"1", "%d", infounit.Bit * 1, ""},
 		{"18446744073709551615", "%d", infounit.Bit * 18446744073709551615, ""},
@@ -5,6 +5,7 @@
 package infounit_test
 
 import (
+	"errors"
 	"math"
 	"testing"
 	"time"
@@ -213,3 +214,328 @@ func TestParseBitCountBinary(t *testing.T) {
 		}
 	}
 }
+
+//
+func TestParseBitCount_outOfRange(t *testing.T) {
+	t.Parallel()
+
+	tc := []string{
+		"18446744073709551616 bit",
+		"20 Ebit",
+		"16 Eibit",
+		"99999999999 Tbit",
+		"18.5 Ebit",
+		"100000000000000 megabits",
+	}
+
+	for _, s := range tc {
+		v, err := infounit.ParseBitCount(s)
+		if !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`%s: ErrOutOfRange expected, got: %v, %v`, s, v, err)
+		}
+	}
+}
+
+//
+func TestParseBitCountRound(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s    string
+		mode infounit.RoundingMode
+		v    infounit.BitCount
+		err  error
+	}{
+		{"1.1 Ebit", infounit.RoundNearest, 1100000000000000000, nil},
+		{"1.1 Ebit", infounit.RoundExact, 1100000000000000000, nil},
+		{"12.000 kbit", infounit.RoundExact, 12000, nil},
+		{"0.3 Gibit", infounit.RoundNearest, 322122547, nil},
+		{"0.3 Gibit", infounit.RoundFloor, 322122547, nil},
+		{"0.3 Gibit", infounit.RoundCeil, 322122548, nil},
+		{"0.3 Gibit", infounit.RoundExact, 0, infounit.ErrFractional},
+		{"1.0005 kbit", infounit.RoundNearest, 1001, nil},
+		{"1.0005 kbit", infounit.RoundFloor, 1000, nil},
+		{"1.0005 kbit", infounit.RoundCeil, 1001, nil},
+		{"1.00049 kbit", infounit.RoundNearest, 1000, nil},
+		{"15.999999999999999999 Eibit", infounit.RoundNearest, 18446744073709551615, nil},
+		{"15.999999999999999999 Eibit", infounit.RoundFloor, 18446744073709551614, nil},
+		{"15.999999999999999999 Eibit", infounit.RoundCeil, 18446744073709551615, nil},
+		{"18.446744073709551615 Ebit", infounit.RoundExact, 18446744073709551615, nil},
+		{"18.446744073709551616 Ebit", infounit.RoundFloor, 0, infounit.ErrOutOfRange},
+		{"0.0000000000000000000001 Ebit", infounit.RoundNearest, 0, nil},
+		{"0.0000000000000000000001 Ebit", infounit.RoundFloor, 0, nil},
+		{"0.0000000000000000000001 Ebit", infounit.RoundCeil, 1, nil},
+		{"0.0000000000000000000001 Ebit", infounit.RoundExact, 0, infounit.ErrFractional},
+		{"0.0000000000000000005 Ebit", infounit.RoundNearest, 1, nil},
+		{"0.00000000000000000049999999 Ebit", infounit.RoundNearest, 0, nil},
+		{"16 Eibit", infounit.RoundFloor, 0, infounit.ErrOutOfRange},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.ParseBitCountRound(c.s, c.mode)
+		switch {
+		case c.err != nil && !errors.Is(err, c.err):
+			t.Errorf(`%s, %v: want(err): %v, got: %v, %v`, c.s, c.mode, c.err, v, err)
+		case c.err == nil && err != nil:
+			t.Errorf(`%s, %v: unexpected error: %v`, c.s, c.mode, err)
+		case v != c.v:
+			t.Errorf(`%s, %v: want: %d, got: %d`, c.s, c.mode, c.v, v)
+		}
+	}
+}
+
+//
+func TestBitCount_Add(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, y uint64
+		v    uint64
+		sat  uint64
+		e    bool
+	}{
+		{0, 0, 0, 0, false},
+		{1000, 24, 1024, 1024, false},
+		{math.MaxUint64 - 1, 1, math.MaxUint64, math.MaxUint64, false},
+		{math.MaxUint64, 1, 0, math.MaxUint64, true},
+		{1 << 63, 1 << 63, 0, math.MaxUint64, true},
+	}
+
+	for _, c := range tc {
+		x, y := infounit.BitCount(c.x), infounit.BitCount(c.y)
+		v, err := x.Add(y)
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d + %d: ErrOutOfRange expected, got: %d, %v`, c.x, c.y, v, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d + %d: unexpected error: %v`, c.x, c.y, err)
+		case !c.e && v != infounit.BitCount(c.v):
+			t.Errorf(`%d + %d: want: %d, got: %d`, c.x, c.y, c.v, v)
+		}
+		if sat := x.SaturatingAdd(y); sat != infounit.BitCount(c.sat) {
+			t.Errorf(`%d + %d: saturating: want: %d, got: %d`, c.x, c.y, c.sat, sat)
+		}
+	}
+}
+
+//
+func TestBitCount_Sub(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, y uint64
+		v    uint64
+		e    bool
+	}{
+		{0, 0, 0, false},
+		{1024, 24, 1000, false},
+		{1024, 1024, 0, false},
+		{1024, 1025, 0, true},
+		{0, math.MaxUint64, 0, true},
+		{math.MaxUint64, math.MaxUint64 - 1, 1, false},
+	}
+
+	for _, c := range tc {
+		x, y := infounit.BitCount(c.x), infounit.BitCount(c.y)
+		v, err := x.Sub(y)
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d - %d: ErrOutOfRange expected, got: %d, %v`, c.x, c.y, v, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d - %d: unexpected error: %v`, c.x, c.y, err)
+		case !c.e && v != infounit.BitCount(c.v):
+			t.Errorf(`%d - %d: want: %d, got: %d`, c.x, c.y, c.v, v)
+		}
+		if sat := x.SaturatingSub(y); sat != infounit.BitCount(c.v) {
+			t.Errorf(`%d - %d: saturating: want: %d, got: %d`, c.x, c.y, c.v, sat)
+		}
+	}
+}
+
+//
+func TestBitCount_Mul(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, n uint64
+		v    uint64
+		e    bool
+	}{
+		{0, math.MaxUint64, 0, false},
+		{1000, 0, 0, false},
+		{uint64(infounit.Gigabit), 18, uint64(infounit.Gigabit) * 18, false},
+		{uint64(infounit.Gigabit), 18446744073, uint64(infounit.Gigabit) * 18446744073, false},
+		{uint64(infounit.Gigabit), 18446744074, 0, true},
+		{1 << 32, 1 << 32, 0, true},
+		{math.MaxUint64, 1, math.MaxUint64, false},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.BitCount(c.x).Mul(c.n)
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d * %d: ErrOutOfRange expected, got: %d, %v`, c.x, c.n, v, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d * %d: unexpected error: %v`, c.x, c.n, err)
+		case !c.e && v != infounit.BitCount(c.v):
+			t.Errorf(`%d * %d: want: %d, got: %d`, c.x, c.n, c.v, v)
+		}
+	}
+}
+
+//
+func TestBitCount_MulFloat(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x uint64
+		f float64
+		v uint64
+		e bool
+	}{
+		{1000, 0, 0, false},
+		{1000, 1.5, 1500, false},
+		{1000, 0.0005, 1, false},
+		{1000, 0.0004, 0, false},
+		{uint64(infounit.Gigabit), 0.25, uint64(infounit.Gigabit) / 4, false},
+		{1000, -1, 0, true},
+		{1000, math.NaN(), 0, true},
+		{1000, math.Inf(+1), 0, true},
+		{1 << 63, 2, 0, true},
+		{1 << 62, 2, 1 << 63, false},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.BitCount(c.x).MulFloat(c.f)
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d * %f: ErrOutOfRange expected, got: %d, %v`, c.x, c.f, v, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d * %f: unexpected error: %v`, c.x, c.f, err)
+		case !c.e && v != infounit.BitCount(c.v):
+			t.Errorf(`%d * %f: want: %d, got: %d`, c.x, c.f, c.v, v)
+		}
+	}
+}
+
+//
+func TestBitCount_Div(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, n uint64
+		v    uint64
+	}{
+		{0, 1, 0},
+		{1000, 3, 333},
+		{1024, 1024, 1},
+		{math.MaxUint64, 2, math.MaxUint64 / 2},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.BitCount(c.x).Div(c.n)
+		switch {
+		case err != nil:
+			t.Errorf(`%d / %d: unexpected error: %v`, c.x, c.n, err)
+		case v != infounit.BitCount(c.v):
+			t.Errorf(`%d / %d: want: %d, got: %d`, c.x, c.n, c.v, v)
+		}
+	}
+
+	if v, err := infounit.BitCount(1000).Div(0); !errors.Is(err, infounit.ErrDivZero) {
+		t.Errorf(`1000 / 0: ErrDivZero expected, got: %d, %v`, v, err)
+	}
+}
+
+//
+func TestBitCount_Round(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, m  uint64
+		trunc uint64
+		round uint64
+		up    uint64
+		upErr bool
+	}{
+		{0, 4096, 0, 0, 0, false},
+		{1, 4096, 0, 0, 4096, false},
+		{2047, 4096, 0, 0, 4096, false},
+		{2048, 4096, 0, 4096, 4096, false},
+		{4096, 4096, 4096, 4096, 4096, false},
+		{4097, 4096, 4096, 4096, 8192, false},
+		{1234567, 1000, 1234000, 1235000, 1235000, false},
+		{1234499, 1000, 1234000, 1234000, 1235000, false},
+		{1234567, 0, 1234567, 1234567, 1234567, false},
+		{1234567, 1, 1234567, 1234567, 1234567, false},
+		{math.MaxUint64, 1 << 20, math.MaxUint64 &^ (1<<20 - 1), math.MaxUint64, 0, true},
+		{math.MaxUint64 &^ (1<<20 - 1), 1 << 20, math.MaxUint64 &^ (1<<20 - 1), math.MaxUint64 &^ (1<<20 - 1), math.MaxUint64 &^ (1<<20 - 1), false},
+		{math.MaxUint64, math.MaxUint64, math.MaxUint64, math.MaxUint64, math.MaxUint64, false},
+		{math.MaxUint64 - 1, math.MaxUint64, 0, math.MaxUint64, math.MaxUint64, false},
+	}
+
+	for _, c := range tc {
+		x, m := infounit.BitCount(c.x), infounit.BitCount(c.m)
+		if v := x.Truncate(m); v != infounit.BitCount(c.trunc) {
+			t.Errorf(`%d.Truncate(%d): want: %d, got: %d`, c.x, c.m, c.trunc, v)
+		}
+		if v := x.AlignDown(m); v != infounit.BitCount(c.trunc) {
+			t.Errorf(`%d.AlignDown(%d): want: %d, got: %d`, c.x, c.m, c.trunc, v)
+		}
+		if v := x.Round(m); v != infounit.BitCount(c.round) {
+			t.Errorf(`%d.Round(%d): want: %d, got: %d`, c.x, c.m, c.round, v)
+		}
+		v, err := x.AlignUp(m)
+		switch {
+		case c.upErr && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d.AlignUp(%d): ErrOutOfRange expected, got: %d, %v`, c.x, c.m, v, err)
+		case !c.upErr && err != nil:
+			t.Errorf(`%d.AlignUp(%d): unexpected error: %v`, c.x, c.m, err)
+		case !c.upErr && v != infounit.BitCount(c.up):
+			t.Errorf(`%d.AlignUp(%d): want: %d, got: %d`, c.x, c.m, c.up, v)
+		}
+		if a, ex := x.IsAligned(m), c.x == c.trunc; a != ex {
+			t.Errorf(`%d.IsAligned(%d): want: %t, got: %t`, c.x, c.m, ex, a)
+		}
+	}
+}
+
+//
+func TestBitCount_AlignUpToByte(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x    uint64
+		up   uint64
+		down uint64
+		e    bool
+	}{
+		{0, 0, 0, false},
+		{1, 8, 0, false},
+		{7, 8, 0, false},
+		{8, 8, 8, false},
+		{9, 16, 8, false},
+		{12345, 12352, 12344, false},
+		{math.MaxUint64 - 7, math.MaxUint64 - 7, math.MaxUint64 - 7, false},
+		{math.MaxUint64 - 6, 0, math.MaxUint64 - 7, true},
+	}
+
+	for _, c := range tc {
+		x := infounit.BitCount(c.x)
+		v, err := x.AlignUpToByte()
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d: ErrOutOfRange expected, got: %d, %v`, c.x, v, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d: unexpected error: %v`, c.x, err)
+		case !c.e && v != infounit.BitCount(c.up):
+			t.Errorf(`%d: want: %d, got: %d`, c.x, c.up, v)
+		}
+		if v := x.AlignDownToByte(); v != infounit.BitCount(c.down) {
+			t.Errorf(`%d: down: want: %d, got: %d`, c.x, c.down, v)
+		}
+		if a, ex := x.IsByteAligned(), c.x == c.down; a != ex {
+			t.Errorf(`%d: aligned: want: %t, got: %t`, c.x, ex, a)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+)
+
+// BitDelta represents a signed difference of bit counts, such as the change
+// in the amount of transferred data measured in bits. BitDelta values are
+// formatted and scanned in the same way as BitCount values, with the sign;
+// e.g. "+1.5 Gbit", "-200 Mibit". See the documentation of Format method
+// bellow for details.
+//
+// The BitCount constants can be used to declare BitDelta values by
+// converting them:
+//
+// 	fmt.Print(infounit.BitDelta(infounit.Mebibit) * -200)
+//
+// Range: -9223372036854775808 bits through 9223372036854775807 bits (=1 EiB)
+type BitDelta int64
+
+// String returns the human-readable string representing the bit delta using
+// SI prefix. This implements the Stringer interface in the package fmt.
+func (bd BitDelta) String() string {
+	var buf [fmtBufSize]byte
+	return string(bd.AppendFormat(buf[:0], nil))
+}
+
+// AppendFormat appends the human-readable string representation of the
+// BitDelta value formatted by f to dst and returns the extended buffer. If f is
+// nil, the default format "% .1s", the same as String, is used. Unlike String
+// and the Printf family functions, this does not allocate as long as dst has
+// enough capacity.
+func (bd BitDelta) AppendFormat(dst []byte, f *Formatter) []byte {
+	return f.orDefault().appendInt(dst, int64(bd), unitBit)
+}
+
+// GoString returns a string representation of the BitDelta value in Go syntax
+// format. This implements the GoStringer interface in the package fmt.
+func (bd BitDelta) GoString() string {
+	return fmt.Sprintf("BitDelta(%d)", int64(bd))
+}
+
+// Abs returns the absolute value of the bit delta as a BitCount. Since the
+// range of BitCount is wider, this never overflows.
+func (bd BitDelta) Abs() BitCount {
+	return BitCount(absInt64(int64(bd)))
+}
+
+// ByteDelta returns the value converted to the number of bytes and the
+// number of remaining bits. Both are truncated toward zero, so the remaining
+// bits have the same sign as the value.
+func (bd BitDelta) ByteDelta() (ByteDelta, BitDelta) {
+	return ByteDelta(bd / 8), bd % 8
+}
+
+// Convert converts the bit delta to a float value in the specified unit. If
+// the goal is to output or to create a string in a human-readable format,
+// fmt.Printf or fmt.Sprintf is preferred.
+func (bd BitDelta) Convert(unit BitCount) float64 {
+	return float64(bd) / float64(unit)
+}
+
+// ConvertRound is the same as Convert except that it returns a value rounded to
+// the specified precision. If the goal is to output or to create a string in a
+// human-readable format, fmt.Printf or fmt.Sprintf is preferred.
+func (bd BitDelta) ConvertRound(unit BitCount, precision int) float64 {
+	p := math.Pow(10, float64(precision))
+	v := math.Round(p*float64(bd)/float64(unit)) / p
+	return v
+}
+
+// AtomicAddBitDelta atomically adds delta to *addr and returns the new value.
+// A wrapper function for the package sync/atomic.
+func AtomicAddBitDelta(addr *BitDelta, delta BitDelta) BitDelta {
+	return BitDelta(atomic.AddInt64((*int64)(addr), int64(delta)))
+}
+
+// AtomicLoadBitDelta atomically loads *addr. A wrapper function for the
+// package sync/atomic.
+func AtomicLoadBitDelta(addr *BitDelta) BitDelta {
+	return BitDelta(atomic.LoadInt64((*int64)(addr)))
+}
+
+// AtomicStoreBitDelta atomically stores val into *addr. A wrapper function for
+// the package sync/atomic.
+func AtomicStoreBitDelta(addr *BitDelta, val BitDelta) {
+	atomic.StoreInt64((*int64)(addr), int64(val))
+}
+
+// AtomicSwapBitDelta atomically stores val into *addr and returns the previous
+// *addr value. A wrapper function for the package sync/atomic.
+func AtomicSwapBitDelta(addr *BitDelta, val BitDelta) BitDelta {
+	return BitDelta(atomic.SwapInt64((*int64)(addr), int64(val)))
+}
+
+// MarshalBinary encodes the BitDelta value into a binary form and returns the
+// result. The value is encoded as a big-endian two's complement integer. This
+// implements the BinaryMarshaler interface in the package encoding.
+func (bd *BitDelta) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(AtomicLoadBitDelta(bd)))
+	return b, nil
+}
+
+// UnmarshalBinary decodes the BitDelta value from a binary form. This
+// implements the BinaryUnmarshaler interface in the package encoding.
+func (bd *BitDelta) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("invalid len: %d", len(data))
+	}
+	AtomicStoreBitDelta(bd, BitDelta(binary.BigEndian.Uint64(data)))
+	return nil
+}
+
+// MarshalText encodes the BitDelta value into a UTF-8-encoded text and returns
+// the result. This implements the TextMarshaler interface in the
+// package encoding.
+func (bd *BitDelta) MarshalText() ([]byte, error) {
+	return bd.AppendText(make([]byte, 0, 32))
+}
+
+// AppendText appends the UTF-8-encoded text form of the BitDelta value, the same
+// as MarshalText, to dst and returns the extended buffer. This implements the
+// TextAppender interface in the package encoding.
+func (bd *BitDelta) AppendText(dst []byte) ([]byte, error) {
+	dst = strconv.AppendInt(dst, int64(AtomicLoadBitDelta(bd)), 10)
+	return append(dst, " bit"...), nil
+}
+
+// UnmarshalText decodes the BitDelta value from a UTF-8-encoded text form.
+// This implements the TextUnmarshaler interface in the package encoding.
+func (bd *BitDelta) UnmarshalText(text []byte) error {
+	val, err := unmarshalParser.ParseBitDelta(string(text))
+	if err != nil {
+		return err
+	}
+	AtomicStoreBitDelta(bd, BitDelta(val))
+	return nil
+}
+
+// MarshalYAML encodes the BitDelta value into an int64 for a YAML field.
+func (bd *BitDelta) MarshalYAML() (interface{}, error) {
+	return int64(AtomicLoadBitDelta(bd)), nil
+}
+
+// UnmarshalYAML decodes the BitDelta value from a YAML field.
+func (bd *BitDelta) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if unmarshal(&s) == nil {
+		// checked first, since the yaml package silently wraps around
+		// numbers that are too large to be decoded
+		if err := checkIntExpr(s); err != nil {
+			return rangeError("BitDelta", s, true)
+		}
+	}
+
+	var i64 int64
+	if unmarshal(&i64) == nil {
+		AtomicStoreBitDelta(bd, BitDelta(i64))
+
+		return nil
+	}
+
+	if unmarshal(&s) == nil {
+		v, err := unmarshalParser.ParseBitDelta(s)
+		if err != nil {
+			return err
+		}
+		AtomicStoreBitDelta(bd, v)
+
+		return nil
+	}
+
+	return typeError("BitDelta", yamlInput(unmarshal))
+}
+
+// IsZero returns whether the BitDelta value is zero.
+func (bd BitDelta) IsZero() bool {
+	return bd == 0
+}
+
+// MarshalJSON encodes the BitDelta value into a number for a JSON field.
+func (bd *BitDelta) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(AtomicLoadBitDelta(bd)))
+}
+
+// UnmarshalJSON decodes the BitDelta value from a JSON field.
+func (bd *BitDelta) UnmarshalJSON(b []byte) error {
+	if string(b) == jsonNULL {
+		return nil
+	}
+
+	var i64 int64
+	if json.Unmarshal(b, &i64) == nil {
+		AtomicStoreBitDelta(bd, BitDelta(i64))
+
+		return nil
+	}
+
+	if err := checkIntExpr(string(b)); err != nil {
+		return rangeError("BitDelta", string(b), true)
+	}
+
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		v, err := unmarshalParser.ParseBitDelta(s)
+		if err != nil {
+			return err
+		}
+		AtomicStoreBitDelta(bd, v)
+
+		return nil
+	}
+
+	return typeError("BitDelta", string(b))
+}
+
+// Format implements the Formatter interface in the package fmt to format
+// BitDelta values. The verbs and flags are the same as BitCount, except that
+// the sign is printed for negative values:
+//
+// 	%s	human-readable format with SI prefix; e.g. "-1.5Gbit"
+// 	%S	human-readable format with binary prefix; e.g. "-200Mibit"
+//
+// The value is scaled by its magnitude, and formatted exactly in the same way
+// as BitCount. In addition to the flags of BitCount, the following flag is
+// available for both %s and %S:
+//
+// 	+	always print a sign; e.g. "+1.5 Gbit"
+//
+// With the '0' flag, the leading zeros are inserted after the sign.
+//
+// The verbs %h and %H for the significant digits are also available, as with
+// BitCount.
+//
+// %v prints in the default format:
+//
+// 	%v	default format, same as "% .1s"
+// 	%+v	default format with sign, same as "%+ .1s"
+// 	%#v	GoString(); e.g. "BitDelta(-1024)"
+//
+// The following int64 compatible verbs are also supported.
+// They print the integer values always in bit:
+//
+// 	%b	base 2
+// 	%d	base 10
+// 	%o	base 8
+// 	%x	base 16, with lower-case letters for a-f
+// 	%X	base 16, with upper-case letters for A-F
+//
+// See the package fmt documentation for details.
+func (bd BitDelta) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's', 'S', 'h', 'H':
+		f := verbFormatter(s, verb)
+		f.PlusSign = s.Flag(int('+'))
+		var buf [fmtBufSize]byte
+		writePadded(s, bd.AppendFormat(buf[:0], &f))
+
+	case 'v':
+		if s.Flag(int('#')) {
+			fmt.Fprint(s, bd.GoString())
+			break
+		}
+		f := defaultFormatter
+		f.PlusSign = s.Flag(int('+'))
+		var buf [fmtBufSize]byte
+		_, _ = s.Write(bd.AppendFormat(buf[:0], &f))
+
+	case 'b', 'd', 'o', 'x', 'X':
+		tFmt := "%"
+		for _, flag := range " #+-0" {
+			if s.Flag(int(flag)) {
+				tFmt += string(flag)
+			}
+		}
+		if wid, ok := s.Width(); ok {
+			tFmt += strconv.FormatInt(int64(wid), 10)
+		}
+		tFmt += string(verb)
+		fmt.Fprintf(s, tFmt, int64(bd))
+
+	default:
+		fmt.Fprintf(s, "%%!%c(BitDelta=%d)", verb, int64(bd))
+	}
+}
+
+// Scan implements the Scanner interface in the package fmt to scan BitDelta
+// values from strings. The verbs are the same as BitCount, except that an
+// optional sign, '+' or '-', is accepted before the digits; e.g. "+1.5 Gbit",
+// "-200 Mibit". No space is allowed between the sign and the digits.
+//
+// If the scanned value exceeds the range of BitDelta, an error wrapping
+// ErrOutOfRange is returned.
+//
+// The following verbs are compatible with int64 and scans integers without a
+// unit suffix:
+//
+// 	%b	base 2
+// 	%o	base 8
+// 	%d	base 10
+// 	%x, %X	base 16
+//
+// See the package fmt documentation for details.
+func (bd *BitDelta) Scan(state fmt.ScanState, verb rune) error {
+	switch verb {
+	case 'b', 'd', 'o', 'x', 'X':
+		tFmt := "%"
+		if wid, ok := state.Width(); ok {
+			tFmt += strconv.FormatInt(int64(wid), 10)
+		}
+		tFmt += string(verb)
+		ptr := (*int64)(bd)
+		if _, err := fmt.Fscanf(state, tFmt, ptr); err != nil {
+			return fmt.Errorf("%%%c: no input: %w", verb, err)
+		}
+
+	case 's', 'S', 'u', 'U':
+		lx := lexer{state: state}
+		v, err := lx.scanInt(verb, RoundNearest, bitCountUnits, "bit count")
+		if err != nil {
+			return err
+		}
+		*bd = BitDelta(v)
+
+	default:
+		return fmt.Errorf("unknown verb for BitDelta: %%%c", verb)
+	}
+	return nil
+}
+
+// ParseBitDelta converts a human-readable string representation into a
+// BitDelta value. The human-readable string is a decimal number with an
+// optional sign and a unit suffix; e.g. "+1.5 Gbit", "-200 Mibit". SI and
+// binary prefixes are correctly recognized. If the value exceeds the range of
+// BitDelta, the returned error wraps ErrOutOfRange.
+func ParseBitDelta(s string) (BitDelta, error) {
+	v, err := parseInt(s, "BitDelta", 's', RoundNearest, bitCountUnits, "bit count")
+	if err != nil {
+		return 0, err
+	}
+	return BitDelta(v), nil
+}
+
+// ParseBitDeltaBinary is the same as ParseBitDelta except that it treats the
+// SI prefixes as binary prefixes. That is, it parses "-100 kbit" as -100 Kibit
+// (=-102400 bit).
+func ParseBitDeltaBinary(s string) (BitDelta, error) {
+	v, err := parseInt(s, "BitDelta", 'S', RoundNearest, bitCountUnits, "bit count")
+	if err != nil {
+		return 0, err
+	}
+	return BitDelta(v), nil
+}
+
+// ParseBitDeltaRound is the same as ParseBitDelta except that it uses the
+// specified rounding mode when the value is not a whole number of bits. The
+// rounding mode is applied to the magnitude, so RoundFloor rounds toward zero
+// and RoundCeil rounds away from zero for both positive and negative values.
+func ParseBitDeltaRound(s string, mode RoundingMode) (BitDelta, error) {
+	v, err := parseInt(s, "BitDelta", 's', mode, bitCountUnits, "bit count")
+	if err != nil {
+		return 0, err
+	}
+	return BitDelta(v), nil
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+	"gopkg.in/yaml.v2"
+)
+
+//
+func TestBitDelta_Binary(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v   int64
+		hex string
+	}{
+		{0, "0000000000000000"},
+		{1, "0000000000000001"},
+		{-1, "FFFFFFFFFFFFFFFF"},
+		{987654321, "000000003ADE68B1"},
+		{-9223372036854775808, "8000000000000000"},
+		{9223372036854775807, "7FFFFFFFFFFFFFFF"},
+	}
+
+	for _, c := range tc {
+		bd := infounit.BitDelta(c.v)
+		bin, err := bd.MarshalBinary()
+		if err != nil {
+			t.Error(err)
+		}
+		exbin, err := hex.DecodeString(c.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bin, exbin) {
+			t.Errorf(`%d: want: %x, got: %x`, c.v, exbin, bin)
+		}
+		var dec infounit.BitDelta
+		if err := dec.UnmarshalBinary(exbin); err != nil {
+			t.Error(err)
+		}
+		if dec != bd {
+			t.Errorf(`%s: want: %d, got: %d`, c.hex, bd, dec)
+		}
+	}
+}
+
+//
+func TestBitDelta_Text(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v   int64
+		txt string
+	}{
+		{0, "0 bit"},
+		{1, "1 bit"},
+		{-1, "-1 bit"},
+		{-987654321, "-987654321 bit"},
+		{-9223372036854775808, "-9223372036854775808 bit"},
+		{9223372036854775807, "9223372036854775807 bit"},
+	}
+
+	for _, c := range tc {
+		bd := infounit.BitDelta(c.v)
+		txtb, err := bd.MarshalText()
+		if err != nil {
+			t.Error(err)
+		}
+		if txt := string(txtb); txt != c.txt {
+			t.Errorf(`%d: want: "%s", got: "%s"`, c.v, c.txt, txt)
+		}
+		var dec infounit.BitDelta
+		if err := dec.UnmarshalText([]byte(c.txt)); err != nil {
+			t.Error(err)
+		}
+		if dec != bd {
+			t.Errorf(`%s: want: %d, got: %d`, c.txt, bd, dec)
+		}
+	}
+}
+
+//
+func TestBitDelta_YAML(t *testing.T) {
+	t.Parallel()
+
+	v := struct {
+		Val      infounit.BitDelta
+		Ptr      *infounit.BitDelta
+		VarExprs []infounit.BitDelta
+	}{}
+
+	yamlSrc := strings.Join([]string{
+		"val: -1111",
+		"ptr: 99991111",
+		"varexprs:",
+		`- "+123 kilobits"`,
+		`- "-345 Mibit"`,
+		`- -67.8 Gbit`,
+		"",
+	}, "\n")
+
+	if err := yaml.UnmarshalStrict(([]byte)(yamlSrc), &v); err != nil {
+		t.Fatalf("yaml.Unmarshal() failed: %v", err)
+	}
+	if v.Val != -1111 {
+		t.Errorf("Val: unexpected value: got: %v, want: -1111 bit", v.Val)
+	}
+	if v.Ptr == nil || *v.Ptr != 99991111 {
+		t.Errorf("Ptr: unexpected value: got: %v, want: %d", v.Ptr, 99991111)
+	}
+	ex := []infounit.BitDelta{
+		infounit.BitDelta(infounit.Kilobit) * 123,
+		infounit.BitDelta(infounit.Mebibit) * -345,
+		infounit.BitDelta(infounit.Megabit) * -67800,
+	}
+	if len(v.VarExprs) != len(ex) {
+		t.Fatalf("VarExprs: unexpected length: got: %d, want: %d", len(v.VarExprs), len(ex))
+	}
+	for i := range ex {
+		if v.VarExprs[i] != ex[i] {
+			t.Errorf("VarExprs[%d]: unexpected value: got: %d, want: %d", i, v.VarExprs[i], ex[i])
+		}
+	}
+
+	out, err := yaml.Marshal(&v)
+	if err != nil {
+		t.Fatalf("yaml.Marshal() failed: %v", err)
+	}
+	exOut := strings.Join([]string{
+		"val: -1111",
+		"ptr: 99991111",
+		"varexprs:",
+		"- 123000",
+		"- -361758720",
+		"- -67800000000",
+		"",
+	}, "\n")
+	if string(out) != exOut {
+		t.Errorf("yaml.Marshal() unexpected result: %q", out)
+	}
+}
+
+//
+func TestBitDelta_JSON(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s string
+		v infounit.BitDelta
+		o string
+	}{
+		{s: `12345`, v: 12345, o: `12345`},
+		{s: `-4567890`, v: -4567890, o: `-4567890`},
+		{s: `"+123 kbit"`, v: 123000, o: `123000`},
+		{s: `"-456 Mbit"`, v: -456000000, o: `-456000000`},
+		{s: `"-789 gibibits"`, v: infounit.BitDelta(infounit.Gibibit) * -789, o: `-847182299136`},
+	}
+	for _, c := range tc {
+		var bd infounit.BitDelta
+		if err := json.Unmarshal([]byte(c.s), &bd); err != nil {
+			t.Errorf("%v: json.Unmarshal() failed: %v", c.s, err)
+			continue
+		}
+		if bd != c.v {
+			t.Errorf("%s: unexpected output: want: %d, got: %d", c.s, c.v, bd)
+		}
+		b, err := json.Marshal(&bd)
+		if err != nil {
+			t.Errorf("%v: json.Marshal() failed: %v", bd, err)
+			continue
+		}
+		if string(b) != c.o {
+			t.Errorf("unexpected JSON output: want: %q, got: %q", c.o, b)
+		}
+	}
+
+	var bd infounit.BitDelta = 7
+	if err := json.Unmarshal([]byte(`null`), &bd); err != nil || bd != 7 {
+		t.Errorf("null: unexpected result: %d, %v", bd, err)
+	}
+}
+
+//
+func TestBitDelta_Unmarshal_outOfRange(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"9223372036854775808", "-9223372036854775809", "99999999999999999999999", "1e19", "-1e19"} {
+		var v infounit.BitDelta
+		if err := json.Unmarshal([]byte(s), &v); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`JSON %s: ErrOutOfRange expected, got: %v, %v`, s, v, err)
+		}
+		if err := yaml.Unmarshal([]byte("- "+s), &[]infounit.BitDelta{v}); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`YAML %s: ErrOutOfRange expected, got: %v`, s, err)
+		}
+	}
+	for _, s := range []string{"-10 Ebit", "+8 Eibit", "9223372036854775808 bit"} {
+		var v infounit.BitDelta
+		if err := v.UnmarshalText([]byte(s)); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`Text %s: ErrOutOfRange expected, got: %v, %v`, s, v, err)
+		}
+		if err := json.Unmarshal([]byte(`"`+s+`"`), &v); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`JSON %q: ErrOutOfRange expected, got: %v, %v`, s, v, err)
+		}
+		if err := yaml.Unmarshal([]byte("- "+s), &[]infounit.BitDelta{v}); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`YAML %q: ErrOutOfRange expected, got: %v`, s, err)
+		}
+	}
+}
+
+//
+func TestBitDelta_AppendText(t *testing.T) {
+	t.Parallel()
+
+	v := infounit.BitDelta(-123456789)
+	txt, err := v.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ex := string(txt), "-123456789 bit"; s != ex {
+		t.Errorf(`MarshalText: want: "%s", got: "%s"`, ex, s)
+	}
+	app, err := v.AppendText([]byte("x="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ex := string(app), "x="+string(txt); s != ex {
+		t.Errorf(`AppendText: want: "%s", got: "%s"`, ex, s)
+	}
+}
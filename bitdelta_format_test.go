@@ -0,0 +1,117 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestBitDelta_Format_1(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v int64
+		f string
+		s string
+	}{
+		{0, "%s", "0bit"},
+		{0, "%+s", "+0bit"},
+		{1, "%# s", "1 bit"},
+		{-1, "%# s", "-1 bit"},
+		{-2, "%# s", "-2 bits"},
+		{1500000000, "%+ .1s", "+1.5 Gbit"},
+		{-1500000000, "% .1s", "-1.5 Gbit"},
+		{-1500000000, "%s", "-1.5Gbit"},
+		{-209715200, "% S", "-200 Mibit"},
+		{-209715200, "%+ S", "-200 Mibit"},
+		{209715200, "%+# .1S", "+200.0 mebibits"},
+		{-1000, "%# s", "-1 kilobit"},
+		{-999960, "%.1s", "-1.0Mbit"},
+		{math.MinInt64, "%S", "-8Eibit"},
+		{math.MaxInt64, "%.3S", "8.000Eibit"},
+		{-123456, "[%12.1s]", "[  -123.5kbit]"},
+		{-123456, "[%-12.1s]", "[-123.5kbit  ]"},
+		{-123456, "[%012.1s]", "[-00123.5kbit]"},
+		{-123456, "[%014.1s]", "[-0000123.5kbit]"},
+		{123456, "[%+014.1s]", "[+0000123.5kbit]"},
+		{123456, "[%014.1s]", "[00000123.5kbit]"},
+		{-123456, "[%3.1s]", "[-123.5kbit]"},
+		{-1500000000, "%v", "-1.5 Gbit"},
+		{1500000000, "%v", "1.5 Gbit"},
+		{1500000000, "%+v", "+1.5 Gbit"},
+		{-1024, "%#v", "BitDelta(-1024)"},
+		{-1024, "%d", "-1024"},
+		{1024, "%+d", "+1024"},
+		{-255, "%x", "-ff"},
+		{-255, "%X", "-FF"},
+		{-5, "%b", "-101"},
+		{-8, "%o", "-10"},
+		{-8, "%06d", "-00008"},
+		{-8, "%q", "%!q(BitDelta=-8)"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.BitDelta(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %d: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestBitDelta_String(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v int64
+		s string
+	}{
+		{0, "0 bit"},
+		{-1, "-1 bit"},
+		{-999, "-999 bit"},
+		{-1000, "-1.0 kbit"},
+		{123456789, "123.5 Mbit"},
+		{-123456789, "-123.5 Mbit"},
+	}
+
+	for _, c := range tc {
+		if s := infounit.BitDelta(c.v).String(); s != c.s {
+			t.Errorf(`%d: want: "%s", got: "%s"`, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func BenchmarkBitDelta_AppendFormat(b *testing.B) {
+	v := infounit.BitDelta(-123456789)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = v.AppendFormat(buf[:0], nil)
+	}
+}
+
+//
+func BenchmarkBitDelta_String(b *testing.B) {
+	v := infounit.BitDelta(-123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = v.String()
+	}
+}
+
+//
+func BenchmarkBitDelta_Sprintf(b *testing.B) {
+	v := infounit.BitDelta(-123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%# .2S", v)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestBitDelta_Scan_1(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		src string
+		fmt string
+		bd  infounit.BitDelta
+		es  string
+	}{
+		{"0bit", "%s", 0, ""},
+		{"+0 bit", "%s", 0, ""},
+		{"-0", "%u", 0, ""},
+		{"123", "%u", 123, ""},
+		{"-123", "%u", -123, ""},
+		{"-123", "%U", -123, ""},
+		{"+1.5 Gbit", "%s", infounit.BitDelta(infounit.Megabit) * 1500, ""},
+		{"-1.5 Gbit", "%s", infounit.BitDelta(infounit.Megabit) * -1500, ""},
+		{"-1.5 Gbit", "%S", infounit.BitDelta(infounit.Mebibit) * -1536, ""},
+		{"-200 Mibit", "%s", infounit.BitDelta(infounit.Mebibit) * -200, ""},
+		{"-200 mebibits", "%s", infounit.BitDelta(infounit.Mebibit) * -200, ""},
+		{"-2kbit", "%u", -2000, ""},
+		{"-2kbit", "%U", -2048, ""},
+		{"-9223372036854775808 bit", "%s", -9223372036854775808, ""},
+		{"9223372036854775807 bit", "%s", 9223372036854775807, ""},
+
+		{"9223372036854775808 bit", "%s", 0, "%s: out of range"},
+		{"-9223372036854775809 bit", "%s", 0, "%s: out of range"},
+		{"+8 Eibit", "%s", 0, "%s: out of range"},
+		{"-20 Ebit", "%s", 0, "%s: 20 Ebit: out of range"},
+		{"- 1 kbit", "%s", 0, "%s: space after sign: -"},
+		{"+ 1 kbit", "%s", 0, "%s: space after sign: +"},
+		{"--1 kbit", "%s", 0, "%s: invalid expr: -1"},
+		{"-1.5 bit", "%s", 0, "%s: non-integer bit count: 1.5"},
+
+		{"-1024", "%d", -1024, ""},
+		{"+1024", "%d", 1024, ""},
+		{"-ff", "%x", -255, ""},
+		{"-101", "%b", -5, ""},
+		{"-10", "%o", -8, ""},
+	}
+
+	for _, c := range tc {
+		var bd infounit.BitDelta
+		_, err := fmt.Sscanf(c.src, c.fmt, &bd)
+		switch c.es {
+		case "": // expecting no error
+			switch {
+			case err != nil:
+				t.Errorf("src='%s', fmt='%s': %s", c.src, c.fmt, err)
+				continue
+			case bd != c.bd:
+				t.Errorf("src='%s', fmt='%s': want: %#v, got: %#v", c.src, c.fmt, c.bd, bd)
+				continue
+			}
+		default: // expecting error
+			switch {
+			case err == nil:
+				t.Errorf("src='%s', fmt='%s': error expected: got: %v", c.src, c.fmt, bd)
+				continue
+			case err.Error() != c.es:
+				t.Errorf("src='%s', fmt='%s': error want: %s, got: %s", c.src, c.fmt, c.es, err.Error())
+				continue
+			}
+		}
+	}
+}
+
+//
+func TestBitDelta_Scan_sscanf(t *testing.T) {
+	t.Parallel()
+
+	var x, y infounit.BitDelta
+	var name string
+	n, err := fmt.Sscanf("usage: +1.5 Gbit quota: -200 Mibit /var", "usage: %s quota: %s %s", &x, &y, &name)
+	switch {
+	case err != nil:
+		t.Fatalf("unexpected error: %v", err)
+	case n != 3:
+		t.Fatalf("unexpected n: want: 3, got: %d", n)
+	}
+	if ex := infounit.BitDelta(infounit.Megabit) * 1500; x != ex {
+		t.Errorf("x: want: %v, got: %v", ex, x)
+	}
+	if ex := infounit.BitDelta(infounit.Mebibit) * -200; y != ex {
+		t.Errorf("y: want: %v, got: %v", ex, y)
+	}
+}
@@ -7,11 +7,8 @@ package infounit
 import (
 	"encoding/binary"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"math"
-	"regexp"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -48,7 +45,46 @@ const (
 // String returns the human-readable string representing the bit rate using SI
 // prefix. This implements the Stringer interface in the package fmt.
 func (br BitRate) String() string {
-	return fmt.Sprintf("% .1s", br)
+	var buf [fmtBufSize]byte
+	return string(br.AppendFormat(buf[:0], nil))
+}
+
+// FormatIn returns the string representation of the bit rate in the unit, such
+// as MegabitPerSecond, with prec digits after the decimal point, e.g.
+// "1500.0 Mbit/s" for 1.5 Gbit/s in MegabitPerSecond. Unlike String, the prefix
+// is never selected automatically, so that all the values in a column of a
+// table can be formatted in the same unit. See Formatter.FormatBitRateIn for
+// the other options such as the long unit names. If unit is not BitPerSecond
+// or one of the constants with a prefix, ErrInvalidUnit is returned.
+func (br BitRate) FormatIn(unit BitRate, prec int) (string, error) {
+	f := Formatter{Precision: prec, Separator: " "}
+	return f.FormatBitRateIn(br, unit)
+}
+
+// BestBitRateUnit returns the unit in which the largest magnitude of the values
+// is formatted with the prefix system ps and prec digits after the decimal
+// point, e.g. GigabitPerSecond for the SI prefixes if it is 12.5 Gbit/s. As
+// with the formatting, the unit is selected after rounding. The result is
+// passed to FormatIn with the same prec to format all the values in the same
+// unit. It returns BitPerSecond if vs is empty.
+func BestBitRateUnit(vs []BitRate, ps PrefixSystem, prec int) BitRate {
+	var max float64
+	for _, v := range vs {
+		if a := math.Abs(float64(v)); max < a {
+			max = a
+		}
+	}
+	f := Formatter{Prefixes: ps, Precision: prec}
+	return BitRate(f.floatUnit(max))
+}
+
+// AppendFormat appends the human-readable string representation of the
+// BitRate value formatted by f to dst and returns the extended buffer. If f is
+// nil, the default format "% .1s", the same as String, is used. Unlike String
+// and the Printf family functions, this does not allocate as long as dst has
+// enough capacity.
+func (br BitRate) AppendFormat(dst []byte, f *Formatter) []byte {
+	return f.orDefault().appendFloat(dst, float64(br), unitBitRate)
 }
 
 // GoString returns a string representation of the BitRate value in Go syntax
@@ -70,6 +106,31 @@ func (br BitRate) IsNaN() bool {
 	return math.IsNaN(float64(br))
 }
 
+// ByteRate returns the value converted to the number of bytes per second.
+// Since the conversion is a division by a power of two, no precision is lost.
+func (br BitRate) ByteRate() ByteRate {
+	return ByteRate(br / 8)
+}
+
+// Truncate returns the result of rounding br toward zero to a multiple of m.
+// If m is not positive, Truncate returns br unchanged.
+func (br BitRate) Truncate(m BitRate) BitRate {
+	if !(0 < m) {
+		return br
+	}
+	return BitRate(math.Trunc(float64(br/m))) * m
+}
+
+// Round returns the result of rounding br to the nearest multiple of m. The
+// rounding behavior for halfway values is to round away from zero. If m is not
+// positive, Round returns br unchanged.
+func (br BitRate) Round(m BitRate) BitRate {
+	if !(0 < m) {
+		return br
+	}
+	return BitRate(math.Round(float64(br/m))) * m
+}
+
 // Convert converts the bit rate to a float value in the specified unit. If the
 // goal is to output or to create a string in a human-readable format,
 // fmt.Printf or fmt.Sprintf is preferred.
@@ -172,18 +233,25 @@ func (br *BitRate) UnmarshalBinary(data []byte) error {
 // the result. This implements the TextMarshaler interface in the
 // package encoding.
 func (br *BitRate) MarshalText() ([]byte, error) {
-	v := float64(AtomicLoadBitRate(br))
-	return ([]byte)(strconv.FormatFloat(v, 'f', -1, 64) + " bit/s"), nil
+	return br.AppendText(make([]byte, 0, 32))
+}
+
+// AppendText appends the UTF-8-encoded text form of the BitRate value, the same
+// as MarshalText, to dst and returns the extended buffer. This implements the
+// TextAppender interface in the package encoding.
+func (br *BitRate) AppendText(dst []byte) ([]byte, error) {
+	dst = strconv.AppendFloat(dst, float64(AtomicLoadBitRate(br)), 'f', -1, 64)
+	return append(dst, " bit/s"...), nil
 }
 
 // UnmarshalText decodes the BitRate value from a UTF-8-encoded text form. This
 // implements the TextUnmarshaler interface in the package encoding.
 func (br *BitRate) UnmarshalText(text []byte) error {
-	var val BitRate
-	if _, err := fmt.Sscanf(string(text), "%s", &val); err != nil {
+	val, err := unmarshalParser.ParseBitRate(string(text))
+	if err != nil {
 		return err
 	}
-	AtomicStoreBitRate(br, val)
+	AtomicStoreBitRate(br, BitRate(val))
 	return nil
 }
 
@@ -203,16 +271,16 @@ func (br *BitRate) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	var s string
 	if unmarshal(&s) == nil {
-		v, err := ParseBitRate(s)
+		v, err := unmarshalParser.ParseBitRate(s)
 		if err != nil {
-			return fmt.Errorf("%q: %w: %v", s, ErrMalformedRepresentation, err)
+			return err
 		}
 		AtomicStoreBitRate(br, v)
 
 		return nil
 	}
 
-	return fmt.Errorf("%w: unexpected type", ErrMalformedRepresentation)
+	return typeError("BitRate", yamlInput(unmarshal))
 }
 
 // IsZero returns whether the BitRate value is zero.
@@ -240,25 +308,22 @@ func (br *BitRate) UnmarshalJSON(b []byte) error {
 
 	var s string
 	if json.Unmarshal(b, &s) == nil {
-		v, err := ParseBitRate(s)
+		v, err := unmarshalParser.ParseBitRate(s)
 		if err != nil {
-			return fmt.Errorf("%q: %w: %v", s, ErrMalformedRepresentation, err)
+			return err
 		}
 		AtomicStoreBitRate(br, v)
 
 		return nil
 	}
 
-	return fmt.Errorf("%w: unexpected type", ErrMalformedRepresentation)
+	return typeError("BitRate", string(b))
 }
 
 //
-const (
-	unitBitRateFull       = unitBitFull
-	unitBitRateAbbr       = unitBitRateFull
-	unitBitRateAlt        = "bps"
-	unitBitRateAbbrSuffix = "/s"
-	unitBitRateLongSuffix = "per second"
+var (
+	unitBitRate    = &unitName{abbr: "bit", full: "bit", sufAbbr: "/s", sufFull: "per second"}
+	unitBitRateAlt = &unitName{abbr: "bps", full: "bit", sufFull: "per second"}
 )
 
 // Format implements the Formatter interface in the package fmt to format
@@ -267,10 +332,12 @@ const (
 // package fmt; fmt.Printf, fmt.Fprintf, fmt.Sprintf, fmt.Errorf, and functions
 // derived from them.
 //
-// For ByteRate type, four custom 'verbs' are implemented:
+// For BitRate type, six custom 'verbs' are implemented:
 //
 // 	%s, %a	human-readable format with SI prefix
 // 	%S, %A	human-readable format with binary prefix
+// 	%h	human-readable format with SI prefix and significant digits
+// 	%H	human-readable format with binary prefix and significant digits
 //
 // %s and %S use "bit/s" unit suffix; e.g. "Mbit/s", "Gibit/s"
 // %a and %A use "bps" unit suffix; e.g. "Mbps", "Gibps"
@@ -283,12 +350,22 @@ const (
 // 	%7.2s	width 7, precision 2
 // 	%7.s	width 7, precision 0
 //
+// Negative values are scaled by their magnitude and printed with the sign,
+// e.g. "-5.0 Gbit/s".
+//
+// The prefix is selected after rounding to the precision, so "%.1s" prints
+// 999.96 as "1.0 kbit/s", not as "1000.0 bit/s".
+//
+// The verbs %h and %H are the same as %s and %S, except that the precision is
+// the number of significant digits, which is 3 by default, like "ls -h"; e.g.
+// "1.23 Gbit/s", "12.3 Gbit/s" and "123 Gbit/s" with the ' ' flag.
+//
 // The following flags are also available for %s, %S, %a and %A:
 //
 // 	' '	(space) print a space between digits and unit; e.g. "12.3 Mbit/s"
 // 	#	use long unit name; e.g. "kilobits per second", "mebibits per second"
 // 	-	pad with spaces on the right rather than the left (left-justify)
-// 	0	pad with leading zeros rather than spaces
+// 	0	pad with leading zeros rather than spaces, after the sign
 //
 // %v prints in the default format:
 //
@@ -311,44 +388,22 @@ const (
 // See the package fmt documentation for details.
 func (br BitRate) Format(s fmt.State, verb rune) {
 	switch verb {
-	case 's', 'S', 'a', 'A':
-		tFmt := "%"
-		if s.Flag(int('-')) {
-			tFmt += "-"
+	case 's', 'S', 'a', 'A', 'h', 'H':
+		f := verbFormatter(s, verb)
+		u := unitBitRate
+		if verb == 'a' || verb == 'A' {
+			u = unitBitRateAlt
 		}
-		if s.Flag(int('0')) {
-			tFmt += "0"
-		}
-		if wid, ok := s.Width(); ok {
-			tFmt += strconv.FormatInt(int64(wid), 10)
-		}
-		tFmt += "s"
-		prec, ok := s.Precision()
-		if !ok {
-			prec = -1
-		}
-		full, space := s.Flag(int('#')), s.Flag(int(' '))
-		var pfx *prefix
-		var uabbr, usuff string
-		switch verb {
-		case 's':
-			pfx, uabbr, usuff = siPrefix, unitBitRateAbbr, unitBitRateAbbrSuffix
-		case 'S':
-			pfx, uabbr, usuff = binPrefix, unitBitRateAbbr, unitBitRateAbbrSuffix
-		case 'a':
-			pfx, uabbr, usuff = siPrefix, unitBitRateAlt, ""
-		case 'A':
-			pfx, uabbr, usuff = binPrefix, unitBitRateAlt, ""
-		}
-		expr := pfx.formatFloat(float64(br), prec, full, space, uabbr, usuff)
-		fmt.Fprintf(s, tFmt, expr)
+		var buf [fmtBufSize]byte
+		writePadded(s, f.appendFloat(buf[:0], float64(br), u))
 
 	case 'v':
 		if s.Flag(int('#')) {
 			fmt.Fprint(s, br.GoString())
 			break
 		}
-		fmt.Fprint(s, br.String())
+		var buf [fmtBufSize]byte
+		_, _ = s.Write(br.AppendFormat(buf[:0], nil))
 
 	case 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X':
 		tFmt := "%"
@@ -374,65 +429,23 @@ func (br BitRate) Format(s fmt.State, verb rune) {
 	}
 }
 
-//
-type bitRateScanUnitEnt struct {
-	re  *regexp.Regexp
-	brs float64
-	brb float64
-}
-
-var (
-	bitRateScanTokenRe []*regexp.Regexp
-	bitRateScanUnitRe  []bitRateScanUnitEnt
-	bitRateScanUnit3Re []bitRateScanUnitEnt // 3 tokens unit suffix, e.g. "kilobits per second"
-)
-
-//
-func init() {
-	bitRateScanTokenRe = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)^(nan|[+-]inf|([+-]?[0-9]*)(\.[0-9]+)?)([a-z/]*)$`), // 1:num, 2:int, 3:frac, 4:unit
-		regexp.MustCompile(`(?i)^([a-z/]+)$`),                                       // 1:unit
-		regexp.MustCompile(`(?i)^per$`),
-		regexp.MustCompile(`(?i)^sec(ond)?$`),
-	}
-	ent := func(s string, brs, brb BitRate) bitRateScanUnitEnt {
-		return bitRateScanUnitEnt{
-			re:  regexp.MustCompile(`(?i)^` + s + `$`),
-			brs: float64(brs),
-			brb: float64(brb),
-		}
-	}
-	st := "(bps|bit/s)"
-	bitRateScanUnitRe = []bitRateScanUnitEnt{
-		ent(st, BitPerSecond, BitPerSecond),
-		ent("k"+st, KilobitPerSecond, KibibitPerSecond),
-		ent("m"+st, MegabitPerSecond, MebibitPerSecond),
-		ent("g"+st, GigabitPerSecond, GibibitPerSecond),
-		ent("t"+st, TerabitPerSecond, TebibitPerSecond),
-		ent("p"+st, PetabitPerSecond, PebibitPerSecond),
-		ent("e"+st, ExabitPerSecond, ExbibitPerSecond),
-		ent("ki"+st, KibibitPerSecond, KibibitPerSecond),
-		ent("mi"+st, MebibitPerSecond, MebibitPerSecond),
-		ent("gi"+st, GibibitPerSecond, GibibitPerSecond),
-		ent("ti"+st, TebibitPerSecond, TebibitPerSecond),
-		ent("pi"+st, PebibitPerSecond, PebibitPerSecond),
-		ent("ei"+st, ExbibitPerSecond, ExbibitPerSecond),
-	}
-	bitRateScanUnit3Re = []bitRateScanUnitEnt{
-		ent("bits?", BitPerSecond, BitPerSecond),
-		ent("k(ilo)?bits?", KilobitPerSecond, KibibitPerSecond),
-		ent("m(ega)?bits?", MegabitPerSecond, MebibitPerSecond),
-		ent("g(iga)?bits?", GigabitPerSecond, GibibitPerSecond),
-		ent("t(era)?bits?", TerabitPerSecond, TebibitPerSecond),
-		ent("p(eta)?bits?", PetabitPerSecond, PebibitPerSecond),
-		ent("e(xa)?bits?", ExabitPerSecond, ExbibitPerSecond),
-		ent("(ki|kibi)bits?", KibibitPerSecond, KibibitPerSecond),
-		ent("(mi|mebi)bits?", MebibitPerSecond, MebibitPerSecond),
-		ent("(gi|gibi)bits?", GibibitPerSecond, GibibitPerSecond),
-		ent("(ti|tebi)bits?", TebibitPerSecond, TebibitPerSecond),
-		ent("(pi|pebi)bits?", PebibitPerSecond, PebibitPerSecond),
-		ent("(ei|exbi)bits?", ExbibitPerSecond, ExbibitPerSecond),
-	}
+// bitRateUnits is the table of the unit suffixes of BitRate consisting of one
+// token. The long unit names, e.g. "kilobits per second", are looked up in
+// bitCountUnits by the first word.
+var bitRateUnits = []scanUnit{
+	{"bps", -1, false, false}, {"bit/s", -1, false, false},
+	{"kbps", 0, false, false}, {"kbit/s", 0, false, false},
+	{"mbps", 1, false, false}, {"mbit/s", 1, false, false},
+	{"gbps", 2, false, false}, {"gbit/s", 2, false, false},
+	{"tbps", 3, false, false}, {"tbit/s", 3, false, false},
+	{"pbps", 4, false, false}, {"pbit/s", 4, false, false},
+	{"ebps", 5, false, false}, {"ebit/s", 5, false, false},
+	{"kibps", 0, true, false}, {"kibit/s", 0, true, false},
+	{"mibps", 1, true, false}, {"mibit/s", 1, true, false},
+	{"gibps", 2, true, false}, {"gibit/s", 2, true, false},
+	{"tibps", 3, true, false}, {"tibit/s", 3, true, false},
+	{"pibps", 4, true, false}, {"pibit/s", 4, true, false},
+	{"eibps", 5, true, false}, {"eibit/s", 5, true, false},
 }
 
 // Scan implements the Scanner interface in the package fmt to scan BitRate
@@ -482,123 +495,12 @@ func (br *BitRate) Scan(state fmt.ScanState, verb rune) error {
 		}
 
 	case 's', 'S', 'u', 'U':
-		token1Bytes, err := state.Token(true, nil)
-		switch {
-		case err != nil:
-			return fmt.Errorf("%%%c: %w", verb, err)
-		case len(token1Bytes) < 1:
-			return fmt.Errorf("%%%c: no input", verb)
-		}
-		token1Str := string(token1Bytes)
-		token1 := bitRateScanTokenRe[0].FindStringSubmatch(token1Str)
-		if token1 == nil {
-			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
-		}
-		// fmt.Printf("[SCAN] TOKEN1: %+v\n", token1)
-
-		numExpr, unitExpr := token1[1], token1[4]
-
-		if len(numExpr) < 1 {
-			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
-		}
-		if unitExpr == "" { // no unit suffix within the first token
-			switch verb {
-			case 'u', 'U':
-				// does not read the second token, assumed to be bit/s
-				unitExpr = "bit/s"
-			case 's', 'S':
-				sp, n, err := state.ReadRune() // read only one space
-				switch {
-				case err != nil:
-					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
-				case n != 1:
-					return fmt.Errorf("%%%c: no unit suffix", verb)
-				case sp != ' ':
-					return fmt.Errorf("%%%c: no space after digits: [%c]", verb, sp)
-				}
-				token2Bytes, err := state.Token(false, nil)
-				switch {
-				case err != nil:
-					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
-				case len(token2Bytes) < 1:
-					return fmt.Errorf("%%%c: no unit suffix", verb)
-				}
-				token2Str := string(token2Bytes)
-				token2 := bitRateScanTokenRe[1].FindStringSubmatch(token2Str)
-				if token2 == nil {
-					return fmt.Errorf("%%%c: invalid unit expr: %s", verb, token2Str)
-				}
-				// fmt.Printf("[SCAN] TOKEN2: %+v\n", token2)
-				unitExpr = token2[1]
-				if unitExpr == "" {
-					return fmt.Errorf("%%%c: no unit suffix", verb)
-				}
-			}
-		}
-
-		// fmt.Printf("[SCAN] FIRST [%s] [%s]\n", numExpr, unitExpr)
-
-		numVal, err := strconv.ParseFloat(numExpr, 64)
+		lx := lexer{state: state}
+		v, err := lx.scanFloat(verb, bitRateUnits, bitCountUnits)
 		if err != nil {
-			return fmt.Errorf("%%%c: invalid expr: %s", verb, numExpr)
-		}
-
-		ptr := (*float64)(br)
-
-		for _, unit := range bitRateScanUnitRe {
-			if unit.re.MatchString(unitExpr) {
-				switch verb {
-				case 's', 'u':
-					*ptr = numVal * unit.brs
-				case 'S', 'U':
-					*ptr = numVal * unit.brb
-				}
-				return nil
-			}
-		}
-
-		// try 3 tokens units
-		// 12.3kilobits per second
-		// 12.3 kilobits per second
-		eSuf := unitExpr
-		for i := 0; i < 2; i++ {
-			sp, n, err := state.ReadRune() // read only one space
-			switch {
-			case errors.Is(err, io.EOF):
-				return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
-			case err != nil:
-				return fmt.Errorf("%%%c: invalid unit suffix: %s: %w", verb, eSuf, err)
-			case n != 1:
-				return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
-			case sp != ' ':
-				return fmt.Errorf("%%%c: unknown unit: %s%c", verb, eSuf, sp)
-			}
-			token34Bytes, err := state.Token(false, nil)
-			switch {
-			case errors.Is(err, io.EOF):
-				return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
-			case err != nil:
-				return fmt.Errorf("%%%c: invalid unit suffix: %s: %w", verb, eSuf, err)
-			case len(token34Bytes) < 1:
-				return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
-			case !bitRateScanTokenRe[2+i].Match(token34Bytes):
-				return fmt.Errorf("%%%c: unknown unit: %s %s", verb, eSuf, string(token34Bytes))
-			}
-			eSuf += string(sp) + string(token34Bytes)
-		}
-
-		for _, unit := range bitRateScanUnit3Re {
-			if unit.re.MatchString(unitExpr) {
-				switch verb {
-				case 's', 'u':
-					*ptr = numVal * unit.brs
-				case 'S', 'U':
-					*ptr = numVal * unit.brb
-				}
-				return nil
-			}
+			return err
 		}
-		return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
+		*br = BitRate(v)
 
 	default:
 		return fmt.Errorf("unknown verb for BitRate: %%%c", verb)
@@ -610,20 +512,20 @@ func (br *BitRate) Scan(state fmt.ScanState, verb rune) error {
 // value. The human-readable string is a decimal number with a unit suffix. SI
 // and binary prefixes are correctly recognized.
 func ParseBitRate(s string) (BitRate, error) {
-	var v BitRate
-	if _, err := fmt.Sscanf(s, "%s", &v); err != nil {
-		return 0, fmt.Errorf("invalid bit rate: %s: %w", s, err)
+	v, err := parseFloat(s, "BitRate", 's', bitRateUnits, bitCountUnits)
+	if err != nil {
+		return 0, err
 	}
-	return v, nil
+	return BitRate(v), nil
 }
 
 // ParseBitRateBinary is the same as ParseBitRate except that it treats the SI
 // prefixes as binary prefixes. That is, it parses "100 kbit/s" as 100 Kibit/s
 // (=102400 bit/s).
 func ParseBitRateBinary(s string) (BitRate, error) {
-	var v BitRate
-	if _, err := fmt.Sscanf(s, "%S", &v); err != nil {
-		return 0, fmt.Errorf("invalid bit rate: %s: %w", s, err)
+	v, err := parseFloat(s, "BitRate", 'S', bitRateUnits, bitCountUnits)
+	if err != nil {
+		return 0, err
 	}
-	return v, nil
+	return BitRate(v), nil
 }
@@ -352,3 +352,24 @@ func TestBitRate_UnmarshalJSON(t *testing.T) {
 		}
 	}
 }
+
+//
+func TestBitRate_AppendText(t *testing.T) {
+	t.Parallel()
+
+	v := infounit.BitRate(123456789)
+	txt, err := v.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ex := string(txt), "123456789 bit/s"; s != ex {
+		t.Errorf(`MarshalText: want: "%s", got: "%s"`, ex, s)
+	}
+	app, err := v.AppendText([]byte("x="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ex := string(app), "x="+string(txt); s != ex {
+		t.Errorf(`AppendText: want: "%s", got: "%s"`, ex, s)
+	}
+}
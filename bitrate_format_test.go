@@ -5,7 +5,9 @@
 package infounit_test
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
@@ -129,3 +131,168 @@ func TestBitRate_Format_2(t *testing.T) {
 		}
 	}
 }
+
+//
+func TestBitRate_Format_carry(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v float64
+		f string
+		s string
+	}{
+		{999.94, "%.1s", "999.9bit/s"},
+		{999.96, "%.1s", "1.0kbit/s"},
+		{1023.96, "%.1S", "1.0Kibit/s"},
+		{1023.96, "%.2S", "1023.96bit/s"},
+		{999960, "%.1s", "1.0Mbit/s"},
+		{999960, "%# .1s", "1.0 megabits per second"},
+		{1048535, "%.1S", "1.0Mibit/s"},
+		{999999999999999999999, "%.1s", "1000.0Ebit/s"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.BitRate(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %f: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestBitRate_Format_significant(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v float64
+		f string
+		s string
+	}{
+		{5, "%h", "5.00bit/s"},
+		{0.5, "%h", "0.500bit/s"},
+		{0.0123456, "%h", "0.0123bit/s"},
+		{0.99996, "%h", "1.00bit/s"},
+		{123456789, "%h", "123Mbit/s"},
+		{123456789, "% .4h", "123.5 Mbit/s"},
+		{12345678, "%H", "11.8Mibit/s"},
+		{999.96, "%h", "1.00kbit/s"},
+		{999960, "%# h", "1.00 megabits per second"},
+		{-1234567, "%h", "-1.23Mbit/s"},
+		{999999999999999999999, "%h", "1000Ebit/s"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.BitRate(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %f: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestBitRate_FormatIn(t *testing.T) {
+	t.Parallel()
+
+	rates := []infounit.BitRate{
+		infounit.KilobitPerSecond * 800,
+		-infounit.MegabitPerSecond * 12.5,
+		infounit.BitRate(math.NaN()),
+		infounit.MegabitPerSecond * 3,
+	}
+	unit := infounit.BestBitRateUnit(rates, infounit.SIPrefixes, 1)
+	if unit != infounit.MegabitPerSecond {
+		t.Fatalf("BestBitRateUnit: unexpected unit: %v", unit)
+	}
+	want := []string{"0.8 Mbit/s", "-12.5 Mbit/s", "NaN bit/s", "3.0 Mbit/s"}
+	for i, r := range rates {
+		s, err := r.FormatIn(unit, 1)
+		if err != nil || s != want[i] {
+			t.Errorf("%v: want: %q, got: %q, %v", float64(r), want[i], s, err)
+		}
+	}
+	if unit := infounit.BestBitRateUnit(nil, infounit.BinaryPrefixes, 1); unit != infounit.BitPerSecond {
+		t.Errorf("BestBitRateUnit(nil): unexpected unit: %v", unit)
+	}
+	if unit := infounit.BestBitRateUnit(rates, infounit.BinaryPrefixes, 1); unit != infounit.MebibitPerSecond {
+		t.Errorf("BestBitRateUnit: unexpected unit: %v", unit)
+	}
+
+	// the unit is selected after rounding
+	rates = []infounit.BitRate{infounit.KilobitPerSecond * 999.96}
+	if unit := infounit.BestBitRateUnit(rates, infounit.SIPrefixes, 1); unit != infounit.MegabitPerSecond {
+		t.Errorf("BestBitRateUnit: unexpected unit: %v", unit)
+	}
+	if unit := infounit.BestBitRateUnit(rates, infounit.SIPrefixes, 2); unit != infounit.KilobitPerSecond {
+		t.Errorf("BestBitRateUnit: unexpected unit: %v", unit)
+	}
+
+	if s, err := infounit.MegabitPerSecond.FormatIn(infounit.KilobitPerSecond*3, 1); !errors.Is(err, infounit.ErrInvalidUnit) {
+		t.Errorf("FormatIn: unexpected result: %q, %v", s, err)
+	}
+}
+
+//
+func TestBitRate_Format_negative(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v float64
+		f string
+		s string
+	}{
+		{-5000000000, "%v", "-5.0 Gbit/s"},
+		{-5000000000, "%s", "-5Gbit/s"},
+		{-5000000000, "% s", "-5 Gbit/s"},
+		{-5000000000, "%.2S", "-4.66Gibit/s"},
+		{-5000000000, "%.1a", "-5.0Gbps"},
+		{-5000000000, "% .1A", "-4.7 Gibps"},
+		{-5000000000, "%# .1s", "-5.0 gigabits per second"},
+		{-5000000000, "%# .1S", "-4.7 gibibits per second"},
+		{-5000000000, "[%12.1s]", "[  -5.0Gbit/s]"},
+		{-5000000000, "[%-12.1s]", "[-5.0Gbit/s  ]"},
+		{-5000000000, "[%012.1s]", "[-005.0Gbit/s]"},
+		{-1, "% .1s", "-1.0 bit/s"},
+		{-1, "%# .1s", "-1.0 bit per second"},
+		{-1000, "%# .1s", "-1.0 kilobit per second"},
+		{-1024, "%# .1S", "-1.0 kibibit per second"},
+		{-0.5, "%s", "-0.5bit/s"},
+		{-999.96, "%.1s", "-1.0kbit/s"},
+		{-999.94, "%.1s", "-999.9bit/s"},
+		{-123456789, "%.3s", "-123.457Mbit/s"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.BitRate(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %f: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func BenchmarkBitRate_AppendFormat(b *testing.B) {
+	v := infounit.BitRate(123456789)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = v.AppendFormat(buf[:0], nil)
+	}
+}
+
+//
+func BenchmarkBitRate_String(b *testing.B) {
+	v := infounit.BitRate(123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = v.String()
+	}
+}
+
+//
+func BenchmarkBitRate_Sprintf(b *testing.B) {
+	v := infounit.BitRate(123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%# .2S", v)
+	}
+}
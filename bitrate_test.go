@@ -262,3 +262,38 @@ func TestParseBitRateBinary(t *testing.T) {
 		}
 	}
 }
+
+//
+func TestBitRate_Round(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, m  float64
+		trunc float64
+		round float64
+	}{
+		{0, 1000, 0, 0},
+		{123456.7, 1000, 123000, 123000},
+		{123500, 1000, 123000, 124000},
+		{-123500, 1000, -123000, -124000},
+		{-123456.7, 1000, -123000, -123000},
+		{987654321, 1 << 20, 941 << 20, 942 << 20},
+		{123456.7, 0, 123456.7, 123456.7},
+		{123456.7, -1000, 123456.7, 123456.7},
+		{123456.7, math.NaN(), 123456.7, 123456.7},
+		{math.Inf(+1), 1000, math.Inf(+1), math.Inf(+1)},
+	}
+
+	for _, c := range tc {
+		x, m := infounit.BitRate(c.x), infounit.BitRate(c.m)
+		if v := x.Truncate(m); v != infounit.BitRate(c.trunc) {
+			t.Errorf(`%f.Truncate(%f): want: %f, got: %f`, c.x, c.m, c.trunc, v)
+		}
+		if v := x.Round(m); v != infounit.BitRate(c.round) {
+			t.Errorf(`%f.Round(%f): want: %f, got: %f`, c.x, c.m, c.round, v)
+		}
+	}
+	if v := infounit.BitRate(math.NaN()).Round(1000); !v.IsNaN() {
+		t.Errorf(`NaN.Round(1000): want: NaN, got: %f`, v)
+	}
+}
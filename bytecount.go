@@ -9,7 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"regexp"
+	"math/bits"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -47,7 +47,47 @@ const (
 // String returns the human-readable string representing the byte count using SI
 // prefix. This implements the Stringer interface in the package fmt.
 func (bc ByteCount) String() string {
-	return fmt.Sprintf("% .1s", bc)
+	var buf [fmtBufSize]byte
+	return string(bc.AppendFormat(buf[:0], nil))
+}
+
+// FormatIn returns the string representation of the byte count in the unit,
+// such as Mebibyte, with prec digits after the decimal point, e.g. "1536.0 MiB"
+// for 1.5 GiB in Mebibyte. Unlike String, the prefix is never selected
+// automatically, so that all the values in a column of a table can be formatted
+// in the same unit. If unit is not Byte or one of the constants with a prefix,
+// ErrInvalidUnit is returned. See Formatter.FormatByteCountIn for the other
+// options such as the long unit names.
+func (bc ByteCount) FormatIn(unit ByteCount, prec int) (string, error) {
+	f := Formatter{Precision: prec, Separator: " "}
+	return f.FormatByteCountIn(bc, unit)
+}
+
+// BestByteCountUnit returns the unit in which the largest of the values is
+// formatted with the prefix system ps and prec digits after the decimal point,
+// e.g. Gigabyte for the SI prefixes if it is 12.5 GB. As with the formatting,
+// the unit is selected after rounding, so it is Megabyte for 999999 bytes with
+// prec 1, as "1.0 MB" rather than "1000.0 kB". The result is passed to FormatIn
+// with the same prec to format all the values in the same unit. It returns Byte
+// if vs is empty.
+func BestByteCountUnit(vs []ByteCount, ps PrefixSystem, prec int) ByteCount {
+	var max ByteCount
+	for _, v := range vs {
+		if max < v {
+			max = v
+		}
+	}
+	f := Formatter{Prefixes: ps, Precision: prec}
+	return ByteCount(f.uintUnit(uint64(max)))
+}
+
+// AppendFormat appends the human-readable string representation of the
+// ByteCount value formatted by f to dst and returns the extended buffer. If f is
+// nil, the default format "% .1s", the same as String, is used. Unlike String
+// and the Printf family functions, this does not allocate as long as dst has
+// enough capacity.
+func (bc ByteCount) AppendFormat(dst []byte, f *Formatter) []byte {
+	return f.orDefault().appendUint(dst, uint64(bc), unitByte)
 }
 
 // GoString returns a string representation of the ByteCount value in Go syntax
@@ -59,10 +99,154 @@ func (bc ByteCount) GoString() string {
 // BitCount returns the value converted to the number of bits. If the number of
 // bits is too large, an ErrOutOfRange will be returned.
 func (bc ByteCount) BitCount() (BitCount, error) {
-	if bc&(0b_111<<61) != 0 {
-		return BitCount(0), ErrOutOfRange
+	v, err := bc.Mul(8)
+	if err != nil {
+		return BitCount(0), err
 	}
-	return BitCount(uint64(bc) << 3), nil
+	return BitCount(v), nil
+}
+
+// Delta returns the signed difference bc-u as a ByteDelta. If the difference
+// exceeds the range of ByteDelta, an ErrOutOfRange will be returned.
+func (bc ByteCount) Delta(u ByteCount) (ByteDelta, error) {
+	if u <= bc {
+		v, err := signInt64(uint64(bc-u), false)
+		return ByteDelta(v), err
+	}
+	v, err := signInt64(uint64(u-bc), true)
+	return ByteDelta(v), err
+}
+
+// AddDelta returns bc+d. If the result is negative or exceeds the range of
+// ByteCount, an ErrOutOfRange will be returned.
+func (bc ByteCount) AddDelta(d ByteDelta) (ByteCount, error) {
+	m := ByteCount(absInt64(int64(d)))
+	switch {
+	case d < 0 && bc < m, 0 < d && ^bc < m:
+		return 0, ErrOutOfRange
+	case d < 0:
+		return bc - m, nil
+	}
+	return bc + m, nil
+}
+
+// Add returns bc+u. If the result exceeds the range of ByteCount, an
+// ErrOutOfRange will be returned instead of a wrapped around value.
+func (bc ByteCount) Add(u ByteCount) (ByteCount, error) {
+	v, carry := bits.Add64(uint64(bc), uint64(u), 0)
+	if carry != 0 {
+		return 0, ErrOutOfRange
+	}
+	return ByteCount(v), nil
+}
+
+// Sub returns bc-u. If u is greater than bc, an ErrOutOfRange will be returned
+// instead of a wrapped around value.
+func (bc ByteCount) Sub(u ByteCount) (ByteCount, error) {
+	if bc < u {
+		return 0, ErrOutOfRange
+	}
+	return bc - u, nil
+}
+
+// Mul returns bc*n. If the result exceeds the range of ByteCount, an
+// ErrOutOfRange will be returned instead of a wrapped around value.
+func (bc ByteCount) Mul(n uint64) (ByteCount, error) {
+	v, err := mulUint64(uint64(bc), n)
+	return ByteCount(v), err
+}
+
+// MulFloat returns bc*f rounded to the nearest integer. Since the calculation
+// is done in float64, the result may not be exact for large values. If the
+// result is negative, NaN, or exceeds the range of ByteCount, an ErrOutOfRange
+// will be returned.
+func (bc ByteCount) MulFloat(f float64) (ByteCount, error) {
+	v := math.Round(float64(bc) * f)
+	if !(0 <= v && v < 0x1p64) {
+		return 0, ErrOutOfRange
+	}
+	return ByteCount(v), nil
+}
+
+// Div returns bc/n, truncated toward zero. If n is zero, an ErrDivZero will be
+// returned.
+func (bc ByteCount) Div(n uint64) (ByteCount, error) {
+	if n == 0 {
+		return 0, ErrDivZero
+	}
+	return bc / ByteCount(n), nil
+}
+
+// SaturatingAdd returns bc+u. If the result exceeds the range of ByteCount, the
+// maximum value of ByteCount is returned.
+func (bc ByteCount) SaturatingAdd(u ByteCount) ByteCount {
+	v, err := bc.Add(u)
+	if err != nil {
+		return math.MaxUint64
+	}
+	return v
+}
+
+// SaturatingSub returns bc-u. If u is greater than bc, zero is returned.
+func (bc ByteCount) SaturatingSub(u ByteCount) ByteCount {
+	v, err := bc.Sub(u)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// Truncate returns the result of rounding bc toward zero to a multiple of m.
+// If m is zero, Truncate returns bc unchanged.
+func (bc ByteCount) Truncate(m ByteCount) ByteCount {
+	if m == 0 {
+		return bc
+	}
+	return bc - bc%m
+}
+
+// Round returns the result of rounding bc to the nearest multiple of m. The
+// rounding behavior for halfway values is to round away from zero. If the
+// result exceeds the maximum value that can be stored in a ByteCount, Round
+// returns the maximum value. If m is zero, Round returns bc unchanged.
+func (bc ByteCount) Round(m ByteCount) ByteCount {
+	if m == 0 {
+		return bc
+	}
+	r := bc % m
+	if r < m-r {
+		return bc - r
+	}
+	return (bc - r).SaturatingAdd(m)
+}
+
+// AlignUp returns the smallest multiple of m that is greater than or equal to
+// bc. For example, bc.AlignUp(4 * infounit.Kibibyte) returns the size of the 4
+// KiB pages required to store bc bytes. If the result exceeds the range of
+// ByteCount, an ErrOutOfRange will be returned. If m is zero, AlignUp returns
+// bc unchanged.
+func (bc ByteCount) AlignUp(m ByteCount) (ByteCount, error) {
+	if m == 0 {
+		return bc, nil
+	}
+	r := bc % m
+	if r == 0 {
+		return bc, nil
+	}
+	return (bc - r).Add(m)
+}
+
+// AlignDown returns the largest multiple of m that is less than or equal to
+// bc. This is the same as Truncate. If m is zero, AlignDown returns bc
+// unchanged.
+func (bc ByteCount) AlignDown(m ByteCount) ByteCount {
+	return bc.Truncate(m)
+}
+
+// IsAligned returns whether bc is a multiple of m. If m is zero, IsAligned
+// returns true.
+func (bc ByteCount) IsAligned(m ByteCount) bool {
+	return m == 0 || bc%m == 0
 }
 
 // Convert converts the byte count to a float value in the specified unit. If
@@ -106,6 +290,31 @@ func (bc ByteCount) CalcBitRate(duration time.Duration) BitRate {
 	return BitRate(float64(bc) * 8 / duration.Seconds())
 }
 
+// CalcTimeByteRate calculates the duration it takes to transfer or process the
+// number of bytes at the specified byte rate.
+func (bc ByteCount) CalcTimeByteRate(rate ByteRate) (time.Duration, error) {
+	if rate == 0 {
+		return 0, ErrDivZeroByteRate
+	}
+	ns := float64(bc) * float64(time.Second) / float64(rate)
+	if ns < float64(math.MinInt64) || float64(math.MaxInt64) < ns {
+		return 0, ErrOutOfRange
+	}
+	return time.Duration(ns), nil
+}
+
+// CalcByteRate calculates the byte rate when the number of bytes is
+// transferred or processed in the specified duration.
+func (bc ByteCount) CalcByteRate(duration time.Duration) ByteRate {
+	if duration == 0 {
+		if bc == 0 {
+			return 0
+		}
+		return ByteRate(math.Inf(+1))
+	}
+	return ByteRate(float64(bc) / duration.Seconds())
+}
+
 // AtomicAddByteCount atomically adds delta to *addr and returns the new value.
 // A wrapper function for the package sync/atomic.
 func AtomicAddByteCount(addr *ByteCount, delta ByteCount) ByteCount {
@@ -168,17 +377,25 @@ func (bc *ByteCount) UnmarshalBinary(data []byte) error {
 // the result. This implements the TextMarshaler interface in the
 // package encoding.
 func (bc *ByteCount) MarshalText() ([]byte, error) {
-	return ([]byte)(fmt.Sprintf("%d B", uint64(AtomicLoadByteCount(bc)))), nil
+	return bc.AppendText(make([]byte, 0, 32))
+}
+
+// AppendText appends the UTF-8-encoded text form of the ByteCount value, the same
+// as MarshalText, to dst and returns the extended buffer. This implements the
+// TextAppender interface in the package encoding.
+func (bc *ByteCount) AppendText(dst []byte) ([]byte, error) {
+	dst = strconv.AppendUint(dst, uint64(AtomicLoadByteCount(bc)), 10)
+	return append(dst, " B"...), nil
 }
 
 // UnmarshalText decodes the ByteCount value from a UTF-8-encoded text form.
 // This implements the TextUnmarshaler interface in the package encoding.
 func (bc *ByteCount) UnmarshalText(text []byte) error {
-	var val ByteCount
-	if _, err := fmt.Sscanf(string(text), "%s", &val); err != nil {
+	val, err := unmarshalParser.ParseByteCount(string(text))
+	if err != nil {
 		return err
 	}
-	AtomicStoreByteCount(bc, val)
+	AtomicStoreByteCount(bc, ByteCount(val))
 	return nil
 }
 
@@ -189,6 +406,15 @@ func (bc *ByteCount) MarshalYAML() (interface{}, error) {
 
 // UnmarshalYAML decodes the ByteCount value from a YAML field.
 func (bc *ByteCount) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if unmarshal(&s) == nil {
+		// checked first, since the yaml package silently wraps around
+		// numbers that are too large to be decoded into uint64
+		if err := checkUintExpr(s); err != nil {
+			return rangeError("ByteCount", s, false)
+		}
+	}
+
 	var u64 uint64
 	if unmarshal(&u64) == nil {
 		AtomicStoreByteCount(bc, ByteCount(u64))
@@ -196,18 +422,17 @@ func (bc *ByteCount) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return nil
 	}
 
-	var s string
 	if unmarshal(&s) == nil {
-		v, err := ParseByteCount(s)
+		v, err := unmarshalParser.ParseByteCount(s)
 		if err != nil {
-			return fmt.Errorf("%q: %w: %v", s, ErrMalformedRepresentation, err)
+			return err
 		}
 		AtomicStoreByteCount(bc, v)
 
 		return nil
 	}
 
-	return fmt.Errorf("%w: unexpected type", ErrMalformedRepresentation)
+	return typeError("ByteCount", yamlInput(unmarshal))
 }
 
 // IsZero returns whether the ByteCount value is zero.
@@ -233,25 +458,26 @@ func (bc *ByteCount) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
+	if err := checkUintExpr(string(b)); err != nil {
+		return rangeError("ByteCount", string(b), false)
+	}
+
 	var s string
 	if json.Unmarshal(b, &s) == nil {
-		v, err := ParseByteCount(s)
+		v, err := unmarshalParser.ParseByteCount(s)
 		if err != nil {
-			return fmt.Errorf("%q: %w: %v", s, ErrMalformedRepresentation, err)
+			return err
 		}
 		AtomicStoreByteCount(bc, v)
 
 		return nil
 	}
 
-	return fmt.Errorf("%w: unexpected type", ErrMalformedRepresentation)
+	return typeError("ByteCount", string(b))
 }
 
 //
-const (
-	unitByteFull = "byte"
-	unitByteAbbr = "B"
-)
+var unitByte = &unitName{abbr: "B", full: "byte"}
 
 // Format implements the Formatter interface in the package fmt to format
 // ByteCount values. This gives the ability to format ByteCount values in
@@ -259,10 +485,12 @@ const (
 // package fmt; fmt.Printf, fmt.Fprintf, fmt.Sprintf, fmt.Errorf, and functions
 // derived from them.
 //
-// For ByteCount type, two custom 'verbs' are implemented:
+// For ByteCount type, four custom 'verbs' are implemented:
 //
 // 	%s	human-readable format with SI prefix
 // 	%S	human-readable format with binary prefix
+// 	%h	human-readable format with SI prefix and significant digits
+// 	%H	human-readable format with binary prefix and significant digits
 //
 // Width and precision can be specified to both %s and %S:
 //
@@ -275,6 +503,17 @@ const (
 // Regardless of the precision specified, while the unit is byte,
 // no decimal parts are printed.
 //
+// With a precision, the value is formatted exactly with integer arithmetic, and
+// the last digit is rounded half to even. The prefix is selected after
+// rounding, so "%.1s" prints 999960 as "1.0MB", not as "1000.0kB". With the
+// default precision, the shortest representation of the value as float64 is
+// printed, e.g. "941.900559425354" rather than all the 23 decimal places.
+//
+// The verbs %h and %H are the same as %s and %S, except that the precision is
+// the number of significant digits, which is 3 by default, like "ls -h"; e.g.
+// "1.23GB", "12.3GB" and "123GB". The prefix is selected after rounding, so
+// "%h" prints 999600 as "1.00MB", not as "1000kB".
+//
 // The following flags are also available for both %s and %S:
 //
 // 	' '	(space) print a space between digits and unit; e.g. "12.3 kB"
@@ -299,39 +538,18 @@ const (
 // See the package fmt documentation for details.
 func (bc ByteCount) Format(s fmt.State, verb rune) {
 	switch verb {
-	case 's', 'S':
-		tFmt := "%"
-		if s.Flag(int('-')) {
-			tFmt += "-"
-		}
-		if s.Flag(int('0')) {
-			tFmt += "0"
-		}
-		if wid, ok := s.Width(); ok {
-			tFmt += strconv.FormatInt(int64(wid), 10)
-		}
-		tFmt += "s"
-		prec, ok := s.Precision()
-		if !ok {
-			prec = -1
-		}
-		full, space := s.Flag(int('#')), s.Flag(int(' '))
-		var pfx *prefix
-		switch verb {
-		case 's':
-			pfx = siPrefix
-		case 'S':
-			pfx = binPrefix
-		}
-		expr := pfx.formatUint(uint64(bc), prec, full, space, unitByteAbbr, unitByteFull)
-		fmt.Fprintf(s, tFmt, expr)
+	case 's', 'S', 'h', 'H':
+		f := verbFormatter(s, verb)
+		var buf [fmtBufSize]byte
+		writePadded(s, bc.AppendFormat(buf[:0], &f))
 
 	case 'v':
 		if s.Flag(int('#')) {
 			fmt.Fprint(s, bc.GoString())
 			break
 		}
-		fmt.Fprint(s, bc.String())
+		var buf [fmtBufSize]byte
+		_, _ = s.Write(bc.AppendFormat(buf[:0], nil))
 
 	case 'b', 'd', 'o', 'x', 'X':
 		tFmt := "%"
@@ -354,46 +572,27 @@ func (bc ByteCount) Format(s fmt.State, verb rune) {
 	}
 }
 
-//
-type byteCountScanUnitEnt struct {
-	re  *regexp.Regexp
-	bcs uint64
-	bcb uint64
-}
-
-var (
-	byteCountScanTokenRe []*regexp.Regexp
-	byteCountScanUnitRe  []byteCountScanUnitEnt
-)
-
-//
-func init() {
-	ent := func(s string, bcs, bcb ByteCount) byteCountScanUnitEnt {
-		return byteCountScanUnitEnt{
-			re:  regexp.MustCompile(`(?i)^` + s + `$`),
-			bcs: uint64(bcs),
-			bcb: uint64(bcb),
-		}
-	}
-	byteCountScanUnitRe = []byteCountScanUnitEnt{
-		ent("b(ytes?)?", Byte, Byte),
-		ent("kb|kilobytes?", Kilobyte, Kibibyte),
-		ent("mb|megabytes?", Megabyte, Mebibyte),
-		ent("gb|gigabytes?", Gigabyte, Gibibyte),
-		ent("tb|terabytes?", Terabyte, Tebibyte),
-		ent("pb|petabytes?", Petabyte, Pebibyte),
-		ent("eb|exabytes?", Exabyte, Exbibyte),
-		ent("kib|kibibytes?", Kibibyte, Kibibyte),
-		ent("mib|mebibytes?", Mebibyte, Mebibyte),
-		ent("gib|gibibytes?", Gibibyte, Gibibyte),
-		ent("tib|tebibytes?", Tebibyte, Tebibyte),
-		ent("pib|pebibytes?", Pebibyte, Pebibyte),
-		ent("eib|exbibytes?", Exbibyte, Exbibyte),
-	}
-	byteCountScanTokenRe = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)^(([0-9]*)(\.[0-9]+)?)([a-z]*)$`), // 1:num, 2:int, 3:frac, 4:unit
-		regexp.MustCompile(`(?i)^([a-z]*)$`),                      // 1:unit
-	}
+// byteCountUnits is the table of the unit suffixes of ByteCount.
+var byteCountUnits = []scanUnit{
+	{"b", -1, false, false}, {"byte", -1, false, true}, {"bytes", -1, false, true},
+	{"kb", 0, false, false}, {"kilobyte", 0, false, true}, {"kilobytes", 0, false, true},
+	{"mb", 1, false, false}, {"megabyte", 1, false, true}, {"megabytes", 1, false, true},
+	{"gb", 2, false, false}, {"gigabyte", 2, false, true}, {"gigabytes", 2, false, true},
+	{"tb", 3, false, false}, {"terabyte", 3, false, true}, {"terabytes", 3, false, true},
+	{"pb", 4, false, false}, {"petabyte", 4, false, true}, {"petabytes", 4, false, true},
+	{"eb", 5, false, false}, {"exabyte", 5, false, true}, {"exabytes", 5, false, true},
+	{"kib", 0, true, false}, {"kibibyte", 0, true, true}, {"kibibytes", 0, true, true},
+	{"mib", 1, true, false}, {"mebibyte", 1, true, true}, {"mebibytes", 1, true, true},
+	{"gib", 2, true, false}, {"gibibyte", 2, true, true}, {"gibibytes", 2, true, true},
+	{"tib", 3, true, false}, {"tebibyte", 3, true, true}, {"tebibytes", 3, true, true},
+	{"pib", 4, true, false}, {"pebibyte", 4, true, true}, {"pebibytes", 4, true, true},
+	{"eib", 5, true, false}, {"exbibyte", 5, true, true}, {"exbibytes", 5, true, true},
+
+	// misspelled names accepted by the earlier versions
+	{"mibibyte", 1, true, true}, {"mibibytes", 1, true, true},
+	{"tibibyte", 3, true, true}, {"tibibytes", 3, true, true},
+	{"pibibyte", 4, true, true}, {"pibibytes", 4, true, true},
+	{"eibibyte", 5, true, true}, {"eibibytes", 5, true, true},
 }
 
 // Scan implements the Scanner interface in the package fmt to scan ByteCount
@@ -421,6 +620,14 @@ func init() {
 // They assume that if the token consists only of digits, it is the number of
 // bytes.
 //
+// If the scanned value exceeds the range of ByteCount, an error wrapping
+// ErrOutOfRange is returned instead of a wrapped around value.
+//
+// Decimal numbers with a unit suffix are converted exactly, without being
+// rounded to float64. If the result is not a whole number of bytes, it is
+// rounded to the nearest integer, with halves rounded away from zero. Use
+// ParseByteCountRound for the other rounding modes.
+//
 // The following verbs are compatible with uint64 and scans integers without a
 // unit suffix. If it is clear that there is absolutely no unit suffix in the
 // input, the use of these is recommended:
@@ -432,7 +639,6 @@ func init() {
 //
 // See the package fmt documentation for details.
 func (bc *ByteCount) Scan(state fmt.ScanState, verb rune) error {
-	// fmt.Printf("**scan[%c]**\n", verb)
 	switch verb {
 	case 'b', 'd', 'o', 'x', 'X':
 		tFmt := "%"
@@ -446,118 +652,12 @@ func (bc *ByteCount) Scan(state fmt.ScanState, verb rune) error {
 		}
 
 	case 's', 'S', 'u', 'U':
-		token1Bytes, err := state.Token(true, nil)
-		switch {
-		case err != nil:
-			return fmt.Errorf("%%%c: %w", verb, err)
-		case len(token1Bytes) < 1:
-			return fmt.Errorf("%%%c: no input", verb)
-		}
-		token1Str := string(token1Bytes)
-		token1 := byteCountScanTokenRe[0].FindStringSubmatch(token1Str)
-		if token1 == nil {
-			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
-		}
-		// fmt.Printf("[SCAN] TOKEN1: %+v\n", token1)
-
-		numExpr := token1[1]
-		isInt := 0 < len(token1[2]) && len(token1[3]) < 1
-		unitExpr := token1[4]
-
-		if len(numExpr) < 1 {
-			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
-		}
-
-		if unitExpr == "" { // no unit suffix within the first token
-			switch verb {
-			case 'u', 'U':
-				// does not read the second token, assumed to be bytes
-				unitExpr = "b"
-			case 's', 'S':
-				sp, n, err := state.ReadRune()
-				if err != nil {
-					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
-				}
-				if n != 1 {
-					return fmt.Errorf("%%%c: no unit suffix", verb)
-				}
-				if sp != ' ' {
-					return fmt.Errorf("%%%c: no space after digits: [%c]", verb, sp)
-				}
-				token2Bytes, err := state.Token(false, nil)
-				if err != nil {
-					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
-				}
-				if len(token2Bytes) < 1 {
-					return fmt.Errorf("%%%c: no unit suffix", verb)
-				}
-				token2Str := string(token2Bytes)
-				token2 := byteCountScanTokenRe[1].FindStringSubmatch(token2Str)
-				if token2 == nil {
-					return fmt.Errorf("%%%c: invalid unit expr: %s", verb, token2Str)
-				}
-				// fmt.Printf("[SCAN] TOKEN2: %+v\n", token2)
-
-				unitExpr = token2[1]
-				if unitExpr == "" {
-					return fmt.Errorf("%%%c: no unit suffix", verb)
-				}
-			}
-		}
-
-		// fmt.Printf("[SCAN] LAST [%s] [%s]\n", numExpr, unitExpr)
-
-		ptr := (*uint64)(bc)
-
-		// unit is byte
-		if byteCountScanUnitRe[0].re.MatchString(unitExpr) {
-			if !isInt {
-				return fmt.Errorf("%%%c: non-integer byte count: %s", verb, numExpr)
-			}
-			numVal, err := strconv.ParseUint(numExpr, 10, 64)
-			if err != nil {
-				return fmt.Errorf("%%%c: invalid byte count: %s: %w", verb, numExpr, err)
-			}
-			*ptr = numVal
-			return nil
-		}
-
-		if isInt { // integer
-			numVal, err := strconv.ParseUint(numExpr, 10, 64)
-			if err != nil {
-				return fmt.Errorf("%%%c: invalid byte count: %s: %w", verb, numExpr, err)
-			}
-			for _, unit := range byteCountScanUnitRe {
-				if unit.re.MatchString(unitExpr) {
-					switch verb {
-					case 's', 'u':
-						*ptr = numVal * unit.bcs
-					case 'S', 'U':
-						*ptr = numVal * unit.bcb
-					}
-					return nil
-				}
-			}
-			return fmt.Errorf("%%%c: unknown unit: %s", verb, unitExpr)
-		}
-
-		// float
-		numVal, err := strconv.ParseFloat(numExpr, 64)
+		lx := lexer{state: state}
+		v, err := lx.scanUint(verb, RoundNearest, byteCountUnits, "byte count")
 		if err != nil {
-			return fmt.Errorf("%%%c: invalid byte count: %s: %w", verb, numExpr, err)
-		}
-		for _, unit := range byteCountScanUnitRe {
-			if unit.re.MatchString(unitExpr) {
-				switch verb {
-				case 's', 'u':
-					*ptr = uint64(math.Round(numVal * float64(unit.bcs)))
-				case 'S', 'U':
-					*ptr = uint64(math.Round(numVal * float64(unit.bcb)))
-				}
-				return nil
-			}
+			return err
 		}
-		return fmt.Errorf("%%%c: unknown unit: %s", verb, unitExpr)
+		*bc = ByteCount(v)
 
 	default:
 		return fmt.Errorf("unknown verb for ByteCount: %%%c", verb)
@@ -568,21 +668,36 @@ func (bc *ByteCount) Scan(state fmt.ScanState, verb rune) error {
 // ParseByteCount converts a human-readable string representation into a
 // ByteCount value. The human-readable string is a decimal number with a unit
 // suffix. SI and binary prefixes are correctly recognized.
+// If the value exceeds the range of ByteCount, the returned error wraps
+// ErrOutOfRange.
 func ParseByteCount(s string) (ByteCount, error) {
-	var v ByteCount
-	if _, err := fmt.Sscanf(s, "%s", &v); err != nil {
-		return 0, fmt.Errorf("invalid byte count: %s: %w", s, err)
+	v, err := parseUint(s, "ByteCount", 's', RoundNearest, byteCountUnits, "byte count")
+	if err != nil {
+		return 0, err
 	}
-	return v, nil
+	return ByteCount(v), nil
 }
 
 // ParseByteCountBinary is the same as ParseByteCount except that it treats the
 // SI prefixes as binary prefixes. That is, it parses "100 kB" as 100 KiB
 // (=102400 B).
 func ParseByteCountBinary(s string) (ByteCount, error) {
-	var v ByteCount
-	if _, err := fmt.Sscanf(s, "%S", &v); err != nil {
-		return 0, fmt.Errorf("invalid byte count: %s: %w", s, err)
+	v, err := parseUint(s, "ByteCount", 'S', RoundNearest, byteCountUnits, "byte count")
+	if err != nil {
+		return 0, err
+	}
+	return ByteCount(v), nil
+}
+
+// ParseByteCountRound is the same as ParseByteCount except that it uses the
+// specified rounding mode when the value is not a whole number of bytes, e.g.
+// "0.3 kibibyte". The conversion is exact; the decimal number is never
+// rounded before it is multiplied by the unit. With RoundExact, the returned
+// error wraps ErrFractional if the value is not a whole number of bytes.
+func ParseByteCountRound(s string, mode RoundingMode) (ByteCount, error) {
+	v, err := parseUint(s, "ByteCount", 's', mode, byteCountUnits, "byte count")
+	if err != nil {
+		return 0, err
 	}
-	return v, nil
+	return ByteCount(v), nil
 }
@@ -166,3 +166,66 @@ func ExampleByteCount_Scan_sscanf() {
 	// work.xlsx 240.2 kB
 	// huge.zip 999.0 TB
 }
+
+//
+func ExampleParseByteCountRound() {
+	for _, mode := range []infounit.RoundingMode{
+		infounit.RoundNearest,
+		infounit.RoundFloor,
+		infounit.RoundCeil,
+		infounit.RoundExact,
+	} {
+		v, err := infounit.ParseByteCountRound("0.3 GiB", mode)
+		if err != nil {
+			fmt.Println(mode, err)
+			continue
+		}
+		fmt.Println(mode, uint64(v))
+	}
+	// Output:
+	// RoundNearest 322122547
+	// RoundFloor 322122547
+	// RoundCeil 322122548
+	// RoundExact invalid byte count: 0.3 GiB: %s: 0.3 GiB: fractional value
+}
+
+//
+func ExampleByteCount_AlignUp() {
+	const page = 4 * infounit.Kibibyte
+
+	for _, size := range []infounit.ByteCount{0, 1, 4096, 10000} {
+		aligned, err := size.AlignUp(page)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(uint64(size), uint64(aligned), size.IsAligned(page))
+	}
+	fmt.Printf("% S\n", (infounit.Mebibyte * 3 / 2).Round(infounit.Mebibyte))
+	// Output:
+	// 0 0 true
+	// 1 4096 false
+	// 4096 4096 true
+	// 10000 12288 false
+	// 2 MiB
+}
+
+//
+func ExampleBestByteCountUnit() {
+	sizes := []infounit.ByteCount{
+		infounit.Mebibyte * 3,
+		infounit.Gibibyte + infounit.Mebibyte*512,
+		infounit.Kibibyte * 200,
+	}
+	unit := infounit.BestByteCountUnit(sizes, infounit.BinaryPrefixes, 2)
+	for _, v := range sizes {
+		s, err := v.FormatIn(unit, 2)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("[%10s]\n", s)
+	}
+	// Output:
+	// [  0.00 GiB]
+	// [  1.50 GiB]
+	// [  0.00 GiB]
+}
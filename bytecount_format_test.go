@@ -131,3 +131,151 @@ func TestByteCount_Format_2(t *testing.T) {
 		}
 	}
 }
+
+//
+func TestByteCount_Format_exact(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v uint64
+		f string
+		s string
+	}{
+		{18446744073709551615, "%s", "18.446744073709553EB"},
+		{18446744073709551615, "%.18s", "18.446744073709551615EB"},
+		{18446744073709551615, "%.20s", "18.44674407370955161500EB"},
+		{18446744073709551615, "%.17s", "18.44674407370955162EB"},
+		{18446744073709551615, "%.3S", "16.000EiB"},
+		{18446744073709551615, "%.18S", "15.999999999999999999EiB"},
+		{18446744073709551615, "%.19S", "15.9999999999999999991EiB"},
+		{9007199254740993, "%s", "9.007199254740993PB"},
+		{9007199254740993, "%.15s", "9.007199254740993PB"},
+		{9007199254740993, "%.14s", "9.00719925474099PB"},
+		{1152921504606846977, "%S", "1EiB"},
+		{1025, "%S", "1.0009765625KiB"},
+		{1152921504606846977, "%.60S", "1.000000000000000000867361737988403547205962240695953369140625EiB"},
+		{1500, "%.0s", "2kB"},
+		{2500, "%.0s", "2kB"},
+		{2501, "%.0s", "3kB"},
+		{1005, "%.2s", "1.00kB"},
+		{1015, "%.2s", "1.02kB"},
+		{1536, "%.0S", "2KiB"},
+		{2560, "%.0S", "2KiB"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.ByteCount(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %d: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestByteCount_Format_carry(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v uint64
+		f string
+		s string
+	}{
+		{999, "%.1s", "999B"},
+		{1023, "%.1S", "1023B"},
+		{999949, "%.1s", "999.9kB"},
+		{999950, "%.1s", "1.0MB"},
+		{999960, "%.1s", "1.0MB"},
+		{999960, "%# .1s", "1.0 megabytes"},
+		{999960, "%.1S", "976.5KiB"},
+		{999499, "%.0s", "999kB"},
+		{999500, "%.0s", "1MB"},
+		{1048535, "%.1S", "1.0MiB"},
+		{1048575, "%.2S", "1.00MiB"},
+		{1048575, "%.3S", "1023.999KiB"},
+		{999999999999999999, "%.1s", "1.0EB"},
+		{1152921504606846975, "%.1S", "1.0EiB"},
+		{18446744073709551615, "%.0S", "16EiB"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.ByteCount(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %d: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestByteCount_Format_significant(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v uint64
+		f string
+		s string
+	}{
+		{5, "%h", "5B"},
+		{999, "%h", "999B"},
+		{1234, "%h", "1.23kB"},
+		{12345, "%h", "12.3kB"},
+		{123456, "%h", "123kB"},
+		{1234567890, "% h", "1.23 GB"},
+		{1234567890, "%.2h", "1.2GB"},
+		{1234567890, "%.5h", "1.2346GB"},
+		{1234567890, "%.0h", "1GB"},
+		{1234567890, "%8h", "  1.23GB"},
+		{1234567890, "%# h", "1.23 gigabytes"},
+		{999600, "%h", "1.00MB"},
+		{999400, "%h", "999kB"},
+		{99960, "%h", "100kB"},
+		{99940, "%h", "99.9kB"},
+		{9996, "%h", "10.0kB"},
+		{1023 * 1024, "%H", "1023KiB"},
+		{1048000, "%H", "1023KiB"},
+		{1048575, "%H", "1.00MiB"},
+		{1536 * 1024, "%H", "1.50MiB"},
+		{18446744073709551615, "%h", "18.4EB"},
+		{18446744073709551615, "%H", "16.0EiB"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.ByteCount(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %d: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+	if s := fmt.Sprintf("%+h", infounit.ByteDelta(-1234567)); s != "-1.23MB" {
+		t.Errorf("ByteDelta: unexpected result: %q", s)
+	}
+	if s := fmt.Sprintf("%h", infounit.BitCount(45678)); s != "45.7kbit" {
+		t.Errorf("BitCount: unexpected result: %q", s)
+	}
+}
+
+//
+func BenchmarkByteCount_AppendFormat(b *testing.B) {
+	v := infounit.ByteCount(123456789)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = v.AppendFormat(buf[:0], nil)
+	}
+}
+
+//
+func BenchmarkByteCount_String(b *testing.B) {
+	v := infounit.ByteCount(123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = v.String()
+	}
+}
+
+//
+func BenchmarkByteCount_Sprintf(b *testing.B) {
+	v := infounit.ByteCount(123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%# .2S", v)
+	}
+}
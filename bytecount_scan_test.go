@@ -109,7 +109,7 @@ func TestByteCount_Scan_1(t *testing.T) {
 		{"611 petabyte", "%s", infounit.Petabyte * 611, ""},
 		{"611 petaBYTes", "%S", infounit.Pebibyte * 611, ""},
 		{"18446 PB", "%s", infounit.Petabyte * 18446, ""},
-		{"18.446 EB", "%s", infounit.ByteCount(float64(infounit.Exabyte) * 18.446), ""},
+		{"18.446 EB", "%s", infounit.Petabyte * 18446, ""},
 
 		{"11EB", "%s", infounit.Exabyte * 11, ""},
 		{"11eb", "%S", infounit.Exbibyte * 11, ""},
@@ -195,6 +195,15 @@ func TestByteCount_Scan_1(t *testing.T) {
 		{"1.21 jigowatts", "%u", 0, "%u: non-integer byte count: 1.21"}, // %u does not read "jigowatts"
 		{"1.21 jigowatts", "%U", 0, "%U: non-integer byte count: 1.21"}, // %U does not read "jigowatts"
 
+		{"18446744073709551616 B", "%s", 0, "%s: invalid byte count: 18446744073709551616: out of range"},
+		{"18446744073709551616", "%u", 0, "%u: invalid byte count: 18446744073709551616: out of range"},
+		{"20 EB", "%s", 0, "%s: 20 EB: out of range"},
+		{"16 EiB", "%s", 0, "%s: 16 EiB: out of range"},
+		{"16EB", "%U", 0, "%U: 16EB: out of range"},
+		{"99999999999 TB", "%s", 0, "%s: 99999999999 TB: out of range"},
+		{"18.5 EB", "%s", 0, "%s: 18.5 EB: out of range"},
+		{"16.0001 EiB", "%s", 0, "%s: 16.0001 EiB: out of range"},
+
 		{"0", "%d", infounit.Byte * 0, ""},
 		{"1", "%d", infounit.Byte * 1, ""},
 		{"18446744073709551615", "%d", infounit.Byte * 18446744073709551615, ""},
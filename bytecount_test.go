@@ -5,6 +5,7 @@
 package infounit_test
 
 import (
+	"errors"
 	"math"
 	"testing"
 	"time"
@@ -159,6 +160,63 @@ func TestByteCount_CalcBitRate(t *testing.T) {
 	}
 }
 
+//
+func TestByteCount_CalcTimeByteRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		b   infounit.ByteCount
+		r   infounit.ByteRate
+		t   time.Duration
+		err error
+	}{
+		{0, infounit.BytePerSecond * 1, time.Second * 0, nil},
+		{1000, infounit.KilobytePerSecond * 1, time.Second * 1, nil},
+		{infounit.Megabyte, infounit.KilobytePerSecond, time.Second * 1000, nil},
+		{infounit.Mebibyte * 3, infounit.MebibytePerSecond * 2, time.Millisecond * 1500, nil},
+		{1, 0, 0, infounit.ErrDivZeroByteRate},
+		{infounit.Exabyte * 10, infounit.BytePerSecond, 0, infounit.ErrOutOfRange},
+	}
+
+	for _, c := range tc {
+		tm, err := c.b.CalcTimeByteRate(c.r)
+		if err != c.err {
+			t.Errorf(`%v in %v: want(err): %s, got(err): %s`, c.b, c.r, c.err, err)
+		}
+		if tm != c.t {
+			t.Errorf(`%v in %v: want: %s, got: %s`, c.b, c.r, c.t, tm)
+		}
+	}
+}
+
+//
+func TestByteCount_CalcByteRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		b infounit.ByteCount
+		t time.Duration
+		r infounit.ByteRate
+	}{
+		{0, time.Second, 0},
+		{1000, time.Second, 1000},
+		{infounit.Megabyte, time.Second * 1000, infounit.KilobytePerSecond},
+		{infounit.Byte, time.Second * 10, infounit.BytePerSecond * 0.1},
+		{1000, 0, infounit.ByteRate(math.Inf(+1))},
+		{0, 0, 0},
+	}
+
+	for _, c := range tc {
+		rate := c.b.CalcByteRate(c.t)
+		switch {
+		case c.r.IsInf(+1) && !rate.IsInf(+1):
+			t.Errorf(`%v in %v: want: %v, got: %v`, c.b, c.t, c.r, rate)
+		case rate != c.r:
+			t.Errorf(`%v in %v: want: %v, got: %v`, c.b, c.t, c.r, rate)
+		}
+	}
+}
+
 //
 func TestParseByteCount(t *testing.T) {
 	t.Parallel()
@@ -220,3 +278,296 @@ func TestParseByteCountBinary(t *testing.T) {
 		}
 	}
 }
+
+//
+func TestParseByteCount_outOfRange(t *testing.T) {
+	t.Parallel()
+
+	tc := []string{
+		"18446744073709551616 B",
+		"20 EB",
+		"16 EiB",
+		"99999999999 TB",
+		"18.5 EB",
+		"100000000000000 megabytes",
+	}
+
+	for _, s := range tc {
+		v, err := infounit.ParseByteCount(s)
+		if !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf(`%s: ErrOutOfRange expected, got: %v, %v`, s, v, err)
+		}
+	}
+}
+
+//
+func TestParseByteCountRound(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s    string
+		mode infounit.RoundingMode
+		v    infounit.ByteCount
+		err  error
+	}{
+		{"1.1 EB", infounit.RoundNearest, 1100000000000000000, nil},
+		{"1.1 EB", infounit.RoundExact, 1100000000000000000, nil},
+		{"12.000 kB", infounit.RoundExact, 12000, nil},
+		{"0.3 GiB", infounit.RoundNearest, 322122547, nil},
+		{"0.3 GiB", infounit.RoundFloor, 322122547, nil},
+		{"0.3 GiB", infounit.RoundCeil, 322122548, nil},
+		{"0.3 GiB", infounit.RoundExact, 0, infounit.ErrFractional},
+		{"1.0005 kB", infounit.RoundNearest, 1001, nil},
+		{"1.0005 kB", infounit.RoundFloor, 1000, nil},
+		{"1.0005 kB", infounit.RoundCeil, 1001, nil},
+		{"1.00049 kB", infounit.RoundNearest, 1000, nil},
+		{"15.999999999999999999 EiB", infounit.RoundNearest, 18446744073709551615, nil},
+		{"15.999999999999999999 EiB", infounit.RoundFloor, 18446744073709551614, nil},
+		{"15.999999999999999999 EiB", infounit.RoundCeil, 18446744073709551615, nil},
+		{"18.446744073709551615 EB", infounit.RoundExact, 18446744073709551615, nil},
+		{"18.446744073709551616 EB", infounit.RoundFloor, 0, infounit.ErrOutOfRange},
+		{"0.0000000000000000000001 EB", infounit.RoundNearest, 0, nil},
+		{"0.0000000000000000000001 EB", infounit.RoundFloor, 0, nil},
+		{"0.0000000000000000000001 EB", infounit.RoundCeil, 1, nil},
+		{"0.0000000000000000000001 EB", infounit.RoundExact, 0, infounit.ErrFractional},
+		{"0.0000000000000000005 EB", infounit.RoundNearest, 1, nil},
+		{"0.00000000000000000049999999 EB", infounit.RoundNearest, 0, nil},
+		{"16 EiB", infounit.RoundFloor, 0, infounit.ErrOutOfRange},
+		{"0.0661076664518200065999999 EiB", infounit.RoundFloor, 76216950271679903, nil},
+		{"0.16391053511994274151528 EiB", infounit.RoundNearest, 188975980771397819, nil},
+		{"0.000000000000000000867361737988403547205962240695953369140625 EiB", infounit.RoundExact, 1, nil},
+		{"3.000000000000000000867361737988403547205962240695953369140625 EiB", infounit.RoundExact, 3*1152921504606846976 + 1, nil},
+		{"0.000000000000000000867361737988403547205962240695953369140626 EiB", infounit.RoundExact, 0, infounit.ErrFractional},
+		{"0.000000000000000000867361737988403547205962240695953369140626 EiB", infounit.RoundCeil, 2, nil},
+		{"0.000000000000000000433680868994201773602981120347976684570312 EiB", infounit.RoundNearest, 0, nil},
+		{"0.000000000000000000433680868994201773602981120347976684570313 EiB", infounit.RoundNearest, 1, nil},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.ParseByteCountRound(c.s, c.mode)
+		switch {
+		case c.err != nil && !errors.Is(err, c.err):
+			t.Errorf(`%s, %v: want(err): %v, got: %v, %v`, c.s, c.mode, c.err, v, err)
+		case c.err == nil && err != nil:
+			t.Errorf(`%s, %v: unexpected error: %v`, c.s, c.mode, err)
+		case v != c.v:
+			t.Errorf(`%s, %v: want: %d, got: %d`, c.s, c.mode, c.v, v)
+		}
+	}
+}
+
+//
+func TestByteCount_Add(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, y uint64
+		v    uint64
+		sat  uint64
+		e    bool
+	}{
+		{0, 0, 0, 0, false},
+		{1000, 24, 1024, 1024, false},
+		{math.MaxUint64 - 1, 1, math.MaxUint64, math.MaxUint64, false},
+		{math.MaxUint64, 1, 0, math.MaxUint64, true},
+		{1 << 63, 1 << 63, 0, math.MaxUint64, true},
+	}
+
+	for _, c := range tc {
+		x, y := infounit.ByteCount(c.x), infounit.ByteCount(c.y)
+		v, err := x.Add(y)
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d + %d: ErrOutOfRange expected, got: %d, %v`, c.x, c.y, v, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d + %d: unexpected error: %v`, c.x, c.y, err)
+		case !c.e && v != infounit.ByteCount(c.v):
+			t.Errorf(`%d + %d: want: %d, got: %d`, c.x, c.y, c.v, v)
+		}
+		if sat := x.SaturatingAdd(y); sat != infounit.ByteCount(c.sat) {
+			t.Errorf(`%d + %d: saturating: want: %d, got: %d`, c.x, c.y, c.sat, sat)
+		}
+	}
+}
+
+//
+func TestByteCount_Sub(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, y uint64
+		v    uint64
+		e    bool
+	}{
+		{0, 0, 0, false},
+		{1024, 24, 1000, false},
+		{1024, 1024, 0, false},
+		{1024, 1025, 0, true},
+		{0, math.MaxUint64, 0, true},
+		{math.MaxUint64, math.MaxUint64 - 1, 1, false},
+	}
+
+	for _, c := range tc {
+		x, y := infounit.ByteCount(c.x), infounit.ByteCount(c.y)
+		v, err := x.Sub(y)
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d - %d: ErrOutOfRange expected, got: %d, %v`, c.x, c.y, v, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d - %d: unexpected error: %v`, c.x, c.y, err)
+		case !c.e && v != infounit.ByteCount(c.v):
+			t.Errorf(`%d - %d: want: %d, got: %d`, c.x, c.y, c.v, v)
+		}
+		if sat := x.SaturatingSub(y); sat != infounit.ByteCount(c.v) {
+			t.Errorf(`%d - %d: saturating: want: %d, got: %d`, c.x, c.y, c.v, sat)
+		}
+	}
+}
+
+//
+func TestByteCount_Mul(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, n uint64
+		v    uint64
+		e    bool
+	}{
+		{0, math.MaxUint64, 0, false},
+		{1000, 0, 0, false},
+		{uint64(infounit.Gigabyte), 18, uint64(infounit.Gigabyte) * 18, false},
+		{uint64(infounit.Gigabyte), 18446744073, uint64(infounit.Gigabyte) * 18446744073, false},
+		{uint64(infounit.Gigabyte), 18446744074, 0, true},
+		{1 << 32, 1 << 32, 0, true},
+		{math.MaxUint64, 1, math.MaxUint64, false},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.ByteCount(c.x).Mul(c.n)
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d * %d: ErrOutOfRange expected, got: %d, %v`, c.x, c.n, v, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d * %d: unexpected error: %v`, c.x, c.n, err)
+		case !c.e && v != infounit.ByteCount(c.v):
+			t.Errorf(`%d * %d: want: %d, got: %d`, c.x, c.n, c.v, v)
+		}
+	}
+}
+
+//
+func TestByteCount_MulFloat(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x uint64
+		f float64
+		v uint64
+		e bool
+	}{
+		{1000, 0, 0, false},
+		{1000, 1.5, 1500, false},
+		{1000, 0.0005, 1, false},
+		{1000, 0.0004, 0, false},
+		{uint64(infounit.Gigabyte), 0.25, uint64(infounit.Gigabyte) / 4, false},
+		{1000, -1, 0, true},
+		{1000, math.NaN(), 0, true},
+		{1000, math.Inf(+1), 0, true},
+		{1 << 63, 2, 0, true},
+		{1 << 62, 2, 1 << 63, false},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.ByteCount(c.x).MulFloat(c.f)
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d * %f: ErrOutOfRange expected, got: %d, %v`, c.x, c.f, v, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d * %f: unexpected error: %v`, c.x, c.f, err)
+		case !c.e && v != infounit.ByteCount(c.v):
+			t.Errorf(`%d * %f: want: %d, got: %d`, c.x, c.f, c.v, v)
+		}
+	}
+}
+
+//
+func TestByteCount_Div(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, n uint64
+		v    uint64
+	}{
+		{0, 1, 0},
+		{1000, 3, 333},
+		{1024, 1024, 1},
+		{math.MaxUint64, 2, math.MaxUint64 / 2},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.ByteCount(c.x).Div(c.n)
+		switch {
+		case err != nil:
+			t.Errorf(`%d / %d: unexpected error: %v`, c.x, c.n, err)
+		case v != infounit.ByteCount(c.v):
+			t.Errorf(`%d / %d: want: %d, got: %d`, c.x, c.n, c.v, v)
+		}
+	}
+
+	if v, err := infounit.ByteCount(1000).Div(0); !errors.Is(err, infounit.ErrDivZero) {
+		t.Errorf(`1000 / 0: ErrDivZero expected, got: %d, %v`, v, err)
+	}
+}
+
+//
+func TestByteCount_Round(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, m  uint64
+		trunc uint64
+		round uint64
+		up    uint64
+		upErr bool
+	}{
+		{0, 4096, 0, 0, 0, false},
+		{1, 4096, 0, 0, 4096, false},
+		{2047, 4096, 0, 0, 4096, false},
+		{2048, 4096, 0, 4096, 4096, false},
+		{4096, 4096, 4096, 4096, 4096, false},
+		{4097, 4096, 4096, 4096, 8192, false},
+		{1234567, 1000, 1234000, 1235000, 1235000, false},
+		{1234499, 1000, 1234000, 1234000, 1235000, false},
+		{1234567, 0, 1234567, 1234567, 1234567, false},
+		{1234567, 1, 1234567, 1234567, 1234567, false},
+		{math.MaxUint64, 1 << 20, math.MaxUint64 &^ (1<<20 - 1), math.MaxUint64, 0, true},
+		{math.MaxUint64 &^ (1<<20 - 1), 1 << 20, math.MaxUint64 &^ (1<<20 - 1), math.MaxUint64 &^ (1<<20 - 1), math.MaxUint64 &^ (1<<20 - 1), false},
+		{math.MaxUint64, math.MaxUint64, math.MaxUint64, math.MaxUint64, math.MaxUint64, false},
+		{math.MaxUint64 - 1, math.MaxUint64, 0, math.MaxUint64, math.MaxUint64, false},
+	}
+
+	for _, c := range tc {
+		x, m := infounit.ByteCount(c.x), infounit.ByteCount(c.m)
+		if v := x.Truncate(m); v != infounit.ByteCount(c.trunc) {
+			t.Errorf(`%d.Truncate(%d): want: %d, got: %d`, c.x, c.m, c.trunc, v)
+		}
+		if v := x.AlignDown(m); v != infounit.ByteCount(c.trunc) {
+			t.Errorf(`%d.AlignDown(%d): want: %d, got: %d`, c.x, c.m, c.trunc, v)
+		}
+		if v := x.Round(m); v != infounit.ByteCount(c.round) {
+			t.Errorf(`%d.Round(%d): want: %d, got: %d`, c.x, c.m, c.round, v)
+		}
+		v, err := x.AlignUp(m)
+		switch {
+		case c.upErr && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d.AlignUp(%d): ErrOutOfRange expected, got: %d, %v`, c.x, c.m, v, err)
+		case !c.upErr && err != nil:
+			t.Errorf(`%d.AlignUp(%d): unexpected error: %v`, c.x, c.m, err)
+		case !c.upErr && v != infounit.ByteCount(c.up):
+			t.Errorf(`%d.AlignUp(%d): want: %d, got: %d`, c.x, c.m, c.up, v)
+		}
+		if a, ex := x.IsAligned(m), c.x == c.trunc; a != ex {
+			t.Errorf(`%d.IsAligned(%d): want: %t, got: %t`, c.x, c.m, ex, a)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+)
+
+// ByteDelta represents a signed difference of byte counts, such as the change
+// in disk usage or the number of bytes over quota. ByteDelta values are
+// formatted and scanned in the same way as ByteCount values, with the sign;
+// e.g. "+1.5 GB", "-200 MiB". See the documentation of Format method bellow
+// for details.
+//
+// The ByteCount constants can be used to declare ByteDelta values by
+// converting them:
+//
+// 	fmt.Print(infounit.ByteDelta(infounit.Mebibyte) * -200)
+//
+// Range: -9223372036854775808 bytes through 9223372036854775807 bytes (=8 EiB)
+type ByteDelta int64
+
+// String returns the human-readable string representing the byte delta using
+// SI prefix. This implements the Stringer interface in the package fmt.
+func (bd ByteDelta) String() string {
+	var buf [fmtBufSize]byte
+	return string(bd.AppendFormat(buf[:0], nil))
+}
+
+// AppendFormat appends the human-readable string representation of the
+// ByteDelta value formatted by f to dst and returns the extended buffer. If f is
+// nil, the default format "% .1s", the same as String, is used. Unlike String
+// and the Printf family functions, this does not allocate as long as dst has
+// enough capacity.
+func (bd ByteDelta) AppendFormat(dst []byte, f *Formatter) []byte {
+	return f.orDefault().appendInt(dst, int64(bd), unitByte)
+}
+
+// GoString returns a string representation of the ByteDelta value in Go syntax
+// format. This implements the GoStringer interface in the package fmt.
+func (bd ByteDelta) GoString() string {
+	return fmt.Sprintf("ByteDelta(%d)", int64(bd))
+}
+
+// Abs returns the absolute value of the byte delta as a ByteCount. Since the
+// range of ByteCount is wider, this never overflows.
+func (bd ByteDelta) Abs() ByteCount {
+	return ByteCount(absInt64(int64(bd)))
+}
+
+// BitDelta returns the value converted to the number of bits. If the number of
+// bits is too large, an ErrOutOfRange will be returned.
+func (bd ByteDelta) BitDelta() (BitDelta, error) {
+	if bd < math.MinInt64>>3 || math.MaxInt64>>3 < bd {
+		return BitDelta(0), ErrOutOfRange
+	}
+	return BitDelta(int64(bd) * 8), nil
+}
+
+// Convert converts the byte delta to a float value in the specified unit. If
+// the goal is to output or to create a string in a human-readable format,
+// fmt.Printf or fmt.Sprintf is preferred.
+func (bd ByteDelta) Convert(unit ByteCount) float64 {
+	return float64(bd) / float64(unit)
+}
+
+// ConvertRound is the same as Convert except that it returns a value rounded to
+// the specified precision. If the goal is to output or to create a string in a
+// human-readable format, fmt.Printf or fmt.Sprintf is preferred.
+func (bd ByteDelta) ConvertRound(unit ByteCount, precision int) float64 {
+	p := math.Pow(10, float64(precision))
+	v := math.Round(p*float64(bd)/float64(unit)) / p
+	return v
+}
+
+// AtomicAddByteDelta atomically adds delta to *addr and returns the new value.
+// A wrapper function for the package sync/atomic.
+func AtomicAddByteDelta(addr *ByteDelta, delta ByteDelta) ByteDelta {
+	return ByteDelta(atomic.AddInt64((*int64)(addr), int64(delta)))
+}
+
+// AtomicLoadByteDelta atomically loads *addr. A wrapper function for the
+// package sync/atomic.
+func AtomicLoadByteDelta(addr *ByteDelta) ByteDelta {
+	return ByteDelta(atomic.LoadInt64((*int64)(addr)))
+}
+
+// AtomicStoreByteDelta atomically stores val into *addr. A wrapper function for
+// the package sync/atomic.
+func AtomicStoreByteDelta(addr *ByteDelta, val ByteDelta) {
+	atomic.StoreInt64((*int64)(addr), int64(val))
+}
+
+// AtomicSwapByteDelta atomically stores val into *addr and returns the previous
+// *addr value. A wrapper function for the package sync/atomic.
+func AtomicSwapByteDelta(addr *ByteDelta, val ByteDelta) ByteDelta {
+	return ByteDelta(atomic.SwapInt64((*int64)(addr), int64(val)))
+}
+
+// MarshalBinary encodes the ByteDelta value into a binary form and returns the
+// result. The value is encoded as a big-endian two's complement integer. This
+// implements the BinaryMarshaler interface in the package encoding.
+func (bd *ByteDelta) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(AtomicLoadByteDelta(bd)))
+	return b, nil
+}
+
+// UnmarshalBinary decodes the ByteDelta value from a binary form. This
+// implements the BinaryUnmarshaler interface in the package encoding.
+func (bd *ByteDelta) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("invalid len: %d", len(data))
+	}
+	AtomicStoreByteDelta(bd, ByteDelta(binary.BigEndian.Uint64(data)))
+	return nil
+}
+
+// MarshalText encodes the ByteDelta value into a UTF-8-encoded text and returns
+// the result. This implements the TextMarshaler interface in the
+// package encoding.
+func (bd *ByteDelta) MarshalText() ([]byte, error) {
+	return bd.AppendText(make([]byte, 0, 32))
+}
+
+// AppendText appends the UTF-8-encoded text form of the ByteDelta value, the same
+// as MarshalText, to dst and returns the extended buffer. This implements the
+// TextAppender interface in the package encoding.
+func (bd *ByteDelta) AppendText(dst []byte) ([]byte, error) {
+	dst = strconv.AppendInt(dst, int64(AtomicLoadByteDelta(bd)), 10)
+	return append(dst, " B"...), nil
+}
+
+// UnmarshalText decodes the ByteDelta value from a UTF-8-encoded text form.
+// This implements the TextUnmarshaler interface in the package encoding.
+func (bd *ByteDelta) UnmarshalText(text []byte) error {
+	val, err := unmarshalParser.ParseByteDelta(string(text))
+	if err != nil {
+		return err
+	}
+	AtomicStoreByteDelta(bd, ByteDelta(val))
+	return nil
+}
+
+// MarshalYAML encodes the ByteDelta value into an int64 for a YAML field.
+func (bd *ByteDelta) MarshalYAML() (interface{}, error) {
+	return int64(AtomicLoadByteDelta(bd)), nil
+}
+
+// UnmarshalYAML decodes the ByteDelta value from a YAML field.
+func (bd *ByteDelta) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if unmarshal(&s) == nil {
+		// checked first, since the yaml package silently wraps around
+		// numbers that are too large to be decoded
+		if err := checkIntExpr(s); err != nil {
+			return rangeError("ByteDelta", s, true)
+		}
+	}
+
+	var i64 int64
+	if unmarshal(&i64) == nil {
+		AtomicStoreByteDelta(bd, ByteDelta(i64))
+
+		return nil
+	}
+
+	if unmarshal(&s) == nil {
+		v, err := unmarshalParser.ParseByteDelta(s)
+		if err != nil {
+			return err
+		}
+		AtomicStoreByteDelta(bd, v)
+
+		return nil
+	}
+
+	return typeError("ByteDelta", yamlInput(unmarshal))
+}
+
+// IsZero returns whether the ByteDelta value is zero.
+func (bd ByteDelta) IsZero() bool {
+	return bd == 0
+}
+
+// MarshalJSON encodes the ByteDelta value into a number for a JSON field.
+func (bd *ByteDelta) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(AtomicLoadByteDelta(bd)))
+}
+
+// UnmarshalJSON decodes the ByteDelta value from a JSON field.
+func (bd *ByteDelta) UnmarshalJSON(b []byte) error {
+	if string(b) == jsonNULL {
+		return nil
+	}
+
+	var i64 int64
+	if json.Unmarshal(b, &i64) == nil {
+		AtomicStoreByteDelta(bd, ByteDelta(i64))
+
+		return nil
+	}
+
+	if err := checkIntExpr(string(b)); err != nil {
+		return rangeError("ByteDelta", string(b), true)
+	}
+
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		v, err := unmarshalParser.ParseByteDelta(s)
+		if err != nil {
+			return err
+		}
+		AtomicStoreByteDelta(bd, v)
+
+		return nil
+	}
+
+	return typeError("ByteDelta", string(b))
+}
+
+// Format implements the Formatter interface in the package fmt to format
+// ByteDelta values. The verbs and flags are the same as ByteCount, except that
+// the sign is printed for negative values:
+//
+// 	%s	human-readable format with SI prefix; e.g. "-1.5GB"
+// 	%S	human-readable format with binary prefix; e.g. "-200MiB"
+//
+// The value is scaled by its magnitude, and formatted exactly in the same way
+// as ByteCount. In addition to the flags of ByteCount, the following flag is
+// available for both %s and %S:
+//
+// 	+	always print a sign; e.g. "+1.5 GB"
+//
+// With the '0' flag, the leading zeros are inserted after the sign.
+//
+// The verbs %h and %H for the significant digits are also available, as with
+// ByteCount.
+//
+// %v prints in the default format:
+//
+// 	%v	default format, same as "% .1s"
+// 	%+v	default format with sign, same as "%+ .1s"
+// 	%#v	GoString(); e.g. "ByteDelta(-1024)"
+//
+// The following int64 compatible verbs are also supported.
+// They print the integer values always in byte:
+//
+// 	%b	base 2
+// 	%d	base 10
+// 	%o	base 8
+// 	%x	base 16, with lower-case letters for a-f
+// 	%X	base 16, with upper-case letters for A-F
+//
+// See the package fmt documentation for details.
+func (bd ByteDelta) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's', 'S', 'h', 'H':
+		f := verbFormatter(s, verb)
+		f.PlusSign = s.Flag(int('+'))
+		var buf [fmtBufSize]byte
+		writePadded(s, bd.AppendFormat(buf[:0], &f))
+
+	case 'v':
+		if s.Flag(int('#')) {
+			fmt.Fprint(s, bd.GoString())
+			break
+		}
+		f := defaultFormatter
+		f.PlusSign = s.Flag(int('+'))
+		var buf [fmtBufSize]byte
+		_, _ = s.Write(bd.AppendFormat(buf[:0], &f))
+
+	case 'b', 'd', 'o', 'x', 'X':
+		tFmt := "%"
+		for _, flag := range " #+-0" {
+			if s.Flag(int(flag)) {
+				tFmt += string(flag)
+			}
+		}
+		if wid, ok := s.Width(); ok {
+			tFmt += strconv.FormatInt(int64(wid), 10)
+		}
+		tFmt += string(verb)
+		fmt.Fprintf(s, tFmt, int64(bd))
+
+	default:
+		fmt.Fprintf(s, "%%!%c(ByteDelta=%d)", verb, int64(bd))
+	}
+}
+
+// Scan implements the Scanner interface in the package fmt to scan ByteDelta
+// values from strings. The verbs are the same as ByteCount, except that an
+// optional sign, '+' or '-', is accepted before the digits; e.g. "+1.5 GB",
+// "-200 MiB". No space is allowed between the sign and the digits.
+//
+// If the scanned value exceeds the range of ByteDelta, an error wrapping
+// ErrOutOfRange is returned.
+//
+// The following verbs are compatible with int64 and scans integers without a
+// unit suffix:
+//
+// 	%b	base 2
+// 	%o	base 8
+// 	%d	base 10
+// 	%x, %X	base 16
+//
+// See the package fmt documentation for details.
+func (bd *ByteDelta) Scan(state fmt.ScanState, verb rune) error {
+	switch verb {
+	case 'b', 'd', 'o', 'x', 'X':
+		tFmt := "%"
+		if wid, ok := state.Width(); ok {
+			tFmt += strconv.FormatInt(int64(wid), 10)
+		}
+		tFmt += string(verb)
+		ptr := (*int64)(bd)
+		if _, err := fmt.Fscanf(state, tFmt, ptr); err != nil {
+			return fmt.Errorf("%%%c: no input: %w", verb, err)
+		}
+
+	case 's', 'S', 'u', 'U':
+		lx := lexer{state: state}
+		v, err := lx.scanInt(verb, RoundNearest, byteCountUnits, "byte count")
+		if err != nil {
+			return err
+		}
+		*bd = ByteDelta(v)
+
+	default:
+		return fmt.Errorf("unknown verb for ByteDelta: %%%c", verb)
+	}
+	return nil
+}
+
+// ParseByteDelta converts a human-readable string representation into a
+// ByteDelta value. The human-readable string is a decimal number with an
+// optional sign and a unit suffix; e.g. "+1.5 GB", "-200 MiB". SI and binary
+// prefixes are correctly recognized. If the value exceeds the range of
+// ByteDelta, the returned error wraps ErrOutOfRange.
+func ParseByteDelta(s string) (ByteDelta, error) {
+	v, err := parseInt(s, "ByteDelta", 's', RoundNearest, byteCountUnits, "byte count")
+	if err != nil {
+		return 0, err
+	}
+	return ByteDelta(v), nil
+}
+
+// ParseByteDeltaBinary is the same as ParseByteDelta except that it treats the
+// SI prefixes as binary prefixes. That is, it parses "-100 kB" as -100 KiB
+// (=-102400 B).
+func ParseByteDeltaBinary(s string) (ByteDelta, error) {
+	v, err := parseInt(s, "ByteDelta", 'S', RoundNearest, byteCountUnits, "byte count")
+	if err != nil {
+		return 0, err
+	}
+	return ByteDelta(v), nil
+}
+
+// ParseByteDeltaRound is the same as ParseByteDelta except that it uses the
+// specified rounding mode when the value is not a whole number of bytes. The
+// rounding mode is applied to the magnitude, so RoundFloor rounds toward zero
+// and RoundCeil rounds away from zero for both positive and negative values.
+func ParseByteDeltaRound(s string, mode RoundingMode) (ByteDelta, error) {
+	v, err := parseInt(s, "ByteDelta", 's', mode, byteCountUnits, "byte count")
+	if err != nil {
+		return 0, err
+	}
+	return ByteDelta(v), nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestAtomicAddByteDelta_1(t *testing.T) {
+	t.Parallel()
+
+	var wg sync.WaitGroup
+	bd := infounit.ByteDelta(infounit.Megabyte)
+	for i := 0; i < 10000; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			infounit.AtomicAddByteDelta(&bd, -300)
+		}()
+	}
+	wg.Wait()
+
+	exbd := infounit.ByteDelta(infounit.Megabyte) - 300*10000
+	if bd != exbd {
+		t.Errorf(`want: %s, got: %s`, exbd, bd)
+	}
+}
+
+//
+func TestAtomicLoadStoreSwapByteDelta(t *testing.T) {
+	t.Parallel()
+
+	var bd infounit.ByteDelta
+	infounit.AtomicStoreByteDelta(&bd, -12345)
+	if v := infounit.AtomicLoadByteDelta(&bd); v != -12345 {
+		t.Errorf(`load: want: -12345, got: %d`, v)
+	}
+	if v := infounit.AtomicSwapByteDelta(&bd, 777); v != -12345 {
+		t.Errorf(`swap: want: -12345, got: %d`, v)
+	}
+	if bd != 777 {
+		t.Errorf(`bd: want: 777, got: %d`, bd)
+	}
+}
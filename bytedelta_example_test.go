@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"fmt"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func ExampleByteDelta_Format_printf() {
+	yesterday := infounit.Gigabyte * 12
+	today := infounit.Megabyte * 10500
+
+	d, err := today.Delta(yesterday)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%v\n", d)      // default format, same as "% .1s"
+	fmt.Printf("%+v\n", d)     // with sign
+	fmt.Printf("%+ .2S\n", d)  // binary prefix, precision 2
+	fmt.Printf("%# s\n", d)    // long unit
+	fmt.Printf("%d\n", d)      // decimal in bytes
+	fmt.Printf("%+v\n", -d)    // positive with sign
+	fmt.Printf("%#v\n", d)     // Go syntax format
+	fmt.Printf("%010.1s\n", d) // zero padding after the sign
+	// Output:
+	// -1.5 GB
+	// -1.5 GB
+	// -1.40 GiB
+	// -1.5 gigabytes
+	// -1500000000
+	// +1.5 GB
+	// ByteDelta(-1500000000)
+	// -00001.5GB
+}
+
+//
+func ExampleByteCount_AddDelta() {
+	quota := infounit.Gigabyte * 10
+
+	for _, s := range []string{"+1.5 GB", "-200 MiB", "-20 GB"} {
+		d, err := infounit.ParseByteDelta(s)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		v, err := quota.AddDelta(d)
+		if err != nil {
+			fmt.Println(s, err)
+			continue
+		}
+		fmt.Println(s, v)
+	}
+	// Output:
+	// +1.5 GB 11.5 GB
+	// -200 MiB 9.8 GB
+	// -20 GB out of range
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestByteDelta_Format_1(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v int64
+		f string
+		s string
+	}{
+		{0, "%s", "0B"},
+		{0, "%+s", "+0B"},
+		{1, "%# s", "1 byte"},
+		{-1, "%# s", "-1 byte"},
+		{-2, "%# s", "-2 bytes"},
+		{1500000000, "%+ .1s", "+1.5 GB"},
+		{-1500000000, "% .1s", "-1.5 GB"},
+		{-1500000000, "%s", "-1.5GB"},
+		{-209715200, "% S", "-200 MiB"},
+		{-209715200, "%+ S", "-200 MiB"},
+		{209715200, "%+# .1S", "+200.0 mebibytes"},
+		{-1000, "%# s", "-1 kilobyte"},
+		{-999960, "%.1s", "-1.0MB"},
+		{math.MinInt64, "%S", "-8EiB"},
+		{math.MaxInt64, "%.3S", "8.000EiB"},
+		{-123456, "[%10.1s]", "[  -123.5kB]"},
+		{-123456, "[%-10.1s]", "[-123.5kB  ]"},
+		{-123456, "[%010.1s]", "[-00123.5kB]"},
+		{-123456, "[%012.1s]", "[-0000123.5kB]"},
+		{123456, "[%+012.1s]", "[+0000123.5kB]"},
+		{123456, "[%012.1s]", "[00000123.5kB]"},
+		{-123456, "[%3.1s]", "[-123.5kB]"},
+		{-1500000000, "%v", "-1.5 GB"},
+		{1500000000, "%v", "1.5 GB"},
+		{1500000000, "%+v", "+1.5 GB"},
+		{-1024, "%#v", "ByteDelta(-1024)"},
+		{-1024, "%d", "-1024"},
+		{1024, "%+d", "+1024"},
+		{-255, "%x", "-ff"},
+		{-255, "%X", "-FF"},
+		{-5, "%b", "-101"},
+		{-8, "%o", "-10"},
+		{-8, "%06d", "-00008"},
+		{-8, "%q", "%!q(ByteDelta=-8)"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.ByteDelta(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %d: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestByteDelta_String(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v int64
+		s string
+	}{
+		{0, "0 B"},
+		{-1, "-1 B"},
+		{-999, "-999 B"},
+		{-1000, "-1.0 kB"},
+		{123456789, "123.5 MB"},
+		{-123456789, "-123.5 MB"},
+	}
+
+	for _, c := range tc {
+		if s := infounit.ByteDelta(c.v).String(); s != c.s {
+			t.Errorf(`%d: want: "%s", got: "%s"`, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func BenchmarkByteDelta_AppendFormat(b *testing.B) {
+	v := infounit.ByteDelta(-123456789)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = v.AppendFormat(buf[:0], nil)
+	}
+}
+
+//
+func BenchmarkByteDelta_String(b *testing.B) {
+	v := infounit.ByteDelta(-123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = v.String()
+	}
+}
+
+//
+func BenchmarkByteDelta_Sprintf(b *testing.B) {
+	v := infounit.ByteDelta(-123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%# .2S", v)
+	}
+}
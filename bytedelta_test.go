@@ -0,0 +1,236 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestByteDelta_Abs(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v int64
+		a uint64
+	}{
+		{0, 0},
+		{1, 1},
+		{-1, 1},
+		{-123456789, 123456789},
+		{math.MaxInt64, math.MaxInt64},
+		{math.MinInt64, 1 << 63},
+	}
+
+	for _, c := range tc {
+		if a := infounit.ByteDelta(c.v).Abs(); a != infounit.ByteCount(c.a) {
+			t.Errorf(`%d: want: %d, got: %d`, c.v, c.a, a)
+		}
+	}
+}
+
+//
+func TestByteDelta_BitDelta(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v int64
+		b int64
+		e bool
+	}{
+		{0, 0, false},
+		{1, 8, false},
+		{-1, -8, false},
+		{-123456789, -987654312, false},
+		{math.MaxInt64 >> 3, math.MaxInt64 &^ 7, false},
+		{math.MinInt64 >> 3, math.MinInt64, false},
+		{math.MaxInt64>>3 + 1, 0, true},
+		{math.MinInt64>>3 - 1, 0, true},
+	}
+
+	for _, c := range tc {
+		b, err := infounit.ByteDelta(c.v).BitDelta()
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d: ErrOutOfRange expected, got: %d, %v`, c.v, b, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d: unexpected error: %v`, c.v, err)
+		case !c.e && b != infounit.BitDelta(c.b):
+			t.Errorf(`%d: want: %d, got: %d`, c.v, c.b, b)
+		}
+	}
+}
+
+//
+func TestByteDelta_Convert(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v    int64
+		unit infounit.ByteCount
+		prec int
+		f    float64
+	}{
+		{-1500000000, infounit.Gigabyte, 1, -1.5},
+		{-123456789, infounit.Megabyte, 2, -123.46},
+		{209715200, infounit.Mebibyte, 0, 200},
+	}
+
+	for _, c := range tc {
+		if f := infounit.ByteDelta(c.v).ConvertRound(c.unit, c.prec); f != c.f {
+			t.Errorf(`%d: want: %f, got: %f`, c.v, c.f, f)
+		}
+		if f, ex := infounit.ByteDelta(c.v).Convert(c.unit), float64(c.v)/float64(c.unit); f != ex {
+			t.Errorf(`%d: want: %f, got: %f`, c.v, ex, f)
+		}
+	}
+}
+
+//
+func TestByteCount_Delta(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, y uint64
+		d    int64
+		e    bool
+	}{
+		{0, 0, 0, false},
+		{1000, 300, 700, false},
+		{300, 1000, -700, false},
+		{math.MaxInt64, 0, math.MaxInt64, false},
+		{0, 1 << 63, math.MinInt64, false},
+		{math.MaxUint64, math.MaxUint64 - 1<<63 + 1, math.MaxInt64, false},
+		{1 << 63, 0, 0, true},
+		{0, 1<<63 + 1, 0, true},
+		{math.MaxUint64, 0, 0, true},
+		{0, math.MaxUint64, 0, true},
+	}
+
+	for _, c := range tc {
+		d, err := infounit.ByteCount(c.x).Delta(infounit.ByteCount(c.y))
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d - %d: ErrOutOfRange expected, got: %d, %v`, c.x, c.y, d, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d - %d: unexpected error: %v`, c.x, c.y, err)
+		case !c.e && d != infounit.ByteDelta(c.d):
+			t.Errorf(`%d - %d: want: %d, got: %d`, c.x, c.y, c.d, d)
+		}
+	}
+}
+
+//
+func TestByteCount_AddDelta(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x uint64
+		d int64
+		r uint64
+		e bool
+	}{
+		{0, 0, 0, false},
+		{1000, 300, 1300, false},
+		{1000, -300, 700, false},
+		{1000, -1000, 0, false},
+		{1000, -1001, 0, true},
+		{0, -1, 0, true},
+		{1 << 63, math.MinInt64, 0, false},
+		{1<<63 - 1, math.MinInt64, 0, true},
+		{math.MaxUint64 - 5, 5, math.MaxUint64, false},
+		{math.MaxUint64 - 5, 6, 0, true},
+		{math.MaxUint64, math.MaxInt64, 0, true},
+	}
+
+	for _, c := range tc {
+		r, err := infounit.ByteCount(c.x).AddDelta(infounit.ByteDelta(c.d))
+		switch {
+		case c.e && !errors.Is(err, infounit.ErrOutOfRange):
+			t.Errorf(`%d + %d: ErrOutOfRange expected, got: %d, %v`, c.x, c.d, r, err)
+		case !c.e && err != nil:
+			t.Errorf(`%d + %d: unexpected error: %v`, c.x, c.d, err)
+		case !c.e && r != infounit.ByteCount(c.r):
+			t.Errorf(`%d + %d: want: %d, got: %d`, c.x, c.d, c.r, r)
+		}
+	}
+}
+
+//
+func TestParseByteDelta(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s string
+		v int64
+		e error
+	}{
+		{"0 B", 0, nil},
+		{"-0 B", 0, nil},
+		{"+1.5 GB", 1500000000, nil},
+		{"-200 MiB", -209715200, nil},
+		{"-200MiB", -209715200, nil},
+		{"  +12 kilobytes", 12000, nil},
+		{"-9223372036854775808 B", math.MinInt64, nil},
+		{"9223372036854775807 B", math.MaxInt64, nil},
+		{"+8 EiB", 0, infounit.ErrOutOfRange},
+		{"-8 EiB", math.MinInt64, nil},
+		{"-8.000001 EiB", 0, infounit.ErrOutOfRange},
+		{"-9223372036854775809 B", 0, infounit.ErrOutOfRange},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.ParseByteDelta(c.s)
+		switch {
+		case c.e != nil && !errors.Is(err, c.e):
+			t.Errorf(`%q: %v expected, got: %d, %v`, c.s, c.e, v, err)
+		case c.e == nil && err != nil:
+			t.Errorf(`%q: unexpected error: %v`, c.s, err)
+		case c.e == nil && v != infounit.ByteDelta(c.v):
+			t.Errorf(`%q: want: %d, got: %d`, c.s, c.v, v)
+		}
+	}
+
+	for _, s := range []string{"", "5", "- 5 GB", "--5 GB", "+-5 GB", "-+5 GB", "5 -GB"} {
+		if v, err := infounit.ParseByteDelta(s); err == nil {
+			t.Errorf(`%q: error expected, got: %d`, s, v)
+		}
+	}
+}
+
+//
+func TestParseByteDeltaRound(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s    string
+		mode infounit.RoundingMode
+		v    int64
+	}{
+		{"-0.3 KiB", infounit.RoundNearest, -307},
+		{"-0.3 KiB", infounit.RoundFloor, -307},
+		{"-0.3 KiB", infounit.RoundCeil, -308},
+		{"+0.3 KiB", infounit.RoundCeil, 308},
+		{"-1.5 kB", infounit.RoundExact, -1500},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.ParseByteDeltaRound(c.s, c.mode)
+		switch {
+		case err != nil:
+			t.Errorf(`%q, %v: unexpected error: %v`, c.s, c.mode, err)
+		case v != infounit.ByteDelta(c.v):
+			t.Errorf(`%q, %v: want: %d, got: %d`, c.s, c.mode, c.v, v)
+		}
+	}
+
+	if v, err := infounit.ParseByteDeltaRound("-0.3 KiB", infounit.RoundExact); !errors.Is(err, infounit.ErrFractional) {
+		t.Errorf(`ErrFractional expected, got: %d, %v`, v, err)
+	}
+}
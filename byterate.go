@@ -0,0 +1,502 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// ByteRate represents a number of bytes that are transferred or processed per
+// unit of time. ByteRate values can be converted to human-readable string
+// representations by the standard Printf family functions in the package fmt.
+// See the documentation of Format method bellow for details.
+type ByteRate float64
+
+// Common ByteRate values for units with SI and binary prefixes. To convert a
+// float value of specific unit to a ByteRate, multiply:
+//
+// 	mbps := 100
+// 	fmt.Print(infounit.ByteRate(mbps) * infounit.MegabytePerSecond)
+const (
+	BytePerSecond     ByteRate = 1                        // B/s, byte per second
+	KilobytePerSecond          = 1000 * BytePerSecond     // kB/s, kilobyte per second
+	MegabytePerSecond          = 1000 * KilobytePerSecond // MB/s, megabyte per second
+	GigabytePerSecond          = 1000 * MegabytePerSecond // GB/s, gigabyte per second
+	TerabytePerSecond          = 1000 * GigabytePerSecond // TB/s, terabyte per second
+	PetabytePerSecond          = 1000 * TerabytePerSecond // PB/s, petabyte per second
+	ExabytePerSecond           = 1000 * PetabytePerSecond // EB/s, exabyte per second
+	KibibytePerSecond          = 1024 * BytePerSecond     // KiB/s, kibibyte per second
+	MebibytePerSecond          = 1024 * KibibytePerSecond // MiB/s, mebibyte per second
+	GibibytePerSecond          = 1024 * MebibytePerSecond // GiB/s, gibibyte per second
+	TebibytePerSecond          = 1024 * GibibytePerSecond // TiB/s, tebibyte per second
+	PebibytePerSecond          = 1024 * TebibytePerSecond // PiB/s, pebibyte per second
+	ExbibytePerSecond          = 1024 * PebibytePerSecond // EiB/s, exbibyte per second
+)
+
+// String returns the human-readable string representing the byte rate using SI
+// prefix. This implements the Stringer interface in the package fmt.
+func (br ByteRate) String() string {
+	var buf [fmtBufSize]byte
+	return string(br.AppendFormat(buf[:0], nil))
+}
+
+// AppendFormat appends the human-readable string representation of the
+// ByteRate value formatted by f to dst and returns the extended buffer. If f is
+// nil, the default format "% .1s", the same as String, is used. Unlike String
+// and the Printf family functions, this does not allocate as long as dst has
+// enough capacity.
+func (br ByteRate) AppendFormat(dst []byte, f *Formatter) []byte {
+	return f.orDefault().appendFloat(dst, float64(br), unitByteRate)
+}
+
+// GoString returns a string representation of the ByteRate value in Go syntax
+// format. This implements the GoStringer interface in the package fmt.
+func (br ByteRate) GoString() string {
+	return fmt.Sprintf("ByteRate(%s)", strconv.FormatFloat(float64(br), 'f', -1, 64))
+}
+
+// IsInf reports whether the byte rate value is an infinity, according to sign.
+// If sign > 0, IsInf reports whether the byte rate value is positive infinity.
+// If sign < 0, IsInf reports whether the byte rate value is negative infinity.
+// If sign == 0, IsInf reports whether the byte rate value is either infinity.
+func (br ByteRate) IsInf(sign int) bool {
+	return math.IsInf(float64(br), sign)
+}
+
+// IsNaN reports whether the byte rate value is an IEEE 754 "not-a-number"
+// value.
+func (br ByteRate) IsNaN() bool {
+	return math.IsNaN(float64(br))
+}
+
+// BitRate returns the value converted to the number of bits per second. Since
+// the conversion is a multiplication by a power of two, no precision is lost.
+func (br ByteRate) BitRate() BitRate {
+	return BitRate(br * 8)
+}
+
+// Truncate returns the result of rounding br toward zero to a multiple of m.
+// If m is not positive, Truncate returns br unchanged.
+func (br ByteRate) Truncate(m ByteRate) ByteRate {
+	if !(0 < m) {
+		return br
+	}
+	return ByteRate(math.Trunc(float64(br/m))) * m
+}
+
+// Round returns the result of rounding br to the nearest multiple of m. The
+// rounding behavior for halfway values is to round away from zero. If m is not
+// positive, Round returns br unchanged.
+func (br ByteRate) Round(m ByteRate) ByteRate {
+	if !(0 < m) {
+		return br
+	}
+	return ByteRate(math.Round(float64(br/m))) * m
+}
+
+// Convert converts the byte rate to a float value in the specified unit. If the
+// goal is to output or to create a string in a human-readable format,
+// fmt.Printf or fmt.Sprintf is preferred.
+func (br ByteRate) Convert(unit ByteRate) float64 {
+	return float64(br) / float64(unit)
+}
+
+// ConvertRound is the same as Convert except that it returns a value rounded to
+// the specified precision. If the goal is to output or to create a string in a
+// human-readable format, fmt.Printf or fmt.Sprintf is preferred.
+func (br ByteRate) ConvertRound(unit ByteRate, precision int) float64 {
+	p := math.Pow(10, float64(precision))
+	v := math.Round(p*float64(br)/float64(unit)) / p
+	return v
+}
+
+// CalcByteCount calculates the number of bytes that can be transferred or
+// processed in the specified duration at this byte rate.
+func (br ByteRate) CalcByteCount(duration time.Duration) (ByteCount, error) {
+	switch {
+	case duration == 0:
+		return 0, nil
+	case br.IsNaN():
+		return 0, nil
+	case duration < 0 && 0 <= br:
+		return 0, ErrOutOfRange
+	case br.IsInf(0):
+		return 0, ErrOutOfRange
+	}
+	bc := float64(br) * duration.Seconds()
+	if bc < 0 || float64(math.MaxUint64) < bc {
+		return 0, ErrOutOfRange
+	}
+	return ByteCount(bc), nil
+}
+
+// CalcBitCount calculates the number of bits that can be transferred or
+// processed in the specified duration at this byte rate.
+func (br ByteRate) CalcBitCount(duration time.Duration) (BitCount, error) {
+	return br.BitRate().CalcBitCount(duration)
+}
+
+// AtomicLoadByteRate atomically loads *addr. A wrapper function for the
+// package sync/atomic.
+func AtomicLoadByteRate(addr *ByteRate) ByteRate {
+	return ByteRate(math.Float64frombits(atomic.LoadUint64((*uint64)(unsafe.Pointer(addr)))))
+}
+
+// AtomicStoreByteRate atomically stores val into *addr. A wrapper function for
+// the package sync/atomic.
+func AtomicStoreByteRate(addr *ByteRate, val ByteRate) {
+	atomic.StoreUint64((*uint64)(unsafe.Pointer(addr)), math.Float64bits(float64(val)))
+}
+
+// AtomicSwapByteRate atomically stores val into *addr and returns the previous
+// *addr value. A wrapper function for the package sync/atomic.
+func AtomicSwapByteRate(addr *ByteRate, val ByteRate) ByteRate {
+	return ByteRate(math.Float64frombits(
+		atomic.SwapUint64(
+			(*uint64)(unsafe.Pointer(addr)),
+			math.Float64bits(float64(val)),
+		),
+	))
+}
+
+// MarshalBinary encodes the ByteRate value into a binary form and returns the
+// result. This implements the BinaryMarshaler interface in the
+// package encoding.
+func (br *ByteRate) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, math.Float64bits(float64(AtomicLoadByteRate(br))))
+	return b, nil
+}
+
+// UnmarshalBinary decodes the ByteRate value from a binary form. This
+// implements the BinaryUnmarshaler interface in the package encoding.
+func (br *ByteRate) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("invalid len: %d", len(data))
+	}
+	AtomicStoreByteRate(br, ByteRate(math.Float64frombits(binary.BigEndian.Uint64(data))))
+	return nil
+}
+
+// MarshalText encodes the ByteRate value into a UTF-8-encoded text and returns
+// the result. This implements the TextMarshaler interface in the
+// package encoding.
+func (br *ByteRate) MarshalText() ([]byte, error) {
+	return br.AppendText(make([]byte, 0, 32))
+}
+
+// AppendText appends the UTF-8-encoded text form of the ByteRate value, the same
+// as MarshalText, to dst and returns the extended buffer. This implements the
+// TextAppender interface in the package encoding.
+func (br *ByteRate) AppendText(dst []byte) ([]byte, error) {
+	dst = strconv.AppendFloat(dst, float64(AtomicLoadByteRate(br)), 'f', -1, 64)
+	return append(dst, " B/s"...), nil
+}
+
+// UnmarshalText decodes the ByteRate value from a UTF-8-encoded text form. This
+// implements the TextUnmarshaler interface in the package encoding.
+func (br *ByteRate) UnmarshalText(text []byte) error {
+	val, err := unmarshalParser.ParseByteRate(string(text))
+	if err != nil {
+		return err
+	}
+	AtomicStoreByteRate(br, ByteRate(val))
+	return nil
+}
+
+// MarshalYAML encodes the ByteRate value into a float64 for a YAML field.
+func (br *ByteRate) MarshalYAML() (interface{}, error) {
+	return float64(AtomicLoadByteRate(br)), nil
+}
+
+// UnmarshalYAML decodes the ByteRate value from a YAML field.
+func (br *ByteRate) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var f64 float64
+	if unmarshal(&f64) == nil {
+		AtomicStoreByteRate(br, ByteRate(f64))
+
+		return nil
+	}
+
+	var s string
+	if unmarshal(&s) == nil {
+		v, err := unmarshalParser.ParseByteRate(s)
+		if err != nil {
+			return err
+		}
+		AtomicStoreByteRate(br, v)
+
+		return nil
+	}
+
+	return typeError("ByteRate", yamlInput(unmarshal))
+}
+
+// IsZero returns whether the ByteRate value is zero.
+func (br ByteRate) IsZero() bool {
+	return br == 0
+}
+
+// MarshalJSON encodes the ByteRate value into a string for a JSON field.
+func (br *ByteRate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(AtomicLoadByteRate(br))
+}
+
+// UnmarshalJSON decodes the ByteRate value from a JSON field.
+func (br *ByteRate) UnmarshalJSON(b []byte) error {
+	if string(b) == jsonNULL {
+		return nil
+	}
+
+	var f64 float64
+	if json.Unmarshal(b, &f64) == nil {
+		AtomicStoreByteRate(br, ByteRate(f64))
+
+		return nil
+	}
+
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		v, err := unmarshalParser.ParseByteRate(s)
+		if err != nil {
+			return err
+		}
+		AtomicStoreByteRate(br, v)
+
+		return nil
+	}
+
+	return typeError("ByteRate", string(b))
+}
+
+//
+var unitByteRate = &unitName{abbr: "B", full: "byte", sufAbbr: "/s", sufFull: "per second"}
+
+// Format implements the Formatter interface in the package fmt to format
+// ByteRate values. This gives the ability to format the ByteRate values in
+// human-readable format using standard Printf family functions in the
+// package fmt; fmt.Printf, fmt.Fprintf, fmt.Sprintf, fmt.Errorf, and functions
+// derived from them.
+//
+// For ByteRate type, four custom 'verbs' are implemented:
+//
+// 	%s	human-readable format with SI prefix; e.g. "MB/s"
+// 	%S	human-readable format with binary prefix; e.g. "MiB/s"
+// 	%h	human-readable format with SI prefix and significant digits
+// 	%H	human-readable format with binary prefix and significant digits
+//
+// Width and precision can be specified to both %s and %S:
+//
+// 	%s	default width, default precision
+// 	%7s	width 7, default precision
+// 	%.2s	default width, precision 2
+// 	%7.2s	width 7, precision 2
+// 	%7.s	width 7, precision 0
+//
+// Negative values are scaled by their magnitude and printed with the sign,
+// e.g. "-5.0 GB/s".
+//
+// The prefix is selected after rounding to the precision, so "%.1s" prints
+// 999.96 as "1.0 kB/s", not as "1000.0 B/s".
+//
+// The verbs %h and %H are the same as %s and %S, except that the precision is
+// the number of significant digits, which is 3 by default, like "ls -h"; e.g.
+// "1.23 GB/s", "12.3 GB/s" and "123 GB/s" with the ' ' flag.
+//
+// The following flags are also available for both %s and %S:
+//
+// 	' '	(space) print a space between digits and unit; e.g. "12.3 MB/s"
+// 	#	use long unit name; e.g. "kilobytes per second", "mebibytes per second"
+// 	-	pad with spaces on the right rather than the left (left-justify)
+// 	0	pad with leading zeros rather than spaces, after the sign
+//
+// %v prints in the default format:
+//
+// 	%v	default format, same as "% .1s"
+// 	%#v	GoString(); e.g. "ByteRate(1234567.89)"
+//
+// The following float64 compatible verbs are also supported.
+// They print the float values always in B/s:
+//
+// 	%b	decimalless scientific notation, e.g. -123456p-78
+// 	%e	scientific notation, e.g. -1.234456e+78
+// 	%E	scientific notation, e.g. -1.234456E+78
+// 	%f	decimal point but no exponent, e.g. 123.456
+// 	%F	synonym for %f
+// 	%g	%e for large exponents, %f otherwise
+// 	%G	%E for large exponents, %F otherwise
+// 	%x	hexadecimal notation, e.g. -0x1.23abcp+20
+// 	%X	upper-case hexadecimal notation, e.g. -0X1.23ABCP+20
+//
+// See the package fmt documentation for details.
+func (br ByteRate) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's', 'S', 'h', 'H':
+		f := verbFormatter(s, verb)
+		var buf [fmtBufSize]byte
+		writePadded(s, br.AppendFormat(buf[:0], &f))
+
+	case 'v':
+		if s.Flag(int('#')) {
+			fmt.Fprint(s, br.GoString())
+			break
+		}
+		var buf [fmtBufSize]byte
+		_, _ = s.Write(br.AppendFormat(buf[:0], nil))
+
+	case 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X':
+		tFmt := "%"
+		for _, flag := range " #+-0" {
+			if s.Flag(int(flag)) {
+				tFmt += string(flag)
+			}
+		}
+		if wid, ok := s.Width(); ok {
+			tFmt += strconv.FormatInt(int64(wid), 10)
+		}
+		if prec, ok := s.Precision(); ok {
+			tFmt += "." + strconv.FormatInt(int64(prec), 10)
+		}
+		tFmt += string(verb)
+		fmt.Fprintf(s, tFmt, float64(br))
+
+	default:
+		fmt.Fprintf(s, "%%!%c(ByteRate=%f)", verb, float64(br))
+	}
+}
+
+// byteRateUnits is the table of the unit suffixes of ByteRate consisting of one
+// token. The long unit names, e.g. "kilobytes per second", are looked up in
+// byteRateUnits3 by the first word. The "B" of the abbreviations is in upper
+// case, which is matched case-sensitively, so that "Mb/s" is not accepted.
+var byteRateUnits = []scanUnit{
+	{"B/s", -1, false, false}, {"byte/s", -1, false, false}, {"bytes/s", -1, false, false},
+	{"kB/s", 0, false, false}, {"kbyte/s", 0, false, false}, {"kbytes/s", 0, false, false},
+	{"mB/s", 1, false, false}, {"mbyte/s", 1, false, false}, {"mbytes/s", 1, false, false},
+	{"gB/s", 2, false, false}, {"gbyte/s", 2, false, false}, {"gbytes/s", 2, false, false},
+	{"tB/s", 3, false, false}, {"tbyte/s", 3, false, false}, {"tbytes/s", 3, false, false},
+	{"pB/s", 4, false, false}, {"pbyte/s", 4, false, false}, {"pbytes/s", 4, false, false},
+	{"eB/s", 5, false, false}, {"ebyte/s", 5, false, false}, {"ebytes/s", 5, false, false},
+	{"kiB/s", 0, true, false}, {"kibyte/s", 0, true, false}, {"kibytes/s", 0, true, false},
+	{"miB/s", 1, true, false}, {"mibyte/s", 1, true, false}, {"mibytes/s", 1, true, false},
+	{"giB/s", 2, true, false}, {"gibyte/s", 2, true, false}, {"gibytes/s", 2, true, false},
+	{"tiB/s", 3, true, false}, {"tibyte/s", 3, true, false}, {"tibytes/s", 3, true, false},
+	{"piB/s", 4, true, false}, {"pibyte/s", 4, true, false}, {"pibytes/s", 4, true, false},
+	{"eiB/s", 5, true, false}, {"eibyte/s", 5, true, false}, {"eibytes/s", 5, true, false},
+}
+
+// byteRateUnits3 is the table of the first words of the unit suffixes of
+// ByteRate consisting of three tokens, e.g. "kilobytes per second" and "kB per
+// second". Unlike byteCountUnits, the "B" of the abbreviations is matched
+// case-sensitively as in byteRateUnits, so that "kb per second" is not
+// accepted.
+var byteRateUnits3 = []scanUnit{
+	{"B", -1, false, false}, {"byte", -1, false, true}, {"bytes", -1, false, true},
+	{"kB", 0, false, false}, {"kilobyte", 0, false, true}, {"kilobytes", 0, false, true},
+	{"mB", 1, false, false}, {"megabyte", 1, false, true}, {"megabytes", 1, false, true},
+	{"gB", 2, false, false}, {"gigabyte", 2, false, true}, {"gigabytes", 2, false, true},
+	{"tB", 3, false, false}, {"terabyte", 3, false, true}, {"terabytes", 3, false, true},
+	{"pB", 4, false, false}, {"petabyte", 4, false, true}, {"petabytes", 4, false, true},
+	{"eB", 5, false, false}, {"exabyte", 5, false, true}, {"exabytes", 5, false, true},
+	{"kiB", 0, true, false}, {"kibibyte", 0, true, true}, {"kibibytes", 0, true, true},
+	{"miB", 1, true, false}, {"mebibyte", 1, true, true}, {"mebibytes", 1, true, true},
+	{"giB", 2, true, false}, {"gibibyte", 2, true, true}, {"gibibytes", 2, true, true},
+	{"tiB", 3, true, false}, {"tebibyte", 3, true, true}, {"tebibytes", 3, true, true},
+	{"piB", 4, true, false}, {"pebibyte", 4, true, true}, {"pebibytes", 4, true, true},
+	{"eiB", 5, true, false}, {"exbibyte", 5, true, true}, {"exbibytes", 5, true, true},
+}
+
+// Scan implements the Scanner interface in the package fmt to scan ByteRate
+// values from strings. This allows ByteRate values to be scanned from
+// human-readable string representations with unit suffixes using the standard
+// Scanf family functions in the package fmt; fmt.Scanf, fmt.Fscanf, and
+// fmt.Sscanf().
+//
+// For ByteRate type, four custom 'verbs' are implemented:
+//
+// 	%s, %u	human-readable formats with both SI and binary prefixes
+// 	%S, %U	treat SI prefix as binary prefix; 1 kB/s = 1024 B/s
+//
+// Note that, unlike Format, the %s verb can properly scan expressions with
+// units using both SI and binary prefixes.
+//
+// Therefore, it is usually recommended to scan using only the %s verb. The %S
+// verb is the same as %s, except that it treats the SI prefix as binary prefix.
+// That is, %S scans the expression "100 kB/s" as 100 KiB/s (=102400 B/s).
+//
+// For verbs %s and %S, unit suffix is mandatory. If the first token consists
+// only of digits, it is assumed that the next token is a unit suffix, with one
+// space in between. On the other hand, %u and %U do not allow expressions with
+// a space between digits and the unit suffix. They always scan only one token.
+// They assume that if the token consists only of digits, it is the number of
+// B/s.
+//
+// Unlike BitRate, the abbreviation "Bps" is not accepted, because it is easily
+// confused with "bps" which means bits per second. For the same reason, the "B"
+// of the abbreviations must be in upper case; "Mb/s" is not accepted, while the
+// case of the prefixes and the long unit names is ignored.
+//
+// The following verbs are compatible with float64 and scans floating point
+// values without a unit suffix. If it is clear that there is absolutely no unit
+// suffix in the input, the use of these is recommended:
+//
+// 	%f, %F	floating point representation
+//
+// See the package fmt documentation for details.
+func (br *ByteRate) Scan(state fmt.ScanState, verb rune) error {
+	switch verb {
+	case 'f', 'F':
+		tFmt := "%"
+		if wid, ok := state.Width(); ok {
+			tFmt += strconv.FormatInt(int64(wid), 10)
+		}
+		tFmt += string(verb)
+		ptr := (*float64)(br)
+		if _, err := fmt.Fscanf(state, tFmt, ptr); err != nil {
+			return fmt.Errorf("%%%c: no input: %w", verb, err)
+		}
+
+	case 's', 'S', 'u', 'U':
+		lx := lexer{state: state}
+		v, err := lx.scanFloat(verb, byteRateUnits, byteRateUnits3)
+		if err != nil {
+			return err
+		}
+		*br = ByteRate(v)
+
+	default:
+		return fmt.Errorf("unknown verb for ByteRate: %%%c", verb)
+	}
+	return nil
+}
+
+// ParseByteRate converts a human-readable string representation into a
+// ByteRate value. The human-readable string is a decimal number with a unit
+// suffix. SI and binary prefixes are correctly recognized.
+func ParseByteRate(s string) (ByteRate, error) {
+	v, err := parseFloat(s, "ByteRate", 's', byteRateUnits, byteRateUnits3)
+	if err != nil {
+		return 0, err
+	}
+	return ByteRate(v), nil
+}
+
+// ParseByteRateBinary is the same as ParseByteRate except that it treats the SI
+// prefixes as binary prefixes. That is, it parses "100 kB/s" as 100 KiB/s
+// (=102400 B/s).
+func ParseByteRateBinary(s string) (ByteRate, error) {
+	v, err := parseFloat(s, "ByteRate", 'S', byteRateUnits, byteRateUnits3)
+	if err != nil {
+		return 0, err
+	}
+	return ByteRate(v), nil
+}
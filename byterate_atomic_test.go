@@ -0,0 +1,52 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestAtomicLoadByteRate_1(t *testing.T) {
+	t.Parallel()
+
+	br := infounit.BytePerSecond * 30000.987
+	v := infounit.AtomicLoadByteRate(&br)
+
+	exbr := infounit.BytePerSecond * 30000.987
+	if v != exbr {
+		t.Errorf(`want: %s, got: %s`, exbr, br)
+	}
+}
+
+//
+func TestAtomicStoreByteRate_1(t *testing.T) {
+	t.Parallel()
+
+	br := infounit.BytePerSecond * 30000
+	infounit.AtomicStoreByteRate(&br, 12345.67)
+
+	exbr := infounit.BytePerSecond * 12345.67
+	if br != exbr {
+		t.Errorf(`want: %s, got: %s`, exbr, br)
+	}
+}
+
+//
+func TestAtomicSwapByteRate_1(t *testing.T) {
+	t.Parallel()
+
+	br := infounit.BytePerSecond * 30000
+	old := infounit.AtomicSwapByteRate(&br, 12345.67)
+
+	if exold := infounit.BytePerSecond * 30000; old != exold {
+		t.Errorf(`want: %s, got: %s`, exold, old)
+	}
+	if exbr := infounit.BytePerSecond * 12345.67; br != exbr {
+		t.Errorf(`want: %s, got: %s`, exbr, br)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestByteRate_Convert_1(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v float64
+		b infounit.ByteRate
+		f float64
+	}{
+		{987654321, infounit.BytePerSecond, 987654321.0},
+		{987654321, infounit.KilobytePerSecond, 987654.321},
+		{987654321, infounit.MegabytePerSecond, 987.654321},
+		{987654321, infounit.GigabytePerSecond, 0.987654321},
+		{987654321000000, infounit.BytePerSecond, 987654321000000},
+		{987654321000000, infounit.KilobytePerSecond, 987654321000},
+		{987654321000000, infounit.MegabytePerSecond, 987654321},
+		{987654321000000, infounit.GigabytePerSecond, 987654.321},
+		{987654321000000, infounit.TerabytePerSecond, 987.654321},
+		{987654321000000, infounit.PetabytePerSecond, 0.987654321},
+		{987654321000000, infounit.ExabytePerSecond, 0.000987654321},
+
+		{1200, infounit.KibibytePerSecond, 1.171875},
+		{1200 * 1024, infounit.MebibytePerSecond, 1.171875},
+		{1200 * 1024 * 1024, infounit.GibibytePerSecond, 1.171875},
+		{1200 * 1024 * 1024 * 1024, infounit.TebibytePerSecond, 1.171875},
+		{1200 * 1024 * 1024 * 1024 * 1024, infounit.PebibytePerSecond, 1.171875},
+		{1200 * 1024 * 1024 * 1024 * 1024 * 1024, infounit.ExbibytePerSecond, 1.171875},
+	}
+
+	for _, c := range tc {
+		bc := infounit.ByteRate(c.v)
+		f := bc.Convert(c.b)
+		// t.Logf(`%s: %s: %f"`, bc, c.b, f)
+		if f != c.f {
+			t.Errorf(`%f in %.0s: want: %f, got: %f`, bc, c.b, c.f, f)
+		}
+	}
+}
+
+//
+func TestByteRate_ConvertRound_1(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v float64
+		b infounit.ByteRate
+		f []float64
+	}{
+		{98765, infounit.BytePerSecond, []float64{98765, 98765, 98765, 98765}},
+		{987654321, infounit.KilobytePerSecond, []float64{987654, 987654.3, 987654.32, 987654.321}},
+		{987654321, infounit.MegabytePerSecond, []float64{988, 987.7, 987.65, 987.654}},
+		{987654321, infounit.GigabytePerSecond, []float64{1, 1.0, 0.99, 0.988}},
+	}
+
+	for _, c := range tc {
+		bc := infounit.ByteRate(c.v)
+		for p, ef := range c.f {
+			f := bc.ConvertRound(c.b, p)
+			// t.Logf(`%s: %s: %d: %f"`, bc, c.b, p, f)
+			if f != ef {
+				t.Errorf(`%f in %.0s p=%d: want: %f, got: %f`, bc, c.b, p, ef, f)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func ExampleByteRate_Format_printf() {
+	x := infounit.KilobytePerSecond * 123.456 // 123.456 kB/s
+
+	fmt.Printf("%v\n", x)     // default format, same as "% .1s"
+	fmt.Printf("%#v\n", x)    // Go syntax format
+	fmt.Printf("% s\n", x)    // SI prefix, with space
+	fmt.Printf("%# .2s\n", x) // long unit, with space
+	fmt.Printf("% .1S\n", x)  // binary prefix, precision 1
+	fmt.Printf("% s\n", x.BitRate())
+	// Output:
+	// 123.5 kB/s
+	// ByteRate(123456)
+	// 123.456 kB/s
+	// 123.46 kilobytes per second
+	// 120.6 KiB/s
+	// 987.648 kbit/s
+}
+
+//
+func ExampleByteCount_CalcByteRate() {
+	size := infounit.Gigabyte * 3
+	rate := size.CalcByteRate(time.Second * 20)
+	fmt.Printf("% .1s\n", rate)
+
+	tm, err := size.CalcTimeByteRate(infounit.MegabytePerSecond * 250)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(tm)
+	// Output:
+	// 150.0 MB/s
+	// 12s
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestByteRate_Format_1(t *testing.T) {
+	t.Parallel()
+
+	v := infounit.ByteRate(987654321.2345)
+	tc := []struct {
+		f string
+		s string
+	}{
+		{"%e", "9.876543e+08"},
+		{"%E", "9.876543E+08"},
+		{"[%30.14e]", "[          9.87654321234500e+08]"},
+		{"%f", "987654321.234500"},
+		{"%.0f", "987654321"},
+		{"%.3F", "987654321.235"},
+
+		{"%v", "987.7 MB/s"},
+		{"%#v", "ByteRate(987654321.2345)"},
+		{"%s", "987.6543212345MB/s"},
+		{"% s", "987.6543212345 MB/s"},
+		{"%.1s", "987.7MB/s"},
+		{"% .1s", "987.7 MB/s"},
+		{"%#s", "987.6543212345megabytes per second"},
+		{"%# .1s", "987.7 megabytes per second"},
+		{"%.2s", "987.65MB/s"},
+		{"%13.0s", "      988MB/s"},
+		{"%-13.s", "988MB/s      "},
+		{"%S", "941.9005596489907MiB/s"},
+		{"% .1S", "941.9 MiB/s"},
+		{"%#.1S", "941.9mebibytes per second"},
+		{"%# .6S", "941.900560 mebibytes per second"},
+		{"%a", "%!a(ByteRate=987654321.234500)"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, v)
+		if s != c.s {
+			t.Errorf(`fmt "%s": want: "%s", got: "%s"`, c.f, c.s, s)
+		}
+	}
+}
+
+//
+func TestByteRate_Format_2(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		b float64
+		s string // "% .1s", "% .1S", "%# .1s", "%# .1S"
+	}{
+		{0, "0.0 B/s, 0.0 B/s, 0.0 bytes per second, 0.0 bytes per second"},
+		{1, "1.0 B/s, 1.0 B/s, 1.0 byte per second, 1.0 byte per second"},
+		{777, "777.0 B/s, 777.0 B/s, 777.0 bytes per second, 777.0 bytes per second"},
+
+		{1000, "1.0 kB/s, 1000.0 B/s, 1.0 kilobyte per second, 1000.0 bytes per second"},
+		{1024, "1.0 kB/s, 1.0 KiB/s, 1.0 kilobytes per second, 1.0 kibibyte per second"},
+		{777777, "777.8 kB/s, 759.5 KiB/s, 777.8 kilobytes per second, 759.5 kibibytes per second"},
+
+		{1000 * 1000, "1.0 MB/s, 976.6 KiB/s, 1.0 megabyte per second, 976.6 kibibytes per second"},
+		{1024 * 1024, "1.0 MB/s, 1.0 MiB/s, 1.0 megabytes per second, 1.0 mebibyte per second"},
+
+		{1000 * 1000 * 1000, "1.0 GB/s, 953.7 MiB/s, 1.0 gigabyte per second, 953.7 mebibytes per second"},
+		{1024 * 1024 * 1024, "1.1 GB/s, 1.0 GiB/s, 1.1 gigabytes per second, 1.0 gibibyte per second"},
+
+		{1000 * 1000 * 1000 * 1000, "1.0 TB/s, 931.3 GiB/s, 1.0 terabyte per second, 931.3 gibibytes per second"},
+		{1000 * 1000 * 1000 * 1000 * 1000, "1.0 PB/s, 909.5 TiB/s, 1.0 petabyte per second, 909.5 tebibytes per second"},
+		{1024 * 1024 * 1024 * 1024 * 1024 * 1024, "1.2 EB/s, 1.0 EiB/s, 1.2 exabytes per second, 1.0 exbibyte per second"},
+	}
+
+	for _, c := range tc {
+		b := infounit.ByteRate(c.b)
+		es := strings.Split(c.s, ", ")
+		for i, f := range []string{"% .1s", "% .1S", "%# .1s", "%# .1S"} {
+			s := fmt.Sprintf(f, b)
+			if s != es[i] {
+				t.Errorf(`("%s", %#v): want: "%s", got: "%s"`, f, b, es[i], s)
+			}
+		}
+	}
+}
+
+//
+func TestByteRate_Format_carry(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v float64
+		f string
+		s string
+	}{
+		{999.94, "%.1s", "999.9B/s"},
+		{999.96, "%.1s", "1.0kB/s"},
+		{1023.96, "%.1S", "1.0KiB/s"},
+		{1023.96, "%.2S", "1023.96B/s"},
+		{999960, "%.1s", "1.0MB/s"},
+		{999960, "%# .1s", "1.0 megabytes per second"},
+		{1048535, "%.1S", "1.0MiB/s"},
+		{999999999999999999999, "%.1s", "1000.0EB/s"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.ByteRate(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %f: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestByteRate_Format_negative(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v float64
+		f string
+		s string
+	}{
+		{-5000000000, "%v", "-5.0 GB/s"},
+		{-5000000000, "% s", "-5 GB/s"},
+		{-5000000000, "%.2S", "-4.66GiB/s"},
+		{-5000000000, "%# .1s", "-5.0 gigabytes per second"},
+		{-1, "%# .1s", "-1.0 byte per second"},
+		{-999.96, "%.1s", "-1.0kB/s"},
+	}
+
+	for _, c := range tc {
+		s := fmt.Sprintf(c.f, infounit.ByteRate(c.v))
+		if s != c.s {
+			t.Errorf(`fmt "%s", %f: want: "%s", got: "%s"`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func BenchmarkByteRate_AppendFormat(b *testing.B) {
+	v := infounit.ByteRate(123456789)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = v.AppendFormat(buf[:0], nil)
+	}
+}
+
+//
+func BenchmarkByteRate_String(b *testing.B) {
+	v := infounit.ByteRate(123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = v.String()
+	}
+}
+
+//
+func BenchmarkByteRate_Sprintf(b *testing.B) {
+	v := infounit.ByteRate(123456789)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%# .2S", v)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestByteRate_Scan_1(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		src string
+		fmt string
+		br  infounit.ByteRate
+		es  string
+	}{
+		{"0B/s", "%s", infounit.BytePerSecond * 0, ""},
+		{"0 B/s", "%s", infounit.BytePerSecond * 0, ""},
+		{"0", "%u", infounit.BytePerSecond * 0, ""},
+		{"0", "%U", infounit.BytePerSecond * 0, ""},
+		{"9999.9999 B/s", "%s", infounit.BytePerSecond * 9999.9999, ""},
+		{"9999.9999 B/s", "%S", infounit.BytePerSecond * 9999.9999, ""},
+		{"-333.33 B/s", "%s", infounit.BytePerSecond * -333.33, ""},
+
+		{"110", "%u", infounit.BytePerSecond * 110, ""},
+		{"110B/S", "%u", infounit.BytePerSecond * 110, ""},
+		{"111byte/s", "%s", infounit.BytePerSecond * 111, ""},
+		{"111bytes/s", "%S", infounit.BytePerSecond * 111, ""},
+		{"112 B/s", "%s", infounit.BytePerSecond * 112, ""},
+		{"112 B/s", "%u", infounit.BytePerSecond * 112, ""}, // %u does not read "B/s"
+
+		{"210kB/s", "%s", infounit.KilobytePerSecond * 210, ""},
+		{"210kB/s", "%S", infounit.KibibytePerSecond * 210, ""},
+		{"210kB/s", "%U", infounit.KibibytePerSecond * 210, ""},
+		{"211 kB/s", "%s", infounit.KilobytePerSecond * 211, ""},
+		{"212kbyte/s", "%s", infounit.KilobytePerSecond * 212, ""},
+		{"213 kilobytes per second", "%s", infounit.KilobytePerSecond * 213, ""},
+		{"214kilobyte per sec", "%s", infounit.KilobytePerSecond * 214, ""},
+		{"215 kB per second", "%S", infounit.KibibytePerSecond * 215, ""},
+
+		{"310 MB/s", "%s", infounit.MegabytePerSecond * 310, ""},
+		{"311 MiB/s", "%s", infounit.MebibytePerSecond * 311, ""},
+		{"312 mebibytes per second", "%s", infounit.MebibytePerSecond * 312, ""},
+		{"410 GB/s", "%s", infounit.GigabytePerSecond * 410, ""},
+		{"411 GiB/s", "%s", infounit.GibibytePerSecond * 411, ""},
+		{"510 TB/s", "%s", infounit.TerabytePerSecond * 510, ""},
+		{"511 TiB/s", "%s", infounit.TebibytePerSecond * 511, ""},
+		{"610 PB/s", "%s", infounit.PetabytePerSecond * 610, ""},
+		{"611 PiB/s", "%s", infounit.PebibytePerSecond * 611, ""},
+		{"7 EB/s", "%s", infounit.ExabytePerSecond * 7, ""},
+		{"7 EiB/s", "%s", infounit.ExbibytePerSecond * 7, ""},
+		{"7.5 exbibytes per second", "%s", infounit.ExbibytePerSecond * 7.5, ""},
+
+		{"999", "%t", 0, "unknown verb for ByteRate: %t"},
+		{"", "%s", 0, "%s: no input"},
+		{"fast B/s", "%s", 0, "%s: invalid expr: fast"},
+		{"999", "%s", 0, "%s: no unit suffix: EOF"},
+		{"999 Mbps", "%s", 0, "%s: unknown unit: Mbps"},
+		{"999 Mbit/s", "%s", 0, "%s: unknown unit: Mbit/s"},
+		{"1.21 jigo watts", "%s", 0, "%s: unknown unit: jigo watts"},
+		{"999.999", "%f", infounit.BytePerSecond * 999.999, ""},
+	}
+
+	for _, c := range tc {
+		var br infounit.ByteRate
+		_, err := fmt.Sscanf(c.src, c.fmt, &br)
+		switch c.es {
+		case "": // expecting no error
+			switch {
+			case err != nil:
+				t.Errorf("src='%s', fmt='%s': %s", c.src, c.fmt, err)
+				continue
+			case br != c.br:
+				t.Errorf("src='%s', fmt='%s': want: %#v, got: %#v", c.src, c.fmt, c.br, br)
+				continue
+			}
+		default: // expecting error
+			switch {
+			case err == nil:
+				t.Errorf("src='%s', fmt='%s': error expected: got: %s", c.src, c.fmt, br)
+				continue
+			case err.Error() != c.es:
+				t.Errorf("src='%s', fmt='%s': error want: %s, got: %s", c.src, c.fmt, c.es, err.Error())
+				continue
+			}
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestByteRate_String(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		b infounit.ByteRate
+		s string
+	}{
+		{infounit.BytePerSecond * 0, "0.0 B/s"},
+		{infounit.BytePerSecond * 1, "1.0 B/s"},
+		{infounit.BytePerSecond * 2, "2.0 B/s"},
+		{infounit.BytePerSecond * 999, "999.0 B/s"},
+		{infounit.BytePerSecond * 987654321, "987.7 MB/s"},
+		{infounit.BytePerSecond * 9876543210, "9.9 GB/s"},
+		{infounit.ByteRate(18446744073709551615), "18.4 EB/s"},
+	}
+
+	for _, c := range tc {
+		s := c.b.String()
+		if s != c.s {
+			t.Errorf(`%f: want: %s, got: %s`, c.b, c.s, s)
+		}
+	}
+}
+
+//
+func TestByteRate_GoString(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		b infounit.ByteRate
+		s string
+	}{
+		{infounit.ByteRate(0), "ByteRate(0)"},
+		{infounit.BytePerSecond * 987654.321, "ByteRate(987654.321)"},
+		{infounit.BytePerSecond * 987654321.012, "ByteRate(987654321.012)"},
+	}
+
+	for _, c := range tc {
+		s := c.b.GoString()
+		if s != c.s {
+			t.Errorf(`%f: want: %s, got: %s`, c.b, c.s, s)
+		}
+	}
+}
+
+//
+func TestByteRate_BitRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		b  infounit.ByteRate
+		br infounit.BitRate
+	}{
+		{0, 0},
+		{1, 8},
+		{-1, -8},
+		{0.125, 1},
+		{infounit.MegabytePerSecond * 12.5, infounit.MegabitPerSecond * 100},
+		{infounit.GibibytePerSecond, infounit.GibibitPerSecond * 8},
+		{infounit.ByteRate(math.Inf(+1)), infounit.BitRate(math.Inf(+1))},
+	}
+
+	for _, c := range tc {
+		if br := c.b.BitRate(); br != c.br {
+			t.Errorf(`%v: want: %v, got: %v`, c.b, c.br, br)
+		}
+		if b := c.br.ByteRate(); b != c.b {
+			t.Errorf(`%v: want: %v, got: %v`, c.br, c.b, b)
+		}
+	}
+}
+
+//
+func TestByteRate_CalcByteCount(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		r   infounit.ByteRate
+		t   time.Duration
+		b   infounit.ByteCount
+		err error
+	}{
+		{0, 0, 0, nil},
+		{1000, time.Second, 1000, nil},
+		{infounit.KilobytePerSecond, time.Second * 1000, infounit.Megabyte, nil},
+		{infounit.BytePerSecond * 0.1, time.Second * 10, infounit.Byte, nil},
+		{infounit.KilobytePerSecond, -time.Second * 10, 0, infounit.ErrOutOfRange},
+		{1000, 0, 0, nil},
+		{infounit.ByteRate(math.NaN()), 1000, 0, nil},
+		{1000, -1000, 0, infounit.ErrOutOfRange},
+		{-1000, -time.Second, 1000, nil},
+		{infounit.ByteRate(math.Inf(+1)), time.Second, 0, infounit.ErrOutOfRange},
+	}
+
+	for _, c := range tc {
+		bc, err := c.r.CalcByteCount(c.t)
+		if err != c.err {
+			t.Errorf(`%v x %v: want(err): %v, got(err): %v`, c.r, c.t, c.err, err)
+		}
+		if bc != c.b {
+			t.Errorf(`%v x %v: want: %v, got: %v`, c.r, c.t, c.b, bc)
+		}
+	}
+}
+
+//
+func TestByteRate_CalcBitCount(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		r   infounit.ByteRate
+		t   time.Duration
+		b   infounit.BitCount
+		err error
+	}{
+		{0, 0, 0, nil},
+		{1000, time.Second, 8000, nil},
+		{infounit.KilobytePerSecond, time.Second * 1000, infounit.Megabit * 8, nil},
+		{infounit.KilobytePerSecond, -time.Second * 10, 0, infounit.ErrOutOfRange},
+		{infounit.ByteRate(math.Inf(-1)), time.Second, 0, infounit.ErrOutOfRange},
+	}
+
+	for _, c := range tc {
+		bc, err := c.r.CalcBitCount(c.t)
+		if err != c.err {
+			t.Errorf(`%v x %v: want(err): %v, got(err): %v`, c.r, c.t, c.err, err)
+		}
+		if bc != c.b {
+			t.Errorf(`%v x %v: want: %v, got: %v`, c.r, c.t, c.b, bc)
+		}
+	}
+}
+
+//
+func TestParseByteRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s string
+		r infounit.ByteRate
+		e bool
+	}{
+		{"", 0, true},
+		{"1B/s", 1, false},
+		{"9 B/s", 9, false},
+		{"0.77 byte/s", 0.77, false},
+		{"1.23 kilobytes per second", 1230, false},
+		{"1.5 MiB/s", 1572864, false},
+		{"-1 kB/s", -1000, false},
+		{"+1 kB/s", +1000, false},
+		{"1 Mbps", 0, true},
+		{"1 MBps", 0, true},
+		{"1 Mb/s", 0, true},
+		{"1 mb/s", 0, true},
+		{"1 b/s", 0, true},
+		{"1 kib/s", 0, true},
+		{"1 mB/s", 1000000, false},
+		{"1 KB/s", 1000, false},
+		{"1 KIB/s", 1024, false},
+		{"1.5 MEGABYTES PER SECOND", 1500000, false},
+		{"5 kB per second", 5000, false},
+		{"5 KiB per sec", 5120, false},
+		{"5 kb per second", 0, true},
+		{"5 Mib per second", 0, true},
+		{"5 b per second", 0, true},
+		{"5 mibibytes per second", 0, true},
+	}
+
+	for _, c := range tc {
+		br, err := infounit.ParseByteRate(c.s)
+		switch {
+		case c.e && err == nil:
+			t.Errorf(`%s: error expected, but nil error.`, c.s)
+		case !c.e && err != nil:
+			t.Errorf(`%s: unexpected error: %v`, c.s, err)
+		}
+		if br != c.r {
+			t.Errorf(`%s: want: %s, got: %s`, c.s, c.r, br)
+		}
+	}
+}
+
+//
+func TestParseByteRateBinary(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s string
+		r infounit.ByteRate
+		e bool
+	}{
+		{"", 0, true},
+		{"1B/s", 1, false},
+		{"1 kB/s", 1024, false},
+		{"1.5 kilobytes per second", 1536, false},
+		{"-1 kB/s", -1024, false},
+	}
+
+	for _, c := range tc {
+		br, err := infounit.ParseByteRateBinary(c.s)
+		switch {
+		case c.e && err == nil:
+			t.Errorf(`%s: error expected, but nil error.`, c.s)
+		case !c.e && err != nil:
+			t.Errorf(`%s: unexpected error: %v`, c.s, err)
+		}
+		if br != c.r {
+			t.Errorf(`%s: want: %s, got: %s`, c.s, c.r, br)
+		}
+	}
+}
+
+//
+func TestByteRate_Round(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		x, m  float64
+		trunc float64
+		round float64
+	}{
+		{0, 1000, 0, 0},
+		{123456.7, 1000, 123000, 123000},
+		{123500, 1000, 123000, 124000},
+		{-123500, 1000, -123000, -124000},
+		{-123456.7, 1000, -123000, -123000},
+		{987654321, 1 << 20, 941 << 20, 942 << 20},
+		{123456.7, 0, 123456.7, 123456.7},
+		{123456.7, -1000, 123456.7, 123456.7},
+		{123456.7, math.NaN(), 123456.7, 123456.7},
+		{math.Inf(+1), 1000, math.Inf(+1), math.Inf(+1)},
+	}
+
+	for _, c := range tc {
+		x, m := infounit.ByteRate(c.x), infounit.ByteRate(c.m)
+		if v := x.Truncate(m); v != infounit.ByteRate(c.trunc) {
+			t.Errorf(`%f.Truncate(%f): want: %f, got: %f`, c.x, c.m, c.trunc, v)
+		}
+		if v := x.Round(m); v != infounit.ByteRate(c.round) {
+			t.Errorf(`%f.Round(%f): want: %f, got: %f`, c.x, c.m, c.round, v)
+		}
+	}
+	if v := infounit.ByteRate(math.NaN()).Round(1000); !v.IsNaN() {
+		t.Errorf(`NaN.Round(1000): want: NaN, got: %f`, v)
+	}
+}
@@ -0,0 +1,551 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+/*
+Command infounit converts byte counts and bit rates between the raw numbers and
+the human-readable representations, in the same way as GNU numfmt, but with the
+parsing rules of the package infounit.
+
+Usage:
+
+	infounit [options] [value ...]
+
+As with numfmt, both --from and --to are none by default, so the values are
+printed unchanged unless the modes are given.
+
+Each argument is converted as a single value. If no argument is given, the
+lines are read from the standard input, and the selected fields of each line
+are converted, while the other parts are printed as is:
+
+	$ echo "1610612736 data.bin" | infounit --to=iec
+	1.5G data.bin
+	$ infounit --from=iec 4kB 1.5MB
+	4096
+	1572864
+	$ printf "SIZE NAME\n   1024 a\n1500000 b\n" | infounit --header --format="% h"
+	SIZE NAME
+	 1.02 kB a
+	1.50 MB b
+	$ infounit --rate --from=auto --to=si 125Mbit/s 1.5K
+	125M
+	1.5K
+
+The values are parsed with ParseByteCount, or ParseBitRate with --rate, so the
+unit symbols and names of the package such as "1.5 kB", "2GiB" and "10 Mbps"
+are accepted. The numbers without unit are the numbers of bytes, or bits per
+second, and the suffixes of numfmt without "B" such as "1.5K" are also
+accepted. The text following the value within a field is ignored, as the
+functions do.
+
+The options are:
+
+	--from=MODE     auto: the SI and binary prefixes as written, and the
+	                suffixes "K", "M", ... as powers of 1000 and "Ki", "Mi",
+	                ... as powers of 1024
+	                si: the same as auto, but the binary prefixes are rejected
+	                iec: the SI prefixes are also binary, e.g. "4kB" and "4K"
+	                as 4096, using ParseByteCountBinary or ParseBitRateBinary
+	                none: the numbers without unit only; the default
+	--to=MODE       none: the number of bytes, or bits per second; the
+	                default
+	                si: the powers of 1000 with the suffixes of numfmt, e.g.
+	                "1.5G"
+	                iec: the powers of 1024, e.g. "1.5G"
+	                iec-i: the powers of 1024 with "i", e.g. "1.5Gi"
+	--format=FMT    the Printf format with a single verb of infounit, such as
+	                "%.2S", "% h" and "size: %10d", to print the values with
+	                the units of the package instead of --to
+	--rate          the values are bit rates, e.g. "100Mbit/s", instead of
+	                byte counts
+	--field=FIELDS  the fields to convert, e.g. "1", "2,4", "2-4", "3-" and
+	                "-"; 1 by default
+	-d, --delimiter=X
+	                the field delimiter instead of the white spaces
+	--header[=N]    print the first N lines, 1 by default, without conversion
+	--padding=N     pad the converted fields to N characters; right-aligned if
+	                N is positive, left-aligned if negative. By default, the
+	                fields with leading white spaces are padded to the
+	                original width, the same as numfmt, keeping at least one
+	                space before the value
+	--invalid=MODE  what to do with the invalid values: abort, fail, warn or
+	                ignore, the same as numfmt; abort by default
+
+The options may follow the values, as numfmt, unless after "--". As with
+numfmt, the number of header lines must be given with "=": "--header 2" is
+--header with the value "2".
+
+The values are rounded up in the same way as numfmt with the default rounding
+method from-zero, e.g. 1001 bytes is "1.1K". The fractional bit rates less than
+1000, or 1024, are rounded to the nearest integer, half to even.
+
+The exit status is 2 if a value is invalid in the mode abort or fail, and 1 if
+the options are invalid.
+*/
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tunabay/go-infounit"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Exit statuses.
+const (
+	exitOK      = 0
+	exitUsage   = 1
+	exitInvalid = 2
+)
+
+// errAbort is returned by convertField and convertLine to stop the conversion.
+var errAbort = errors.New("abort")
+
+// headerFlag is the value of the --header option, which may be given without
+// the number of lines.
+type headerFlag int
+
+func (h *headerFlag) String() string { return strconv.Itoa(int(*h)) }
+
+func (h *headerFlag) IsBoolFlag() bool { return true }
+
+func (h *headerFlag) Set(s string) error {
+	if s == "true" {
+		*h = 1
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid number of header lines: %s", s)
+	}
+	*h = headerFlag(n)
+	return nil
+}
+
+// fieldRange is a range of the field numbers, which start at 1. hi is 0 if the
+// range has no upper bound.
+type fieldRange struct{ lo, hi int }
+
+// fieldList is the value of the --field option.
+type fieldList []fieldRange
+
+// parseFieldList parses a list of the field numbers and ranges such as "1",
+// "2,4", "2-4", "3-", "-2" and "-".
+func parseFieldList(s string) (fieldList, error) {
+	var fl fieldList
+	for _, part := range strings.Split(s, ",") {
+		lo, hi := part, part
+		if i := strings.IndexByte(part, '-'); 0 <= i {
+			lo, hi = part[:i], part[i+1:]
+		}
+		r := fieldRange{lo: 1}
+		var err error
+		if lo != "" {
+			if r.lo, err = strconv.Atoi(lo); err != nil || r.lo < 1 {
+				return nil, fmt.Errorf("invalid field: %s", part)
+			}
+		}
+		if hi != "" {
+			if r.hi, err = strconv.Atoi(hi); err != nil || r.hi < r.lo {
+				return nil, fmt.Errorf("invalid field: %s", part)
+			}
+		}
+		fl = append(fl, r)
+	}
+	return fl, nil
+}
+
+// contains returns whether the n-th field is selected.
+func (fl fieldList) contains(n int) bool {
+	for _, r := range fl {
+		if r.lo <= n && (r.hi == 0 || n <= r.hi) {
+			return true
+		}
+	}
+	return false
+}
+
+// binaryPrefixes are the lower-cased beginnings of the units with the binary
+// prefixes, which are rejected with --from=si.
+var binaryPrefixes = []string{
+	"kib", "mib", "gib", "tib", "pib", "eib",
+	"mebi", "tebi", "pebi", "exbi",
+}
+
+// hasBinaryPrefix returns whether the unit of the value has a binary prefix.
+func hasBinaryPrefix(s string) bool {
+	u := strings.ToLower(strings.TrimLeft(s, "+-.0123456789 "))
+	for _, p := range binaryPrefixes {
+		if strings.HasPrefix(u, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNumber returns whether the value is a number without unit.
+func isNumber(s string) bool {
+	return strings.Trim(s, "+-.0123456789") == "" && strings.ContainsAny(s, "0123456789")
+}
+
+// newParser returns the function to parse the values in the mode of --from.
+func newParser(from string, rate bool) func(string) (fmt.Formatter, error) {
+	return func(s string) (fmt.Formatter, error) {
+		switch {
+		case isNumber(s) && rate:
+			s += " bit/s"
+		case isNumber(s):
+			s += " B"
+		case from == "none":
+			return nil, fmt.Errorf("unit not allowed with --from=none: %s", s)
+		case from == "si" && hasBinaryPrefix(s):
+			return nil, fmt.Errorf("binary prefix not allowed with --from=si: %s", s)
+		}
+		var v fmt.Formatter
+		var err error
+		switch {
+		case rate && from == "iec":
+			v, err = infounit.ParseBitRateBinary(s)
+		case rate:
+			v, err = infounit.ParseBitRate(s)
+		case from == "iec":
+			v, err = infounit.ParseByteCountBinary(s)
+		default:
+			v, err = infounit.ParseByteCount(s)
+		}
+		var pe *infounit.ParseError
+		if err == nil || !errors.As(err, &pe) || pe.Reason != infounit.ReasonUnknownUnit {
+			return v, err
+		}
+
+		// the suffixes of numfmt, e.g. "1.5K" and "1.5Ki"
+		si := from != "iec"
+		if from == "auto" && strings.HasSuffix(s, "i") {
+			s, si = s[:len(s)-1], false
+		}
+		n, cerr := infounit.ParseCoreutilsByteCount(s, si)
+		switch {
+		case cerr != nil:
+			return nil, err
+		case rate:
+			return infounit.BitRate(n), nil
+		}
+		return n, nil
+	}
+}
+
+// human returns the representation of n in the same way as numfmt with the
+// mode of --to, which is si, iec or iec-i.
+func human(n uint64, neg bool, to string) string {
+	s := infounit.FormatCoreutilsByteCount(infounit.ByteCount(n), to == "si")
+	switch c := s[len(s)-1]; {
+	case c == 'k': // the --si option of coreutils prints "k"
+		s = s[:len(s)-1] + "K"
+	case to == "iec-i" && 'A' <= c:
+		s += "i"
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// humanRate returns the representation of the bit rate in the same way as
+// numfmt with the mode of --to.
+func humanRate(v infounit.BitRate, to string) (string, error) {
+	f, base := math.Abs(float64(v)), 1024.0
+	if to == "si" {
+		base = 1000
+	}
+	switch {
+	case math.IsNaN(f) || 1<<64 <= f:
+		return "", fmt.Errorf("out of range: %v", v)
+	case f < base:
+		f = math.RoundToEven(f)
+	default:
+		f = math.Ceil(f)
+	}
+	return human(uint64(f), v < 0, to), nil
+}
+
+// converter holds the options of the conversion.
+type converter struct {
+	parse   func(string) (fmt.Formatter, error)
+	to      string
+	format  string // overrides to if not empty
+	fields  fieldList
+	delim   string // empty for the white spaces
+	padding int
+	invalid string
+	stderr  io.Writer
+	failed  bool // an invalid value was found
+}
+
+// convert converts a single value.
+func (c *converter) convert(s string) (string, error) {
+	v, err := c.parse(s)
+	if err != nil {
+		return "", err
+	}
+	if c.format != "" {
+		return fmt.Sprintf(c.format, v), nil
+	}
+	switch v := v.(type) {
+	case infounit.ByteCount:
+		if c.to == "none" {
+			return strconv.FormatUint(uint64(v), 10), nil
+		}
+		return human(uint64(v), false, c.to), nil
+	case infounit.BitRate:
+		if c.to == "none" {
+			return strconv.FormatFloat(float64(v), 'f', -1, 64), nil
+		}
+		return humanRate(v, c.to)
+	}
+	return "", fmt.Errorf("unexpected value: %v", v)
+}
+
+// checkFormat returns an error unless the format has exactly one verb with the
+// optional flags, width and precision, e.g. "%.2S", "% h" and "size: %10d".
+// The width and precision given as arguments by '*' are not supported.
+func checkFormat(s string) error {
+	isDigit := func(c byte) bool { return '0' <= c && c <= '9' }
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		if i++; i < len(s) && s[i] == '%' {
+			continue
+		}
+		for i < len(s) && strings.IndexByte("+-# 0", s[i]) != -1 {
+			i++
+		}
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		if i < len(s) && s[i] == '.' {
+			for i++; i < len(s) && isDigit(s[i]); i++ {
+			}
+		}
+		switch {
+		case i == len(s):
+			return fmt.Errorf("format with no verb: %s", s)
+		case !('a' <= s[i] && s[i] <= 'z' || 'A' <= s[i] && s[i] <= 'Z'):
+			return fmt.Errorf("invalid verb %q in format: %s", s[i], s)
+		}
+		n++
+	}
+	if n != 1 {
+		return fmt.Errorf("format must have a single verb: %s", s)
+	}
+	return nil
+}
+
+// pad pads the converted value to the width. The value is right-aligned if
+// width is positive, and left-aligned if negative.
+func pad(s string, width int) string {
+	left := width < 0
+	if left {
+		width = -width
+	}
+	n := width - utf8.RuneCountInString(s)
+	if n <= 0 {
+		return s
+	}
+	if left {
+		return s + strings.Repeat(" ", n)
+	}
+	return strings.Repeat(" ", n) + s
+}
+
+// convertField converts a field, the leading white spaces of which are lead.
+// If the value is invalid, the field is returned as is unless the mode is
+// abort.
+func (c *converter) convertField(lead, tok string) (string, error) {
+	s, err := c.convert(tok)
+	if err != nil {
+		switch c.invalid {
+		case "abort":
+			fmt.Fprintf(c.stderr, "infounit: %v\n", err)
+			return "", errAbort
+		case "fail", "warn":
+			fmt.Fprintf(c.stderr, "infounit: %v\n", err)
+		}
+		c.failed = true
+		return lead + tok, nil
+	}
+	width := c.padding
+	if width == 0 && lead != "" { // keep at least one space before the value
+		width = utf8.RuneCountInString(lead + tok)
+		if n := utf8.RuneCountInString(s); width <= n {
+			return lead[:1] + s, nil
+		}
+	}
+	return pad(s, width), nil
+}
+
+// convertLine converts the selected fields of the line.
+func (c *converter) convertLine(line string) (string, error) {
+	var b strings.Builder
+	if c.delim != "" {
+		for i, f := range strings.Split(line, c.delim) {
+			if i != 0 {
+				b.WriteString(c.delim)
+			}
+			if c.fields.contains(i + 1) {
+				s, err := c.convertField("", f)
+				if err != nil {
+					return "", err
+				}
+				f = s
+			}
+			b.WriteString(f)
+		}
+		return b.String(), nil
+	}
+	isBlank := func(c byte) bool { return c == ' ' || c == '\t' }
+	for i, n := 0, 1; i < len(line); n++ {
+		start := i
+		for i < len(line) && isBlank(line[i]) {
+			i++
+		}
+		tok := i
+		for i < len(line) && !isBlank(line[i]) {
+			i++
+		}
+		if tok == i || !c.fields.contains(n) {
+			b.WriteString(line[start:i])
+			continue
+		}
+		lead := line[start:tok]
+		if c.padding != 0 && lead != "" {
+			// like numfmt, a single blank is kept before the padded field
+			// except the first one
+			if n != 1 {
+				b.WriteByte(lead[0])
+			}
+			lead = ""
+		}
+		s, err := c.convertField(lead, line[tok:i])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(s)
+	}
+	return b.String(), nil
+}
+
+// run runs the command with the arguments, and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("infounit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	from := fs.String("from", "none", "input mode: auto, si, iec or none")
+	to := fs.String("to", "none", "output mode: none, si, iec or iec-i")
+	format := fs.String("format", "", "Printf format with a single verb, e.g. \"% .2S\"")
+	rate := fs.Bool("rate", false, "convert bit rates instead of byte counts")
+	field := fs.String("field", "1", "fields to convert, e.g. \"1\", \"2,4\", \"2-4\"")
+	delim := fs.String("delimiter", "", "field delimiter instead of white spaces")
+	fs.StringVar(delim, "d", "", "shorthand for --delimiter")
+	padding := fs.Int("padding", 0, "pad the converted fields to N characters")
+	invalid := fs.String("invalid", "abort", "invalid values: abort, fail, warn or ignore")
+	var header headerFlag
+	fs.Var(&header, "header", "print the first N lines without conversion")
+
+	// Like numfmt, the options may follow the values, but not "--". The "--"
+	// given as the value of -d is not distinguished, e.g. "-d -- 1 --to=si".
+	var values []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return exitUsage
+		}
+		n := len(args) - fs.NArg()
+		if 0 < n && args[n-1] == "--" {
+			values = append(values, fs.Args()...)
+			break
+		}
+		if args = fs.Args(); len(args) == 0 {
+			break
+		}
+		values, args = append(values, args[0]), args[1:]
+	}
+
+	c := &converter{to: *to, delim: *delim, padding: *padding, invalid: *invalid, stderr: stderr}
+	usage := func(format string, a ...interface{}) int {
+		fmt.Fprintf(stderr, "infounit: "+format+"\n", a...)
+		return exitUsage
+	}
+	var err error
+	if c.fields, err = parseFieldList(*field); err != nil {
+		return usage("%v", err)
+	}
+	switch c.invalid {
+	case "abort", "fail", "warn", "ignore":
+	default:
+		return usage("invalid mode for --invalid: %s", c.invalid)
+	}
+
+	switch *from {
+	case "auto", "si", "iec", "none":
+		c.parse = newParser(*from, *rate)
+	default:
+		return usage("invalid mode for --from: %s", *from)
+	}
+	switch c.to {
+	case "none", "si", "iec", "iec-i":
+	default:
+		return usage("invalid mode for --to: %s", c.to)
+	}
+	if c.format = *format; c.format != "" {
+		if err := checkFormat(c.format); err != nil {
+			return usage("%v", err)
+		}
+	}
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	if len(values) != 0 {
+		for _, a := range values {
+			s, err := c.convertField("", a)
+			if err != nil {
+				return exitInvalid
+			}
+			fmt.Fprintln(w, s)
+		}
+	} else {
+		sc := bufio.NewScanner(stdin)
+		sc.Buffer(nil, 1<<20)
+		for n := 0; sc.Scan(); n++ {
+			line := sc.Text()
+			if n < int(header) {
+				fmt.Fprintln(w, line)
+				continue
+			}
+			s, err := c.convertLine(line)
+			if err != nil {
+				return exitInvalid
+			}
+			fmt.Fprintln(w, s)
+		}
+		if err := sc.Err(); err != nil {
+			fmt.Fprintf(stderr, "infounit: %v\n", err)
+			return exitInvalid
+		}
+	}
+	if c.failed && c.invalid == "fail" {
+		return exitInvalid
+	}
+	return exitOK
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+//
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		args   []string
+		stdin  string
+		stdout string
+		status int
+	}{
+		{[]string{"1500000"}, "", "1500000\n", exitOK},
+		{[]string{"--to=iec", "1610612736"}, "", "1.5G\n", exitOK},
+		{[]string{"--to=iec-i", "1610612736", "1000"}, "", "1.5Gi\n1000\n", exitOK},
+		{[]string{"--to=si", "999", "1000", "1001"}, "", "999\n1.0K\n1.1K\n", exitOK},
+		{[]string{"--from=auto", "1.5 GiB", "4kB", "100"}, "", "1610612736\n4000\n100\n", exitOK},
+		{[]string{"--from=iec", "--to=none", "4kB", "4K"}, "", "4096\n4096\n", exitOK},
+		{[]string{"--from=auto", "--to=none", "1.5K", "1.5Ki", "1 kilobyte"}, "", "1500\n1536\n1000\n", exitOK},
+		{[]string{"--from=si", "--to=none", "1.5K", "1.5kB"}, "", "1500\n1500\n", exitOK},
+		{[]string{"--from=si", "1.5KiB"}, "", "", exitInvalid},
+		{[]string{"--from=si", "1.5Ki"}, "", "", exitInvalid},
+		{[]string{"--from=si", "1 kibibyte"}, "", "", exitInvalid},
+		{[]string{"--from=none", "--to=none", "1024"}, "", "1024\n", exitOK},
+		{[]string{"1kB"}, "", "", exitInvalid},
+		{[]string{"--from=none", "1kB"}, "", "", exitInvalid},
+		{[]string{"--from=none", "1K"}, "", "", exitInvalid},
+		{[]string{"--format=%h", "123456789"}, "", "123MB\n", exitOK},
+		{[]string{"--format=% .2S", "--padding=10", "5000000"}, "", "  4.77 MiB\n", exitOK},
+		{[]string{"--format=%.1s", "--padding=-8", "5000"}, "", "5.0kB   \n", exitOK},
+		{[]string{"--format=%5d%%", "500"}, "", "  500%\n", exitOK},
+		{[]string{"--format=%% %-8.1s|", "5000"}, "", "% 5.0kB   |\n", exitOK},
+		{[]string{"--rate", "--from=auto", "--to=si", "125000000", "1.5K"}, "", "125M\n1.5K\n", exitOK},
+		{[]string{"--rate", "--to=si", "2.5", "2.7", "--", "-1500"}, "", "2\n3\n-1.5K\n", exitOK},
+		{[]string{"--rate", "--from=auto", "1.5 Gbit/s", "2.5"}, "", "1500000000\n2.5\n", exitOK},
+		{[]string{"--rate", "--from=auto", "--to=iec", "10Mbps"}, "", "9.6M\n", exitOK},
+		{[]string{"--rate", "--from=auto", "--format=%.1S", "10Mbps"}, "", "9.5Mibit/s\n", exitOK},
+
+		{[]string{"4kB", "--to=iec", "8kB", "--from=auto"}, "", "4.0K\n7.9K\n", exitOK},
+		{[]string{"--from=auto", "4kB", "--", "--to=iec"}, "", "4000\n", exitInvalid},
+		{[]string{"--", "-5"}, "", "", exitInvalid},
+		{[]string{"--header", "2"}, "", "2\n", exitOK},
+
+		{nil, "1610612736 data.bin\n", "1610612736 data.bin\n", exitOK},
+		{[]string{"--to=si"}, "1610612736 data.bin\n", "1.7G data.bin\n", exitOK},
+		{[]string{"--to=si"}, "   1024 a\n    1 b\n", "   1.1K a\n    1 b\n", exitOK},
+		{[]string{"--header", "--to=si"}, "SIZE NAME\n   1024 a\n", "SIZE NAME\n   1.1K a\n", exitOK},
+		{[]string{"--header=2", "--to=si"}, "A\nB\n1000\n", "A\nB\n1.0K\n", exitOK},
+		{[]string{"--header", "2"}, "A\nB\n1000\n", "2\n", exitOK},
+		{[]string{"--to=si", "--field=2,4-"}, "a 1000 b 2000 3000\n", "a 1.0K b 2.0K 3.0K\n", exitOK},
+		{[]string{"--to=si", "--field=-2"}, "1000 2000 3000\n", "1.0K 2.0K 3000\n", exitOK},
+		{[]string{"--to=si", "--field=2", "--padding=7"}, "x 1000\n", "x    1.0K\n", exitOK},
+		{[]string{"--to=si", "--padding=7"}, "   1000 y\n", "   1.0K y\n", exitOK},
+		{[]string{"--to=si", "--padding=-7"}, "   1000 y\n", "1.0K    y\n", exitOK},
+		{[]string{"-d", ",", "--field=2", "--to=iec"}, "a,2048,x\n", "a,2.0K,x\n", exitOK},
+		{[]string{"--to=si", "--delimiter=:", "--field=-"}, "1000:2000\n", "1.0K:2.0K\n", exitOK},
+
+		{[]string{"--to=si"}, "1000\nx\n2000\n", "1.0K\n", exitInvalid},
+		{[]string{"--to=si", "--invalid=fail"}, "1000\nx\n2000\n", "1.0K\nx\n2.0K\n", exitInvalid},
+		{[]string{"--to=si", "--invalid=warn"}, "1000\nx\n2000\n", "1.0K\nx\n2.0K\n", exitOK},
+		{[]string{"--invalid=ignore"}, "-5\n", "-5\n", exitOK},
+
+		{[]string{"--to=foo"}, "", "", exitUsage},
+		{[]string{"--from=foo"}, "", "", exitUsage},
+		{[]string{"--field=0"}, "", "", exitUsage},
+		{[]string{"--field=3-2"}, "", "", exitUsage},
+		{[]string{"--invalid=foo"}, "", "", exitUsage},
+		{[]string{"--format=%s %s"}, "", "", exitUsage},
+		{[]string{"--format=%%"}, "", "", exitUsage},
+		{[]string{"--format=100%%s"}, "", "", exitUsage},
+		{[]string{"--format=%*s"}, "", "", exitUsage},
+		{[]string{"--format=%5."}, "", "", exitUsage},
+		{[]string{"--format=%[1]s"}, "", "", exitUsage},
+		{[]string{"--header=x"}, "", "", exitUsage},
+		{[]string{"1000", "--header=x"}, "", "", exitUsage},
+		{[]string{"--unknown"}, "", "", exitUsage},
+	}
+
+	for _, c := range tc {
+		var stdout, stderr bytes.Buffer
+		status := run(c.args, strings.NewReader(c.stdin), &stdout, &stderr)
+		if status != c.status || stdout.String() != c.stdout {
+			t.Errorf("%q, %q: want: %d, %q, got: %d, %q (%s)", c.args, c.stdin, c.status, c.stdout, status, stdout.String(), stderr.String())
+		}
+		if (status == exitOK) != (stderr.Len() == 0) && !strings.Contains(strings.Join(c.args, " "), "warn") {
+			t.Errorf("%q, %q: unexpected stderr: %q", c.args, c.stdin, stderr.String())
+		}
+	}
+}
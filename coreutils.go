@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"strconv"
+)
+
+// coreutilsSuffixes are the suffixes of the human-readable sizes of the GNU
+// coreutils, indexed by the power of the base minus 1. Only the kilo of the SI
+// prefixes is in lower case.
+var coreutilsSuffixes = [2][6]byte{
+	{'K', 'M', 'G', 'T', 'P', 'E'},
+	{'k', 'M', 'G', 'T', 'P', 'E'},
+}
+
+// ParseCoreutilsByteCount converts a size string printed by the -h option of
+// the GNU coreutils such as ls, du and df, into a ByteCount value, e.g. "4.0K",
+// "1.5M" or "23G". The syntax is a non-negative decimal number followed by an
+// optional suffix K, M, G, T, P or E without space, and no "B". If si is true,
+// the suffixes are powers of 1000, as printed with the --si option; otherwise,
+// they are powers of 1024. The suffix "k" is also accepted in both cases. The
+// fractional bytes are rounded up, in the same direction as the commands round
+// the sizes.
+func ParseCoreutilsByteCount(s string, si bool) (ByteCount, error) {
+	lx := lexer{s: s, typ: "ByteCount"}
+	t, _ := lexNumber(s, false)
+	if t.num == "" {
+		tok := s[:tokenEnd(s, 0, len(s))]
+		reason := ReasonBadNumber
+		if tok != "" && tok[0] == '-' {
+			if t, _ := lexNumber(tok[1:], false); t.num != "" {
+				reason = ReasonNegative // e.g. "-5K"
+			}
+		}
+		return 0, lx.errorf(reason, 0, tok, nil, "invalid expr: %s", tok)
+	}
+	lx.pos = len(t.num)
+	p := binPrefix
+	if si {
+		p = siPrefix
+	}
+	unit := uint64(1)
+	if t.unit != "" {
+		unit = 0
+		for i, c := range coreutilsSuffixes[0] {
+			if len(t.unit) == 1 && (t.unit[0] == c || t.unit[0] == coreutilsSuffixes[1][i]) {
+				unit = p.thresholds[i]
+				break
+			}
+		}
+		if unit == 0 {
+			return 0, lx.errorf(ReasonUnknownUnit, lx.pos, t.unit, nil, "unknown unit for coreutils: %s", t.unit)
+		}
+	}
+	v, err := mulDecimal(t.integer, t.frac, unit, RoundCeil)
+	if err != nil {
+		return 0, lx.errorf(numErrReason(err), 0, t.num, err, "%v", err)
+	}
+	return ByteCount(v), nil
+}
+
+// FormatCoreutilsByteCount returns the representation of the ByteCount value
+// in exactly the same way as the -h option of the GNU coreutils such as ls, du
+// and df, e.g. "4.0K", "1.5M" and "23G". The values less than 1024 are printed
+// as the number of bytes without suffix. The other values are printed with one
+// decimal place if less than 10, and without decimal places otherwise, always
+// rounded up; e.g. 1025 bytes is "1.1K", and 1048575 bytes is "1.0M". If si is
+// true, the powers of 1000 are used as the --si option, e.g. "1.5k".
+func FormatCoreutilsByteCount(v ByteCount, si bool) string {
+	base, suffixes := uint64(kibi), &coreutilsSuffixes[0]
+	if si {
+		base, suffixes = kilo, &coreutilsSuffixes[1]
+	}
+
+	// The same algorithm as human_readable in gnulib with human_ceiling.
+	// tenths is the first digit of the remainder, and rounding is whether
+	// the rest of the remainder is 0, less than, exactly or more than half.
+	amt, tenths, rounding, exp := uint64(v), uint64(0), uint64(0), 0
+	point := false
+	for base <= amt && exp < len(suffixes) {
+		r10 := amt%base*10 + tenths
+		r2 := r10%base*2 + rounding>>1
+		amt /= base
+		tenths = r10 / base
+		switch {
+		case r2 < base && r2+rounding == 0:
+			rounding = 0
+		case r2 < base:
+			rounding = 1
+		case r2+rounding <= base:
+			rounding = 2
+		default:
+			rounding = 3
+		}
+		exp++
+	}
+	if 0 < exp && amt < 10 {
+		if 0 < rounding {
+			tenths++
+			rounding = 0
+			if tenths == 10 {
+				amt++
+				tenths = 0
+			}
+		}
+		if amt < 10 {
+			point = true
+		}
+	}
+	if !point && 0 < tenths+rounding {
+		amt++
+		if amt == base && exp < len(suffixes) {
+			amt, tenths, point = 1, 0, true
+			exp++
+		}
+	}
+
+	dst := make([]byte, 0, 24)
+	dst = strconv.AppendUint(dst, amt, 10)
+	if point {
+		dst = append(dst, '.', byte('0'+tenths))
+	}
+	if 0 < exp {
+		dst = append(dst, suffixes[exp-1])
+	}
+	return string(dst)
+}
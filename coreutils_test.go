@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestFormatCoreutilsByteCount(t *testing.T) {
+	t.Parallel()
+
+	// the outputs of "ls -lh" and "ls -l --si" of GNU coreutils 9.1
+	tc := []struct {
+		v  uint64
+		h  string
+		si string
+	}{
+		{0, "0", "0"},
+		{1, "1", "1"},
+		{999, "999", "999"},
+		{1000, "1000", "1.0k"},
+		{1023, "1023", "1.1k"},
+		{1024, "1.0K", "1.1k"},
+		{1025, "1.1K", "1.1k"},
+		{1536, "1.5K", "1.6k"},
+		{9949, "9.8K", "10k"},
+		{9950, "9.8K", "10k"},
+		{10000, "9.8K", "10k"},
+		{10239, "10K", "11k"},
+		{10240, "10K", "11k"},
+		{10241, "11K", "11k"},
+		{99999, "98K", "100k"},
+		{102400, "100K", "103k"},
+		{999999, "977K", "1.0M"},
+		{1000000, "977K", "1.0M"},
+		{1000001, "977K", "1.1M"},
+		{1047552, "1023K", "1.1M"},
+		{1048063, "1.0M", "1.1M"},
+		{1048575, "1.0M", "1.1M"},
+		{1048576, "1.0M", "1.1M"},
+		{9999999, "9.6M", "10M"},
+		{123456789, "118M", "124M"},
+		{1073741823, "1.0G", "1.1G"},
+		{5368709120, "5.0G", "5.4G"},
+		{1099511627775, "1.0T", "1.1T"},
+		{1152921504606846975, "1.0E", "1.2E"},
+		{18446744073709551615, "16E", "19E"},
+	}
+
+	for _, c := range tc {
+		if s := infounit.FormatCoreutilsByteCount(infounit.ByteCount(c.v), false); s != c.h {
+			t.Errorf("%d: want: %q, got: %q", c.v, c.h, s)
+		}
+		if s := infounit.FormatCoreutilsByteCount(infounit.ByteCount(c.v), true); s != c.si {
+			t.Errorf("%d, si: want: %q, got: %q", c.v, c.si, s)
+		}
+	}
+}
+
+//
+func TestParseCoreutilsByteCount(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s      string
+		si     bool
+		v      infounit.ByteCount
+		reason infounit.ParseErrorReason
+	}{
+		{"0", false, 0, 0},
+		{"1023", false, 1023, 0},
+		{"4.0K", false, infounit.Kibibyte * 4, 0},
+		{"1.5M", false, infounit.Mebibyte * 3 / 2, 0},
+		{"23G", false, infounit.Gibibyte * 23, 0},
+		{"16E", false, 0, infounit.ReasonOutOfRange},
+		{"15E", false, infounit.Exbibyte * 15, 0},
+		{"1.1K", false, 1127, 0},
+		{"1.0k", true, infounit.Kilobyte, 0},
+		{"1.0K", true, infounit.Kilobyte, 0},
+		{"124M", true, infounit.Megabyte * 124, 0},
+		{"1.2E", true, infounit.Exabyte * 6 / 5, 0},
+		{"19E", true, 0, infounit.ReasonOutOfRange},
+		{"1.5m", false, 0, infounit.ReasonUnknownUnit},
+		{"4.0KB", false, 0, infounit.ReasonUnknownUnit},
+		{"4.0KiB", false, 0, infounit.ReasonUnknownUnit},
+		{"4.0 K", false, 0, infounit.ReasonUnknownUnit},
+		{"1Z", false, 0, infounit.ReasonUnknownUnit},
+		{"-1K", false, 0, infounit.ReasonNegative},
+		{"", false, 0, infounit.ReasonBadNumber},
+		{"K", false, 0, infounit.ReasonBadNumber},
+	}
+
+	for _, c := range tc {
+		v, err := infounit.ParseCoreutilsByteCount(c.s, c.si)
+		if c.reason != 0 {
+			var pe *infounit.ParseError
+			if !errors.As(err, &pe) || pe.Reason != c.reason {
+				t.Errorf("%q, %v: want reason %v, got: %v", c.s, c.si, c.reason, err)
+			}
+			continue
+		}
+		if err != nil || v != c.v {
+			t.Errorf("%q, %v: want: %d, got: %d, %v", c.s, c.si, c.v, v, err)
+		}
+	}
+
+	// the sizes are rounded up, so they are never parsed into smaller values
+	for _, v := range []uint64{0, 1000, 1024, 1536, 10241, 1048575, 18446744073709551615} {
+		for _, si := range []bool{false, true} {
+			s := infounit.FormatCoreutilsByteCount(infounit.ByteCount(v), si)
+			p, err := infounit.ParseCoreutilsByteCount(s, si)
+			if err != nil && !(v == 18446744073709551615 && errors.Is(err, infounit.ErrOutOfRange)) {
+				t.Errorf("%d, %v: %q: unexpected error: %v", v, si, s, err)
+			} else if err == nil && p < infounit.ByteCount(v) {
+				t.Errorf("%d, %v: %q: parsed into smaller value %d", v, si, s, p)
+			}
+		}
+	}
+	const es = "invalid byte count: 19E: out of range"
+	if _, err := infounit.ParseCoreutilsByteCount("19E", true); err == nil || err.Error() != es {
+		t.Errorf("want: %q, got: %v", es, err)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"math/bits"
+	"strconv"
+)
+
+// RoundingMode specifies how a decimal value that is not a whole number of
+// bytes or bits is converted to an integer count. The zero value is
+// RoundNearest.
+type RoundingMode int
+
+// Rounding modes.
+const (
+	RoundNearest RoundingMode = iota // round to nearest, half away from zero
+	RoundFloor                       // round toward zero
+	RoundCeil                        // round away from zero
+	RoundExact                       // do not round, fail with ErrFractional
+)
+
+// String returns the name of the rounding mode. This implements the Stringer
+// interface in the package fmt.
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundNearest:
+		return "RoundNearest"
+	case RoundFloor:
+		return "RoundFloor"
+	case RoundCeil:
+		return "RoundCeil"
+	case RoundExact:
+		return "RoundExact"
+	}
+	return "RoundingMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// mulDecimal multiplies the decimal number intExpr.fracExpr by unit and returns
+// the product rounded to an integer with the specified rounding mode. intExpr
+// and fracExpr are strings consisting only of the digits 0-9, and either of
+// them may be empty. The calculation is done entirely with integer arithmetic,
+// so the result is exact regardless of the number of digits. ErrOutOfRange is
+// returned if the result overflows uint64, and ErrFractional is returned if the
+// mode is RoundExact and the result is not an integer.
+func mulDecimal(intExpr, fracExpr string, unit uint64, mode RoundingMode) (uint64, error) {
+	var iv uint64
+	if intExpr != "" {
+		v, err := parseUint64(intExpr)
+		if err != nil {
+			return 0, err
+		}
+		iv = v
+	}
+	ip, err := mulUint64(iv, unit)
+	if err != nil {
+		return 0, err
+	}
+
+	for 0 < len(fracExpr) && fracExpr[len(fracExpr)-1] == '0' {
+		fracExpr = fracExpr[:len(fracExpr)-1]
+	}
+	if fracExpr == "" {
+		return ip, nil
+	}
+
+	// The product of the fraction and unit is computed from the last digit,
+	// as (d1*unit + (d2*unit + (... + dn*unit/10 ...)/10)/10)/10, keeping
+	// the integer part of each term in 128 bits. Since the integer part of
+	// a term is the integer part of the sum of the digit times unit and the
+	// integer part of the next term divided by 10, the result is exact for
+	// any number of digits. sticky records whether any of the discarded
+	// remainders is nonzero.
+	var hi, lo, r uint64
+	sticky := false
+	for i := len(fracExpr) - 1; 0 <= i; i-- {
+		hi, lo, r = div128by10(hi, lo)
+		sticky = sticky || r != 0
+		h, l := bits.Mul64(uint64(fracExpr[i]-'0'), unit)
+		var c uint64
+		lo, c = bits.Add64(lo, l, 0)
+		hi += h + c
+	}
+	_, q, r := div128by10(hi, lo) // the quotient is less than unit
+
+	switch mode {
+	case RoundFloor:
+	case RoundCeil:
+		if r != 0 || sticky {
+			q++
+		}
+	case RoundExact:
+		if r != 0 || sticky {
+			return 0, ErrFractional
+		}
+	default:
+		if 5 <= r {
+			q++
+		}
+	}
+
+	v, carry := bits.Add64(ip, q, 0)
+	if carry != 0 {
+		return 0, ErrOutOfRange
+	}
+	return v, nil
+}
+
+// div128by10 divides the 128-bit integer hi:lo by 10, and returns the quotient
+// and the remainder.
+func div128by10(hi, lo uint64) (qhi, qlo, r uint64) {
+	qhi, r = hi/10, hi%10
+	qlo, r = bits.Div64(r, lo, 10)
+	return qhi, qlo, r
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package infounit_test
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+// FuzzParseByteCountRound compares the results with big.Rat. The units without
+// prefix are not used, since fractional bytes are rejected for them.
+func FuzzParseByteCountRound(f *testing.F) {
+	units := []struct {
+		name string
+		unit uint64
+	}{
+		{"kB", 1000},
+		{"KiB", 1 << 10},
+		{"MB", 1000000},
+		{"GiB", 1 << 30},
+		{"TB", 1000000000000},
+		{"PiB", 1 << 50},
+		{"EB", 1000000000000000000},
+		{"EiB", 1 << 60},
+	}
+	f.Add("0", "0661076664518200065999999", uint8(7), uint8(infounit.RoundFloor))
+	f.Add("0", "16391053511994274151528", uint8(7), uint8(infounit.RoundNearest))
+	f.Add("15", "999999999999999999", uint8(7), uint8(infounit.RoundCeil))
+	f.Add("1", "5", uint8(0), uint8(infounit.RoundExact))
+	f.Fuzz(func(t *testing.T, integer, frac string, ui, mode uint8) {
+		if integer == "" || strings.Trim(integer, "0123456789") != "" || strings.Trim(frac, "0123456789") != "" {
+			return
+		}
+		u := units[int(ui)%len(units)]
+		m := infounit.RoundingMode(mode % 4)
+		s := integer
+		if frac != "" {
+			s += "." + frac
+		}
+		s += " " + u.name
+
+		want, ok := new(big.Rat).SetString(integer + "." + frac + "0")
+		if !ok {
+			t.Fatalf("%s: big.Rat.SetString failed", s)
+		}
+		want.Mul(want, new(big.Rat).SetInt(new(big.Int).SetUint64(u.unit)))
+		q, r := new(big.Int).QuoRem(want.Num(), want.Denom(), new(big.Int))
+		r2 := new(big.Int).Lsh(r, 1)
+		var wantErr error
+		switch {
+		case !q.IsUint64():
+			wantErr = infounit.ErrOutOfRange
+		case m == infounit.RoundExact && r.Sign() != 0:
+			wantErr = infounit.ErrFractional
+		case m == infounit.RoundCeil && r.Sign() != 0,
+			m == infounit.RoundNearest && want.Denom().Cmp(r2) <= 0:
+			q.Add(q, big.NewInt(1))
+		}
+		if wantErr == nil && !q.IsUint64() {
+			wantErr = infounit.ErrOutOfRange
+		}
+
+		v, err := infounit.ParseByteCountRound(s, m)
+		switch {
+		case wantErr != nil && !errors.Is(err, wantErr):
+			t.Errorf("%s, %v: want(err): %v, got: %d, %v", s, m, wantErr, v, err)
+		case wantErr == nil && err != nil:
+			t.Errorf("%s, %v: want: %v, got error: %v", s, m, q, err)
+		case wantErr == nil && uint64(v) != q.Uint64():
+			t.Errorf("%s, %v: want: %v, got: %d", s, m, q, v)
+		}
+	})
+}
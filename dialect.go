@@ -0,0 +1,221 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"math"
+	"strconv"
+)
+
+// Dialect specifies the size syntax of the configuration of a software, used
+// by ParseByteCountDialect and FormatByteCountDialect. All the dialects use
+// the powers of 1024 regardless of the suffixes.
+type Dialect int
+
+// Dialects.
+const (
+	// DialectJVM is the syntax of the JVM options such as -Xmx; an integer
+	// with an optional suffix k, m, g or t in either case, e.g. "2g".
+	DialectJVM Dialect = iota + 1
+
+	// DialectDocker is the syntax of the Docker memory limits such as the
+	// --memory option of docker run, parsed by RAMInBytes of go-units; a
+	// decimal number with an optional suffix b, k, m, g, t or p in either
+	// case, optionally followed by "i" and "b", and optionally preceded by
+	// a space, e.g. "512m", "512MB" or "1.5 GiB". The fractional bytes are
+	// truncated.
+	DialectDocker
+
+	// DialectSystemd is the syntax of the systemd resource limits such as
+	// MemoryMax; a decimal number with an optional suffix B, K, M, G, T, P
+	// or E in upper case, e.g. "1G". The fractional bytes are truncated.
+	// "infinity" is the maximum value of ByteCount.
+	DialectSystemd
+
+	// DialectPostgreSQL is the syntax of the PostgreSQL memory parameters
+	// such as shared_buffers; a decimal number with a mandatory unit B, kB,
+	// MB, GB or TB, which are case-sensitive, optionally preceded by
+	// spaces, e.g. "128MB". The fractional bytes are rounded to nearest.
+	DialectPostgreSQL
+
+	// DialectNginx is the syntax of the nginx sizes such as
+	// client_max_body_size; an integer with an optional suffix k, m or g in
+	// either case, e.g. "10m".
+	DialectNginx
+)
+
+// String returns the name of the dialect. This implements the Stringer
+// interface in the package fmt.
+func (d Dialect) String() string {
+	switch d {
+	case DialectJVM:
+		return "JVM"
+	case DialectDocker:
+		return "Docker"
+	case DialectSystemd:
+		return "systemd"
+	case DialectPostgreSQL:
+		return "PostgreSQL"
+	case DialectNginx:
+		return "nginx"
+	}
+	return "Dialect(" + strconv.Itoa(int(d)) + ")"
+}
+
+// dialectUnit is an entry of the unit tables of the dialects.
+type dialectUnit struct {
+	name string // e.g. "k", "MB"
+	exp  int    // power of 1024; e.g. 1 for kibi
+}
+
+// dialectSpec holds the syntax of a dialect.
+type dialectSpec struct {
+	units    []dialectUnit
+	fold     bool         // the unit names are case-insensitive
+	frac     bool         // the number may have a fractional part
+	spaces   int          // max number of spaces before the unit; -1 for any
+	needUnit bool         // the unit is mandatory
+	infinity string       // the name of the maximum value, if any
+	mode     RoundingMode // to round the fractional bytes
+	suffixes []string     // for formatting, indexed by the power of 1024
+}
+
+// dockerUnits returns the unit table of DialectDocker, which accepts all the
+// combinations like "m", "mb", "mi" and "mib", and also "b", "i" and "ib"
+// without prefix.
+func dockerUnits() []dialectUnit {
+	units := []dialectUnit{{"b", 0}, {"i", 0}, {"ib", 0}}
+	for i, p := range []string{"k", "m", "g", "t", "p"} {
+		for _, s := range []string{"", "b", "i", "ib"} {
+			units = append(units, dialectUnit{p + s, i + 1})
+		}
+	}
+	return units
+}
+
+// dialectSpecs holds the syntax of the dialects.
+var dialectSpecs = map[Dialect]*dialectSpec{
+	DialectJVM: {
+		units:    []dialectUnit{{"k", 1}, {"m", 2}, {"g", 3}, {"t", 4}},
+		fold:     true,
+		suffixes: []string{"", "k", "m", "g", "t"},
+	},
+	DialectDocker: {
+		units:    dockerUnits(),
+		fold:     true,
+		frac:     true,
+		spaces:   1,
+		mode:     RoundFloor,
+		suffixes: []string{"", "k", "m", "g", "t", "p"},
+	},
+	DialectSystemd: {
+		units: []dialectUnit{
+			{"B", 0}, {"K", 1}, {"M", 2}, {"G", 3}, {"T", 4}, {"P", 5}, {"E", 6},
+		},
+		frac:     true,
+		infinity: "infinity",
+		mode:     RoundFloor,
+		suffixes: []string{"", "K", "M", "G", "T", "P", "E"},
+	},
+	DialectPostgreSQL: {
+		units:    []dialectUnit{{"B", 0}, {"kB", 1}, {"MB", 2}, {"GB", 3}, {"TB", 4}},
+		frac:     true,
+		spaces:   -1,
+		needUnit: true,
+		mode:     RoundNearest,
+		suffixes: []string{"B", "kB", "MB", "GB", "TB"},
+	},
+	DialectNginx: {
+		units:    []dialectUnit{{"k", 1}, {"m", 2}, {"g", 3}},
+		fold:     true,
+		suffixes: []string{"", "k", "m", "g"},
+	},
+}
+
+// ParseByteCountDialect converts a size string in the syntax of the dialect
+// into a ByteCount value, e.g. "2g" (2 GiB) for DialectJVM and "1G" (1 GiB)
+// for DialectSystemd. The whole string must be a single size; no spaces are
+// allowed before or after it. For an unknown dialect, a *ParseError with
+// ReasonDisallowed is returned.
+func ParseByteCountDialect(s string, d Dialect) (ByteCount, error) {
+	lx := lexer{s: s, typ: "ByteCount"}
+	spec, ok := dialectSpecs[d]
+	if !ok {
+		return 0, lx.errorf(ReasonDisallowed, 0, "", nil, "unknown dialect: %v", d)
+	}
+	if spec.infinity != "" && s == spec.infinity {
+		return ByteCount(math.MaxUint64), nil
+	}
+
+	var t numToken
+	t.integer = s[:digitsEnd(s, 0)]
+	lx.pos = len(t.integer)
+	if spec.frac && lx.pos+1 < len(s) && s[lx.pos] == '.' && digitsEnd(s, lx.pos+1) != lx.pos+1 {
+		end := digitsEnd(s, lx.pos+1)
+		t.frac = s[lx.pos+1 : end]
+		lx.pos = end
+	}
+	t.num = s[:lx.pos]
+	if t.integer == "" {
+		tok := s[:tokenEnd(s, 0, len(s))]
+		reason := ReasonBadNumber
+		if tok != "" && tok[0] == '-' {
+			reason = ReasonNegative
+		}
+		return 0, lx.errorf(reason, 0, tok, nil, "invalid expr: %s", tok)
+	}
+
+	for n := 0; lx.pos < len(s) && s[lx.pos] == ' ' && n != spec.spaces; n++ {
+		lx.pos++
+	}
+	t.unit = s[lx.pos:]
+	exp := -1
+	for _, u := range spec.units {
+		if u.name == t.unit || spec.fold && equalFoldASCII(u.name, t.unit) {
+			exp = u.exp
+			break
+		}
+	}
+	switch {
+	case t.unit == "" && spec.needUnit:
+		return 0, lx.errorf(ReasonMissingUnit, lx.pos, "", nil, "no unit suffix")
+	case t.unit == "":
+		exp = 0
+	case exp < 0:
+		return 0, lx.errorf(ReasonUnknownUnit, lx.pos, t.unit, nil, "unknown unit for %v: %s", d, t.unit)
+	}
+
+	unit := uint64(1)
+	if 0 < exp {
+		unit = binPrefix.thresholds[exp-1]
+	}
+	v, err := mulDecimal(t.integer, t.frac, unit, spec.mode)
+	if err != nil {
+		return 0, lx.errorf(numErrReason(err), 0, t.num, err, "%v", err)
+	}
+	return ByteCount(v), nil
+}
+
+// FormatByteCountDialect returns the representation of the ByteCount value in
+// the syntax of the dialect, which is parsed by ParseByteCountDialect into the
+// same value. The largest unit that represents the value as an integer is
+// used, e.g. "1536m" for 1.5 GiB in DialectJVM. The value is formatted as the
+// number of bytes for an unknown dialect.
+func FormatByteCountDialect(v ByteCount, d Dialect) string {
+	spec, ok := dialectSpecs[d]
+	switch {
+	case !ok:
+		return strconv.FormatUint(uint64(v), 10)
+	case spec.infinity != "" && v == math.MaxUint64:
+		return spec.infinity
+	}
+	n := uint64(v)
+	exp := 0
+	for n != 0 && exp+1 < len(spec.suffixes) && n%kibi == 0 {
+		n /= kibi
+		exp++
+	}
+	return strconv.FormatUint(n, 10) + spec.suffixes[exp]
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestParseByteCountDialect(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		d      infounit.Dialect
+		s      string
+		v      infounit.ByteCount
+		reason infounit.ParseErrorReason
+	}{
+		{infounit.DialectJVM, "2g", infounit.Gibibyte * 2, 0},
+		{infounit.DialectJVM, "2G", infounit.Gibibyte * 2, 0},
+		{infounit.DialectJVM, "512m", infounit.Mebibyte * 512, 0},
+		{infounit.DialectJVM, "1024k", infounit.Mebibyte, 0},
+		{infounit.DialectJVM, "1t", infounit.Tebibyte, 0},
+		{infounit.DialectJVM, "1048576", infounit.Mebibyte, 0},
+		{infounit.DialectJVM, "1.5g", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectJVM, "2gb", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectJVM, "2 g", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectJVM, "-2g", 0, infounit.ReasonNegative},
+		{infounit.DialectJVM, "", 0, infounit.ReasonBadNumber},
+		{infounit.DialectJVM, "16777216t", 0, infounit.ReasonOutOfRange},
+
+		{infounit.DialectDocker, "512m", infounit.Mebibyte * 512, 0},
+		{infounit.DialectDocker, "512M", infounit.Mebibyte * 512, 0},
+		{infounit.DialectDocker, "512mb", infounit.Mebibyte * 512, 0},
+		{infounit.DialectDocker, "512MB", infounit.Mebibyte * 512, 0},
+		{infounit.DialectDocker, "512MiB", infounit.Mebibyte * 512, 0},
+		{infounit.DialectDocker, "512 mi", infounit.Mebibyte * 512, 0},
+		{infounit.DialectDocker, "2G", infounit.Gibibyte * 2, 0},
+		{infounit.DialectDocker, "1.5g", infounit.Mebibyte * 1536, 0},
+		{infounit.DialectDocker, "0.001k", 1, 0},
+		{infounit.DialectDocker, "100b", 100, 0},
+		{infounit.DialectDocker, "100ib", 100, 0},
+		{infounit.DialectDocker, "100", 100, 0},
+		{infounit.DialectDocker, "1t", infounit.Tebibyte, 0},
+		{infounit.DialectDocker, "1p", infounit.Pebibyte, 0},
+		{infounit.DialectDocker, "1e", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectDocker, "1.k", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectDocker, "512  m", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectDocker, "-1g", 0, infounit.ReasonNegative},
+
+		{infounit.DialectSystemd, "1G", infounit.Gibibyte, 0},
+		{infounit.DialectSystemd, "1.5G", infounit.Mebibyte * 1536, 0},
+		{infounit.DialectSystemd, "100B", 100, 0},
+		{infounit.DialectSystemd, "100", 100, 0},
+		{infounit.DialectSystemd, "2E", infounit.Exbibyte * 2, 0},
+		{infounit.DialectSystemd, "infinity", math.MaxUint64, 0},
+		{infounit.DialectSystemd, "1g", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectSystemd, "1GB", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectSystemd, "50%", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectSystemd, "16E", 0, infounit.ReasonOutOfRange},
+
+		{infounit.DialectPostgreSQL, "128MB", infounit.Mebibyte * 128, 0},
+		{infounit.DialectPostgreSQL, "128 MB", infounit.Mebibyte * 128, 0},
+		{infounit.DialectPostgreSQL, "64kB", infounit.Kibibyte * 64, 0},
+		{infounit.DialectPostgreSQL, "1TB", infounit.Tebibyte, 0},
+		{infounit.DialectPostgreSQL, "1.5kB", 1536, 0},
+		{infounit.DialectPostgreSQL, "0.0005kB", 1, 0},
+		{infounit.DialectPostgreSQL, "100B", 100, 0},
+		{infounit.DialectPostgreSQL, "128mb", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectPostgreSQL, "128KB", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectPostgreSQL, "128", 0, infounit.ReasonMissingUnit},
+
+		{infounit.DialectNginx, "10m", infounit.Mebibyte * 10, 0},
+		{infounit.DialectNginx, "10M", infounit.Mebibyte * 10, 0},
+		{infounit.DialectNginx, "8k", infounit.Kibibyte * 8, 0},
+		{infounit.DialectNginx, "1g", infounit.Gibibyte, 0},
+		{infounit.DialectNginx, "1024", infounit.Kibibyte, 0},
+		{infounit.DialectNginx, "1t", 0, infounit.ReasonUnknownUnit},
+		{infounit.DialectNginx, "1.5m", 0, infounit.ReasonUnknownUnit},
+	}
+	for _, c := range tc {
+		v, err := infounit.ParseByteCountDialect(c.s, c.d)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%v %q: want: %d, got: %d, %v", c.d, c.s, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		if !errors.As(err, &pe) || pe.Reason != c.reason {
+			t.Errorf("%v %q: %v expected, got: %d, %v", c.d, c.s, c.reason, v, err)
+		}
+	}
+	var pe *infounit.ParseError
+	if _, err := infounit.ParseByteCountDialect("1G", infounit.Dialect(0)); !errors.As(err, &pe) || pe.Reason != infounit.ReasonDisallowed {
+		t.Errorf("unknown dialect: %v expected, got: %v", infounit.ReasonDisallowed, err)
+	}
+	const es = "invalid byte count: 16777216t: out of range"
+	if _, err := infounit.ParseByteCountDialect("16777216t", infounit.DialectJVM); err == nil || err.Error() != es {
+		t.Errorf("want: %q, got: %v", es, err)
+	}
+}
+
+//
+func TestFormatByteCountDialect(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v                               infounit.ByteCount
+		jvm, docker, systemd, pg, nginx string
+	}{
+		{0, "0", "0", "0", "0B", "0"},
+		{100, "100", "100", "100", "100B", "100"},
+		{infounit.Kibibyte, "1k", "1k", "1K", "1kB", "1k"},
+		{infounit.Mebibyte * 1536, "1536m", "1536m", "1536M", "1536MB", "1536m"},
+		{infounit.Gibibyte * 2, "2g", "2g", "2G", "2GB", "2g"},
+		{infounit.Pebibyte, "1024t", "1p", "1P", "1024TB", "1048576g"},
+		{infounit.Exbibyte * 3, "3145728t", "3072p", "3E", "3145728TB", "3221225472g"},
+		{infounit.Megabyte, "1000000", "1000000", "1000000", "1000000B", "1000000"},
+		{math.MaxUint64, "18446744073709551615", "18446744073709551615", "infinity", "18446744073709551615B", "18446744073709551615"},
+	}
+	for _, c := range tc {
+		for i, want := range []string{c.jvm, c.docker, c.systemd, c.pg, c.nginx} {
+			d := infounit.Dialect(i + 1)
+			s := infounit.FormatByteCountDialect(c.v, d)
+			if s != want {
+				t.Errorf("%d %v: want: %q, got: %q", c.v, d, want, s)
+			}
+			if v, err := infounit.ParseByteCountDialect(s, d); err != nil || v != c.v {
+				t.Errorf("%d %v: round trip %q: got: %d, %v", c.v, d, s, v, err)
+			}
+		}
+	}
+}
+
+//
+func TestDialect_String(t *testing.T) {
+	t.Parallel()
+
+	for d, s := range map[infounit.Dialect]string{
+		infounit.DialectJVM:        "JVM",
+		infounit.DialectDocker:     "Docker",
+		infounit.DialectSystemd:    "systemd",
+		infounit.DialectPostgreSQL: "PostgreSQL",
+		infounit.DialectNginx:      "nginx",
+		infounit.Dialect(0):        "Dialect(0)",
+	} {
+		if d.String() != s {
+			t.Errorf("want: %q, got: %q", s, d.String())
+		}
+	}
+}
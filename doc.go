@@ -4,12 +4,15 @@
 
 /*
 Package infounit provides information unit data types that can be formatted into
-human-readable string representations. The following three data types are
+human-readable string representations. The following six data types are
 implemented:
 
 	ByteCount  non-negative number of bytes
 	BitCount   non-negative number of bits
 	BitRate    number of bits per unit of time
+	ByteRate   number of bytes per unit of time
+	ByteDelta  signed difference of byte counts
+	BitDelta   signed difference of bit counts
 
 These types can be formatted into and scanned from human-readable string
 representations with both SI and binary prefixes using the standard Printf and
@@ -65,6 +68,13 @@ Unit conversion is also possible by division:
 
 	int(infounit.Mebibyte / infounit.Kibibyte)  // 1024
 
+The arithmetic operators on ByteCount values silently wrap around like uint64.
+Methods Add, Sub, Mul, MulFloat, and Div return ErrOutOfRange instead, and
+SaturatingAdd and SaturatingSub clamp the result at 0 and the maximum value:
+
+	free, err := infounit.Gigabyte.Sub(used)  // ErrOutOfRange if used > 1 GB
+	infounit.Kilobyte.SaturatingSub(used)     // 0 if used > 1 kB
+
 See the Constants section below for the complete list of defined constants.
 
 ByteCount values can be flexibly formatted using the standard Printf family
@@ -100,6 +110,36 @@ A ' '(space) flag puts a spece between digits and the unit. A '#' flag uses an
 alternative long unit name. See the Format method documentation bellow for
 details on all supported verbs and flags.
 
+The verbs %h and %H print a fixed number of significant digits like "ls -h"
+and "top", taking the precision as the number of digits, 3 by default:
+
+	fmt.Printf("%h", z)          // 988MB
+	fmt.Printf("%H", z)          // 942MiB
+	fmt.Printf("%h", z/100)      // 9.88MB
+	fmt.Printf("%.2h", z/100)    // 9.9MB
+
+The same options are also available as the fields of a Formatter, which is
+useful when they are chosen at runtime. It also provides the options that are
+not available as the flags, such as the range of prefixes and plural handling:
+
+	f := infounit.Formatter{Prefixes: infounit.BinaryPrefixes, Precision: 2}
+	f.FormatByteCount(z)         // 941.90MiB
+
+FormatIn formats a value in a fixed unit instead of the automatically selected
+one, and BestByteCountUnit picks a single unit for a column of values:
+
+	unit := infounit.BestByteCountUnit(sizes, infounit.BinaryPrefixes, 2)
+	for _, v := range sizes {
+		s, _ := v.FormatIn(unit, 2)
+		fmt.Println(s) // "0.25 GiB", "1.50 GiB", ...
+	}
+
+For hot paths such as logging, AppendFormat and AppendText append the
+representations to a byte slice without allocating:
+
+	buf = z.AppendFormat(buf[:0], &f)  // 941.90MiB
+	buf = z.AppendFormat(buf[:0], nil) // 987.7 MB, same as String()
+
 ByteCount type also supports the standard Scanf family functions in the
 package fmt, fmt.Scanf, fmt.Fscanf(), and fmt.Sscanf(). Human-readable string
 representations with SI and binary prefixes can be "scanned" as ByteCount
@@ -115,6 +155,82 @@ binary prefixes. So it is usually fine to use only the %s verb to scan.
 The %S verb treats the SI prefix representation as binary prefixes. See the
 Scan method documentation bellow for details.
 
+To convert a single string, ParseByteCount and the other Parse functions are
+the same as fmt.Sscanf with the %s verb, but faster and allocation-free.
+
+	size, err := infounit.ParseByteCount("1.5 GiB")
+
+The errors returned by the Parse functions, the Scan methods and the Unmarshal
+methods are *ParseError, which holds the position of the offending token and
+the reason, such as an unknown unit or a value out of range.
+
+	_, err = infounit.ParseByteCount("1.5 jigabytes")
+	var pe *infounit.ParseError
+	if errors.As(err, &pe) {
+		fmt.Println(pe.Reason, pe.Offset, pe.Token) // unknown unit 4 jigabytes
+	}
+
+The Parse functions stop after the value like fmt.Sscanf, so "10 GB please" is
+parsed as 10 GB. To validate a whole string, such as a configuration value, use
+a Parser, which also has options to allow or forbid a missing unit, a space
+before the unit, and the long unit names. The Unmarshal methods also reject
+the trailing input.
+
+	p := infounit.Parser{Space: infounit.SpaceRequired}
+	size, err = p.ParseByteCount("10 GB please") // trailing input
+
+The Numbers field of Parser and the Number field of Formatter specify the
+decimal mark and the digit grouping of localized numbers, e.g. "1.234,5 MB".
+If a Parser accepts more than one NumberFormat, ambiguous numbers such as
+"1,234 MB" are rejected:
+
+	p = infounit.Parser{Numbers: []infounit.NumberFormat{
+		infounit.NumberFormatEnglish, infounit.NumberFormatGerman,
+	}}
+	size, err = p.ParseByteCount("1,5 GB")   // 1.5 GB
+	size, err = p.ParseByteCount("1,234 MB") // ambiguous number
+
+The Language field of Formatter and Parser selects the localized unit names by
+a BCP 47 language tag. German, French, Japanese and Russian are supported, and
+the plural forms of the long names follow the CLDR plural rules:
+
+	f := infounit.Formatter{Precision: 1, Separator: " ", Language: "fr"}
+	f.Number = infounit.NumberFormatFrench
+	f.FormatByteCount(infounit.Megabyte * 1500) // "1,5 Go"
+	f.LongNames = true
+	f.FormatByteCount(infounit.Gigabyte * 25)   // "25,0 gigaoctets"
+
+Kubernetes resource quantities such as "512Mi", "1.5G" and "2e9" are converted
+by ParseKubernetesByteCount and FormatKubernetesByteCount, and the bandwidths
+in bits per second by ParseKubernetesBitRate and FormatKubernetesBitRate.
+
+The size syntaxes of the configurations of JVM, Docker, systemd, PostgreSQL and
+nginx, such as "2g" for -Xmx and "1G" for MemoryMax, are converted by
+ParseByteCountDialect and FormatByteCountDialect:
+
+	size, err = infounit.ParseByteCountDialect("1G", infounit.DialectSystemd) // 1 GiB
+	infounit.FormatByteCountDialect(size, infounit.DialectPostgreSQL)         // "1GB"
+
+The syntax of the GOMEMLIMIT environment variable, such as "512MiB", is
+converted by ParseGoMemLimit and FormatGoMemLimit. MemoryLimit returns the
+current memory limit of the Go runtime, on Go 1.19 or later.
+
+The rates of the Linux tc command, such as "100mbit" and "10mbps", where "bps"
+means bytes per second, are converted by ParseTCBitRate, and FormatTCBitRate
+formats a BitRate value in the same way as "tc -s qdisc", e.g. "100Mbit".
+
+The human-readable sizes of the -h option of the GNU coreutils such as ls, du and
+df, e.g. "4.0K" and "23G", are converted by ParseCoreutilsByteCount and
+FormatCoreutilsByteCount, which rounds up in the same way as the commands, with
+the powers of 1024 or 1000 as the --si option.
+
+EvalByteCount and EvalBitRate evaluate arithmetic expressions with units, which
+is useful for command line flags:
+
+	size, err = infounit.EvalByteCount("2GiB + 512MiB") // 2560 MiB
+	size, err = infounit.EvalByteCount("10% of 2 TB")   // 200 GB
+	speed, err := infounit.EvalBitRate("1 GB / 10s")    // 800 Mbit/s
+
 BitCount
 
 BitCount represents a number of bits. It is internally uint64. Functions and
@@ -126,5 +242,32 @@ BitRate
 BitRate represents a number of bits that are transferred or processed per unit
 of time. It is a suitable type for storing data transfer speed, processing
 speed, etc. It is internally float64.
+
+ByteRate
+
+ByteRate represents a number of bytes that are transferred or processed per
+unit of time. It is the same as BitRate, except that it is formatted with byte
+units such as "MB/s" and "MiB/s". ByteRate and BitRate values can be converted
+to each other without loss of precision:
+
+	rate := infounit.MegabytePerSecond * 12.5
+	fmt.Printf("% s\n", rate)           // "12.5 MB/s"
+	fmt.Printf("% s\n", rate.BitRate()) // "100 Mbit/s"
+
+ByteDelta and BitDelta
+
+ByteDelta represents a signed difference of byte counts, such as the change in
+disk usage or the number of bytes over quota. It is internally int64, and is
+formatted and scanned in the same way as ByteCount, with the sign. The '+' flag
+always prints the sign:
+
+	used, err := today.Delta(yesterday) // ByteCount - ByteCount
+	fmt.Printf("%+ .1s\n", used)        // "+1.5 GB"
+
+	d, _ := infounit.ParseByteDelta("-200 MiB")
+	size, err := quota.AddDelta(d)      // ErrOutOfRange if negative
+
+BitDelta is the same as ByteDelta, except that it represents a difference of
+bit counts.
 */
 package infounit
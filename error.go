@@ -6,14 +6,157 @@ package infounit
 
 import (
 	"errors"
+	"strconv"
 )
 
 // ErrOutOfRange is the error thrown when the result exceeds the range.
 var ErrOutOfRange = errors.New("out of range")
 
+// ErrDivZero is the error thrown when trying to divide by zero.
+var ErrDivZero = errors.New("division by zero")
+
 // ErrDivZeroBitRate is the error thrown when trying to divide by zero bit rate.
 var ErrDivZeroBitRate = errors.New("division by zero bit rate")
 
+// ErrDivZeroByteRate is the error thrown when trying to divide by zero byte
+// rate.
+var ErrDivZeroByteRate = errors.New("division by zero byte rate")
+
 // ErrMalformedRepresentation is the error thrown when trying to conver
 // a malformed string representation.
 var ErrMalformedRepresentation = errors.New("malformed representation")
+
+// ErrFractional is the error thrown when a value that is not a whole number of
+// bytes or bits is converted with the RoundExact rounding mode.
+var ErrFractional = errors.New("fractional value")
+
+// ErrInvalidUnit is the error thrown when trying to format a value in a unit
+// that is not a power of 1000 or 1024, such as 3 * Kilobyte.
+var ErrInvalidUnit = errors.New("invalid unit")
+
+// ParseErrorReason is the reason why a human-readable representation could not
+// be parsed.
+type ParseErrorReason int
+
+// Parse error reasons.
+const (
+	ReasonBadNumber        ParseErrorReason = iota + 1 // missing or malformed number
+	ReasonUnknownUnit                                  // unit suffix not recognized
+	ReasonMissingUnit                                  // unit suffix required but missing
+	ReasonOutOfRange                                   // value exceeds the range of the type
+	ReasonNegative                                     // negative value for an unsigned type
+	ReasonFractional                                   // not a whole number with RoundExact
+	ReasonTrailingInput                                // input after the value, by a Parser
+	ReasonDisallowed                                   // form not allowed by the Parser options
+	ReasonInvalidOperation                             // operation not defined for the operands
+	ReasonAmbiguous                                    // number read differently by the formats
+)
+
+// String returns the description of the reason, e.g. "unknown unit".
+func (r ParseErrorReason) String() string {
+	switch r {
+	case ReasonBadNumber:
+		return "bad number"
+	case ReasonUnknownUnit:
+		return "unknown unit"
+	case ReasonMissingUnit:
+		return "missing unit"
+	case ReasonOutOfRange:
+		return "out of range"
+	case ReasonNegative:
+		return "negative not allowed"
+	case ReasonFractional:
+		return "fractional value"
+	case ReasonTrailingInput:
+		return "trailing input"
+	case ReasonDisallowed:
+		return "not allowed"
+	case ReasonInvalidOperation:
+		return "invalid operation"
+	case ReasonAmbiguous:
+		return "ambiguous number"
+	}
+	return "ParseErrorReason(" + strconv.Itoa(int(r)) + ")"
+}
+
+// ParseError is the error returned when a human-readable representation can
+// not be converted into a value, by the Parse functions, the Scan methods and
+// the Unmarshal methods. Use errors.As to get the details:
+//
+// 	var pe *infounit.ParseError
+// 	if errors.As(err, &pe) && pe.Reason == infounit.ReasonUnknownUnit {
+// 		fmt.Printf("unknown unit %q at %d\n", pe.Token, pe.Offset)
+// 	}
+//
+// ParseError matches ErrOutOfRange with errors.Is if the reason is
+// ReasonOutOfRange, both ErrOutOfRange and ErrMalformedRepresentation if
+// ReasonNegative, ErrFractional if ReasonFractional, and
+// ErrMalformedRepresentation otherwise.
+type ParseError struct {
+	// Input is the string being parsed. For the errors returned by Scan, it
+	// is the part of the input read by Scan, excluding the leading spaces.
+	Input string
+
+	// Offset is the byte offset of Token in Input.
+	Offset int
+
+	// Token is the offending part of Input, e.g. the unknown unit suffix.
+	// It may be empty, e.g. when the input ends before the unit suffix.
+	Token string
+
+	// Type is the name of the target type, e.g. "ByteCount".
+	Type string
+
+	// Reason is the reason for the error.
+	Reason ParseErrorReason
+
+	// Err is the underlying error if any, e.g. io.EOF.
+	Err error
+
+	msg    string // detailed message compatible with the earlier versions
+	prefix bool   // the message is prefixed with the input
+}
+
+// Error returns the string representation of the error.
+func (e *ParseError) Error() string {
+	if !e.prefix {
+		return e.msg
+	}
+	return "invalid " + typeWords(e.Type) + ": " + e.Input + ": " + e.msg
+}
+
+// Is returns whether the error matches target; ErrMalformedRepresentation,
+// ErrOutOfRange or ErrFractional, depending on the reason.
+func (e *ParseError) Is(target error) bool {
+	switch e.Reason {
+	case ReasonOutOfRange:
+		return target == ErrOutOfRange
+	case ReasonNegative:
+		return target == ErrOutOfRange || target == ErrMalformedRepresentation
+	case ReasonFractional:
+		return target == ErrFractional
+	}
+	return target == ErrMalformedRepresentation
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// typeWords returns the name of the type in lower-case words, e.g. "byte
+// count" for "ByteCount".
+func typeWords(typ string) string {
+	b := make([]byte, 0, len(typ)+2)
+	for i := 0; i < len(typ); i++ {
+		c := typ[i]
+		if 'A' <= c && c <= 'Z' {
+			if 0 < i {
+				b = append(b, ' ')
+			}
+			c += 'a' - 'A'
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+	"gopkg.in/yaml.v2"
+)
+
+//
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		parse  func(string) error
+		src    string
+		typ    string
+		reason infounit.ParseErrorReason
+		off    int
+		tok    string
+		es     string
+	}{
+		{
+			parseByteCount, "", "ByteCount", infounit.ReasonBadNumber, 0, "",
+			"invalid byte count: : %s: no input",
+		},
+		{
+			parseByteCount, "  x B", "ByteCount", infounit.ReasonBadNumber, 2, "x",
+			"invalid byte count:   x B: %s: invalid expr: x",
+		},
+		{
+			parseByteCount, "-5 kB", "ByteCount", infounit.ReasonNegative, 0, "-5",
+			"invalid byte count: -5 kB: %s: invalid expr: -5",
+		},
+		{
+			parseByteCount, "12 jigobytes", "ByteCount", infounit.ReasonUnknownUnit, 3, "jigobytes",
+			"invalid byte count: 12 jigobytes: %s: unknown unit: jigobytes",
+		},
+		{
+			parseByteCount, "12foo", "ByteCount", infounit.ReasonUnknownUnit, 2, "foo",
+			"invalid byte count: 12foo: %s: unknown unit: foo",
+		},
+		{
+			parseByteCount, "12", "ByteCount", infounit.ReasonMissingUnit, 2, "",
+			"invalid byte count: 12: %s: no unit suffix: EOF",
+		},
+		{
+			parseByteCount, "12\tkB", "ByteCount", infounit.ReasonMissingUnit, 2, "\t",
+			"invalid byte count: 12\tkB: %s: no space after digits: [\t]",
+		},
+		{
+			parseByteCount, "1.5 B", "ByteCount", infounit.ReasonBadNumber, 0, "1.5",
+			"invalid byte count: 1.5 B: %s: non-integer byte count: 1.5",
+		},
+		{
+			parseByteCount, "20 EB", "ByteCount", infounit.ReasonOutOfRange, 0, "20",
+			"invalid byte count: 20 EB: %s: 20 EB: out of range",
+		},
+		{
+			parseByteCount, "20EB", "ByteCount", infounit.ReasonOutOfRange, 0, "20",
+			"invalid byte count: 20EB: %s: 20EB: out of range",
+		},
+		{
+			parseBitCount, "18446744073709551616 bit", "BitCount", infounit.ReasonOutOfRange, 0, "18446744073709551616",
+			"invalid bit count: 18446744073709551616 bit: %s: invalid bit count: 18446744073709551616: out of range",
+		},
+		{
+			parseByteDelta, "-9 EiB", "ByteDelta", infounit.ReasonOutOfRange, 0, "-9 EiB",
+			"invalid byte delta: -9 EiB: %s: out of range",
+		},
+		{
+			parseByteDelta, "- 5 kB", "ByteDelta", infounit.ReasonBadNumber, 0, "-",
+			"invalid byte delta: - 5 kB: %s: space after sign: -",
+		},
+		{
+			parseBitRate, "5 kilobits per minute", "BitRate", infounit.ReasonUnknownUnit, 2, "kilobits per",
+			"invalid bit rate: 5 kilobits per minute: %s: unknown unit: kilobits per minute",
+		},
+		{
+			parseByteRate, "5 kB/s/", "ByteRate", infounit.ReasonUnknownUnit, 2, "kB/s/",
+			"invalid byte rate: 5 kB/s/: %s: unknown unit: kB/s/",
+		},
+	}
+	for _, c := range tc {
+		err := c.parse(c.src)
+		var pe *infounit.ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("%q: unexpected error: %v", c.src, err)
+			continue
+		}
+		if pe.Input != c.src || pe.Type != c.typ || pe.Reason != c.reason || pe.Offset != c.off || pe.Token != c.tok {
+			t.Errorf("%q: unexpected ParseError: %+v", c.src, *pe)
+		}
+		if err.Error() != c.es {
+			t.Errorf("%q: unexpected message: want: %q, got: %q", c.src, c.es, err.Error())
+		}
+	}
+}
+
+func parseByteCount(s string) error {
+	_, err := infounit.ParseByteCount(s)
+	return err
+}
+
+func parseBitCount(s string) error {
+	_, err := infounit.ParseBitCount(s)
+	return err
+}
+
+func parseByteDelta(s string) error {
+	_, err := infounit.ParseByteDelta(s)
+	return err
+}
+
+func parseBitRate(s string) error {
+	_, err := infounit.ParseBitRate(s)
+	return err
+}
+
+func parseByteRate(s string) error {
+	_, err := infounit.ParseByteRate(s)
+	return err
+}
+
+//
+func TestParseError_Is(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		err    error
+		reason infounit.ParseErrorReason
+		target error
+	}{
+		{parseByteCount("5 foo"), infounit.ReasonUnknownUnit, infounit.ErrMalformedRepresentation},
+		{parseByteCount("5"), infounit.ReasonMissingUnit, infounit.ErrMalformedRepresentation},
+		{parseByteCount("20 EB"), infounit.ReasonOutOfRange, infounit.ErrOutOfRange},
+		{parseByteCount("-1 B"), infounit.ReasonNegative, infounit.ErrOutOfRange},
+		{func() error { _, err := infounit.ParseByteCountRound("0.3 KiB", infounit.RoundExact); return err }(), infounit.ReasonFractional, infounit.ErrFractional},
+		{json.Unmarshal([]byte(`-1`), new(infounit.ByteCount)), infounit.ReasonNegative, infounit.ErrOutOfRange},
+		{parseByteCount("-1 B"), infounit.ReasonNegative, infounit.ErrMalformedRepresentation},
+		{json.Unmarshal([]byte(`"-5 B"`), new(infounit.ByteCount)), infounit.ReasonNegative, infounit.ErrMalformedRepresentation},
+		{json.Unmarshal([]byte(`"-5 bit"`), new(infounit.BitCount)), infounit.ReasonNegative, infounit.ErrMalformedRepresentation},
+		{yaml.Unmarshal([]byte(`-5 B`), new(infounit.ByteCount)), infounit.ReasonNegative, infounit.ErrMalformedRepresentation},
+		{yaml.Unmarshal([]byte(`-5 bit`), new(infounit.BitCount)), infounit.ReasonNegative, infounit.ErrMalformedRepresentation},
+		{new(infounit.ByteCount).UnmarshalText([]byte("-5 B")), infounit.ReasonNegative, infounit.ErrMalformedRepresentation},
+		{new(infounit.BitCount).UnmarshalText([]byte("-5 bit")), infounit.ReasonNegative, infounit.ErrMalformedRepresentation},
+		{new(infounit.ByteCount).UnmarshalText([]byte("-5 B")), infounit.ReasonNegative, infounit.ErrOutOfRange},
+		{json.Unmarshal([]byte(`1e20`), new(infounit.BitCount)), infounit.ReasonOutOfRange, infounit.ErrOutOfRange},
+		{json.Unmarshal([]byte(`-1e19`), new(infounit.ByteDelta)), infounit.ReasonOutOfRange, infounit.ErrOutOfRange},
+		{json.Unmarshal([]byte(`"5 foo"`), new(infounit.ByteCount)), infounit.ReasonUnknownUnit, infounit.ErrMalformedRepresentation},
+		{json.Unmarshal([]byte(`"5 foo"`), new(infounit.BitRate)), infounit.ReasonUnknownUnit, infounit.ErrMalformedRepresentation},
+		{new(infounit.ByteRate).UnmarshalText([]byte("x")), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{new(infounit.BitDelta).UnmarshalText([]byte("9 Eibit")), infounit.ReasonOutOfRange, infounit.ErrOutOfRange},
+		{json.Unmarshal([]byte(`true`), new(infounit.ByteCount)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{json.Unmarshal([]byte(`[1]`), new(infounit.BitCount)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{json.Unmarshal([]byte(`{}`), new(infounit.ByteDelta)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{json.Unmarshal([]byte(`false`), new(infounit.BitDelta)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{json.Unmarshal([]byte(`[]`), new(infounit.BitRate)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{json.Unmarshal([]byte(`true`), new(infounit.ByteRate)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{yaml.Unmarshal([]byte(`[1]`), new(infounit.ByteCount)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{yaml.Unmarshal([]byte(`{a: 1}`), new(infounit.BitCount)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{yaml.Unmarshal([]byte(`[1]`), new(infounit.ByteDelta)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{yaml.Unmarshal([]byte(`[1]`), new(infounit.BitDelta)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{yaml.Unmarshal([]byte(`[1]`), new(infounit.BitRate)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+		{yaml.Unmarshal([]byte(`[1]`), new(infounit.ByteRate)), infounit.ReasonBadNumber, infounit.ErrMalformedRepresentation},
+	}
+	for i, c := range tc {
+		var pe *infounit.ParseError
+		if !errors.As(c.err, &pe) {
+			t.Errorf("#%d: unexpected error: %v", i, c.err)
+			continue
+		}
+		if pe.Reason != c.reason {
+			t.Errorf("#%d: %v: unexpected reason: want: %v, got: %v", i, c.err, c.reason, pe.Reason)
+		}
+		if !errors.Is(c.err, c.target) {
+			t.Errorf("#%d: %v: does not match %v", i, c.err, c.target)
+		}
+	}
+}
+
+//
+func TestParseError_unmarshalType(t *testing.T) {
+	t.Parallel()
+
+	var pe *infounit.ParseError
+	err := json.Unmarshal([]byte(`{"v": [1, 2]}`), &struct{ V infounit.ByteCount }{})
+	if !errors.As(err, &pe) || pe.Input != "[1, 2]" || pe.Type != "ByteCount" {
+		t.Errorf("JSON: unexpected error: %#v", err)
+	}
+	err = yaml.Unmarshal([]byte("v: [1, 2]"), &struct{ V infounit.BitRate }{})
+	if !errors.As(err, &pe) || pe.Input != "[1 2]" || pe.Type != "BitRate" {
+		t.Errorf("YAML: unexpected error: %#v", err)
+	}
+}
+
+//
+func TestParseError_scan(t *testing.T) {
+	t.Parallel()
+
+	var bc infounit.ByteCount
+	_, err := fmt.Sscanf("size: 12 jigobytes", "size: %s", &bc)
+	var pe *infounit.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pe.Input != "12 jigobytes" || pe.Offset != 3 || pe.Token != "jigobytes" || pe.Reason != infounit.ReasonUnknownUnit {
+		t.Errorf("unexpected ParseError: %+v", *pe)
+	}
+	if s := err.Error(); s != "%s: unknown unit: jigobytes" {
+		t.Errorf("unexpected message: %q", s)
+	}
+}
+
+//
+func TestParseErrorReason_String(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		r infounit.ParseErrorReason
+		s string
+	}{
+		{infounit.ReasonBadNumber, "bad number"},
+		{infounit.ReasonUnknownUnit, "unknown unit"},
+		{infounit.ReasonMissingUnit, "missing unit"},
+		{infounit.ReasonOutOfRange, "out of range"},
+		{infounit.ReasonNegative, "negative not allowed"},
+		{infounit.ReasonFractional, "fractional value"},
+		{infounit.ReasonTrailingInput, "trailing input"},
+		{infounit.ReasonDisallowed, "not allowed"},
+		{infounit.ReasonInvalidOperation, "invalid operation"},
+		{infounit.ReasonAmbiguous, "ambiguous number"},
+		{0, "ParseErrorReason(0)"},
+	}
+	for _, c := range tc {
+		if s := c.r.String(); s != c.s {
+			t.Errorf("%d: want: %q, got: %q", int(c.r), c.s, s)
+		}
+	}
+}
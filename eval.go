@@ -0,0 +1,403 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"math"
+	"math/big"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// evalKind is the kind of a value in an expression.
+type evalKind int
+
+// Kinds of values in expressions.
+const (
+	evalScalar   evalKind = iota // dimensionless number, e.g. "3", "10%"
+	evalData                     // number of bits
+	evalDuration                 // number of seconds
+	evalRate                     // number of bits per second
+)
+
+// String returns the name of the kind used in error messages.
+func (k evalKind) String() string {
+	switch k {
+	case evalData:
+		return "byte count"
+	case evalDuration:
+		return "duration"
+	case evalRate:
+		return "bit rate"
+	}
+	return "number"
+}
+
+// evalValue is a value in an expression. The arithmetic is done exactly with
+// rational numbers, and only the final result is rounded.
+type evalValue struct {
+	kind evalKind
+	v    *big.Rat
+}
+
+// evalDurationUnits is the table of the units of durations in expressions.
+var evalDurationUnits = []struct {
+	name string
+	d    time.Duration
+}{
+	{"ns", time.Nanosecond},
+	{"us", time.Microsecond}, {"µs", time.Microsecond},
+	{"ms", time.Millisecond},
+	{"s", time.Second}, {"sec", time.Second},
+	{"m", time.Minute}, {"min", time.Minute},
+	{"h", time.Hour},
+}
+
+// evalMaxData is the maximum number of bits of a byte count in expressions,
+// which is the maximum value of ByteCount.
+var evalMaxData = new(big.Rat).Mul(new(big.Rat).SetUint64(math.MaxUint64), big.NewRat(8, 1))
+
+// evaluator evaluates an expression by recursive descent:
+//
+// 	expr    = term { ("+" | "-") term }
+// 	term    = unary { ("*" | "/" | "of") unary }
+// 	unary   = [ "+" | "-" ] unary | primary
+// 	primary = number [ unit | "%" ] | "(" expr ")"
+type evaluator struct {
+	lx    lexer
+	depth int // nesting depth of the parentheses and signs
+}
+
+// evalMaxDepth is the maximum nesting depth of the parentheses and signs, which
+// limits the recursion.
+const evalMaxDepth = 100
+
+// EvalByteCount evaluates an arithmetic expression of byte counts, such as
+// "2GiB + 512MiB", "4 * 256 MB", "1TB / 3" or "10% of 2 TB", and returns the
+// result as a ByteCount value.
+//
+// An expression consists of the operators +, -, * and /, parentheses, and
+// numbers. "of" is the same as *. A number may be followed by a unit of
+// ByteCount, BitCount, BitRate or ByteRate, a unit of time.Duration (ns, us,
+// ms, s, m, h), or "%", with an optional space in between. A number without
+// unit is dimensionless. The operands must be of compatible kinds: a byte
+// count divided by a byte count is a dimensionless ratio, a byte count divided
+// by a duration is a bit rate, a bit rate multiplied by a duration is a byte
+// count, and so on. For example, "1 GB / 10s * 1m" is 6 GB.
+//
+// The arithmetic is exact, and the result is rounded to the nearest byte.
+// Every byte count in the expression, including the intermediate results,
+// must be within the range of ByteCount. Otherwise, the returned *ParseError
+// has ReasonOutOfRange. It has ReasonInvalidOperation if the kinds of the
+// operands are not compatible or a value is divided by zero. The parentheses
+// and signs may be nested up to 100 levels; ReasonBadNumber is returned for
+// the deeper ones.
+func EvalByteCount(s string) (ByteCount, error) {
+	e := evaluator{lx: lexer{s: s, typ: "ByteCount"}}
+	x, err := e.eval(evalData)
+	if err != nil {
+		return 0, err
+	}
+
+	// round to the nearest, half away from zero
+	num := new(big.Int).Mul(x.v.Num(), big.NewInt(2))
+	den := new(big.Int).Mul(x.v.Denom(), big.NewInt(16)) // bits to bytes
+	num.Add(num, new(big.Int).Rsh(den, 1))
+	num.Quo(num, den)
+	return ByteCount(num.Uint64()), nil
+}
+
+// EvalBitRate evaluates an arithmetic expression of bit rates, such as
+// "100 Mbit/s * 2", "1 GB / 10s" or "80% of 1 Gbit/s", and returns the result
+// as a BitRate value. The syntax is the same as EvalByteCount. The result may
+// be negative.
+func EvalBitRate(s string) (BitRate, error) {
+	e := evaluator{lx: lexer{s: s, typ: "BitRate"}}
+	x, err := e.eval(evalRate)
+	if err != nil {
+		return 0, err
+	}
+	f, _ := x.v.Float64()
+	if math.IsInf(f, 0) {
+		return 0, e.lx.errorf(ReasonOutOfRange, 0, s, ErrOutOfRange, "%v", ErrOutOfRange)
+	}
+	return BitRate(f), nil
+}
+
+// eval evaluates the whole input, the result of which must be of the kind.
+func (e *evaluator) eval(kind evalKind) (evalValue, error) {
+	e.skipSpace()
+	if e.lx.pos == len(e.lx.s) {
+		return evalValue{}, e.lx.errorf(ReasonBadNumber, e.lx.pos, "", nil, "no input")
+	}
+	x, err := e.expr()
+	if err != nil {
+		return x, err
+	}
+	if e.skipSpace(); e.lx.pos < len(e.lx.s) {
+		rest := e.lx.s[e.lx.pos:]
+		return x, e.lx.errorf(ReasonTrailingInput, e.lx.pos, rest, nil, "trailing input: %q", rest)
+	}
+	switch {
+	case x.kind == kind:
+		return x, nil
+	case x.kind == evalScalar:
+		return x, e.lx.errorf(ReasonMissingUnit, 0, e.lx.s, nil, "result is a number, not a %v", kind)
+	}
+	return x, e.lx.errorf(ReasonInvalidOperation, 0, e.lx.s, nil, "result is a %v, not a %v", x.kind, kind)
+}
+
+// skipSpace skips the white spaces.
+func (e *evaluator) skipSpace() {
+	for e.lx.pos < len(e.lx.s) {
+		r, n := utf8.DecodeRuneInString(e.lx.s[e.lx.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		e.lx.pos += n
+	}
+}
+
+// operator reads the next binary operator in ops, and returns it with the
+// offset. "of" is returned as '*' if ops contains '*'. It returns 0 and does
+// not advance if the next token is not one of them.
+func (e *evaluator) operator(ops string) (byte, int) {
+	e.skipSpace()
+	s, pos := e.lx.s, e.lx.pos
+	if len(s) <= pos {
+		return 0, pos
+	}
+	for i := 0; i < len(ops); i++ {
+		switch {
+		case s[pos] == ops[i]:
+			e.lx.pos++
+			return ops[i], pos
+		case ops[i] == '*' && e.wordEnd(pos) == pos+2 && equalFoldASCII("of", s[pos:pos+2]):
+			e.lx.pos += 2
+			return '*', pos
+		}
+	}
+	return 0, pos
+}
+
+// expr evaluates the additive expression.
+func (e *evaluator) expr() (evalValue, error) {
+	x, err := e.term()
+	for err == nil {
+		op, off := e.operator("+-")
+		if op == 0 {
+			break
+		}
+		var y evalValue
+		if y, err = e.term(); err == nil {
+			x, err = e.apply(op, off, x, y)
+		}
+	}
+	return x, err
+}
+
+// term evaluates the multiplicative expression.
+func (e *evaluator) term() (evalValue, error) {
+	x, err := e.unary()
+	for err == nil {
+		op, off := e.operator("*/")
+		if op == 0 {
+			break
+		}
+		var y evalValue
+		if y, err = e.unary(); err == nil {
+			x, err = e.apply(op, off, x, y)
+		}
+	}
+	return x, err
+}
+
+// unary evaluates the operand with an optional sign.
+func (e *evaluator) unary() (evalValue, error) {
+	e.skipSpace()
+	s, pos := e.lx.s, e.lx.pos
+	if pos < len(s) && (s[pos] == '+' || s[pos] == '-') {
+		if err := e.nest(pos); err != nil {
+			return evalValue{}, err
+		}
+		e.lx.pos++
+		x, err := e.unary()
+		e.depth--
+		if err != nil || s[pos] == '+' {
+			return x, err
+		}
+		x.v.Neg(x.v)
+		return x, e.check(x, pos)
+	}
+	return e.primary()
+}
+
+// nest increments the nesting depth for the parenthesis or sign at the offset,
+// and returns an error if it exceeds evalMaxDepth.
+func (e *evaluator) nest(off int) error {
+	if e.depth++; evalMaxDepth < e.depth {
+		return e.lx.errorf(ReasonBadNumber, off, e.lx.s[off:off+1], nil, "nested too deeply")
+	}
+	return nil
+}
+
+// primary evaluates a number with an optional unit, or an expression in
+// parentheses.
+func (e *evaluator) primary() (evalValue, error) {
+	s, start := e.lx.s, e.lx.pos
+	if start == len(s) {
+		return evalValue{}, e.lx.errorf(ReasonBadNumber, start, "", nil, "unexpected end of input")
+	}
+	if s[start] == '(' {
+		if err := e.nest(start); err != nil {
+			return evalValue{}, err
+		}
+		e.lx.pos++
+		x, err := e.expr()
+		e.depth--
+		if err != nil {
+			return x, err
+		}
+		if op, off := e.operator(")"); op == 0 {
+			return x, e.lx.errorf(ReasonBadNumber, off, s[off:tokenEnd(s, off, len(s))], nil, "missing ')'")
+		}
+		return x, nil
+	}
+
+	end := digitsEnd(s, start)
+	if end+1 < len(s) && s[end] == '.' && digitsEnd(s, end+1) != end+1 {
+		end = digitsEnd(s, end+1)
+	}
+	if end == start {
+		tok := s[start:tokenEnd(s, start, len(s))]
+		return evalValue{}, e.lx.errorf(ReasonBadNumber, start, tok, nil, "invalid expr: %s", tok)
+	}
+	x := evalValue{kind: evalScalar, v: new(big.Rat)}
+	x.v.SetString(s[start:end])
+	e.lx.pos = end
+
+	// unit
+	e.skipSpace()
+	pos := e.lx.pos
+	if pos < len(s) && s[pos] == '%' {
+		e.lx.pos++
+		x.v.Quo(x.v, big.NewRat(100, 1))
+		return x, nil
+	}
+	wend := e.wordEnd(pos)
+	word := s[pos:wend]
+	if word == "" || equalFoldASCII("of", word) {
+		e.lx.pos = end
+		return x, nil
+	}
+	kind, mul, ok := evalUnit(word)
+	if !ok {
+		return x, e.lx.errorf(ReasonUnknownUnit, pos, word, nil, "unknown unit: %s", word)
+	}
+	e.lx.pos = wend
+	x.kind = kind
+	x.v.Mul(x.v, mul)
+	return x, e.check(x, start)
+}
+
+// wordEnd returns the end of the word starting at s[pos], which is a unit or
+// "of". A word consists of letters and '/' followed by a letter, e.g. "kbit/s".
+func (e *evaluator) wordEnd(pos int) int {
+	s := e.lx.s
+	for pos < len(s) {
+		r, n := utf8.DecodeRuneInString(s[pos:])
+		switch {
+		case unicode.IsLetter(r):
+		case r == '/' && pos+1 < len(s) && isUnitChar(s[pos+1], false):
+		default:
+			return pos
+		}
+		pos += n
+	}
+	return pos
+}
+
+// evalUnit returns the kind and the value of the unit.
+func evalUnit(word string) (evalKind, *big.Rat, bool) {
+	tables := []struct {
+		units []scanUnit
+		kind  evalKind
+		bits  int64
+	}{
+		{byteCountUnits, evalData, 8},
+		{bitCountUnits, evalData, 1},
+		{bitRateUnits, evalRate, 1},
+		{byteRateUnits, evalRate, 8},
+	}
+	for _, t := range tables {
+		if _, mul, ok := lookupUnit(t.units, word, false); ok {
+			v := new(big.Rat).SetUint64(mul)
+			return t.kind, v.Mul(v, big.NewRat(t.bits, 1)), true
+		}
+	}
+	for _, u := range evalDurationUnits {
+		if equalFoldASCII(u.name, word) {
+			return evalDuration, big.NewRat(int64(u.d), int64(time.Second)), true
+		}
+	}
+	return 0, nil, false
+}
+
+// apply applies the binary operator to the operands.
+func (e *evaluator) apply(op byte, off int, x, y evalValue) (evalValue, error) {
+	z := evalValue{kind: -1, v: new(big.Rat)}
+	switch op {
+	case '+', '-':
+		if x.kind == y.kind {
+			z.kind = x.kind
+		}
+		if op == '+' {
+			z.v.Add(x.v, y.v)
+		} else {
+			z.v.Sub(x.v, y.v)
+		}
+	case '*':
+		switch {
+		case x.kind == evalScalar:
+			z.kind = y.kind
+		case y.kind == evalScalar:
+			z.kind = x.kind
+		case x.kind == evalRate && y.kind == evalDuration, x.kind == evalDuration && y.kind == evalRate:
+			z.kind = evalData
+		}
+		z.v.Mul(x.v, y.v)
+	case '/':
+		switch {
+		case y.kind == evalScalar:
+			z.kind = x.kind
+		case x.kind == y.kind:
+			z.kind = evalScalar
+		case x.kind == evalData && y.kind == evalDuration:
+			z.kind = evalRate
+		case x.kind == evalData && y.kind == evalRate:
+			z.kind = evalDuration
+		}
+		if 0 <= z.kind && y.v.Sign() == 0 {
+			return z, e.lx.errorf(ReasonInvalidOperation, off, "/", ErrDivZero, "%v", ErrDivZero)
+		}
+		if 0 <= z.kind {
+			z.v.Quo(x.v, y.v)
+		}
+	}
+	if z.kind < 0 {
+		return z, e.lx.errorf(ReasonInvalidOperation, off, string(op), nil, "invalid operation: %v %c %v", x.kind, op, y.kind)
+	}
+	return z, e.check(z, off)
+}
+
+// check checks that the byte count is within the range of ByteCount.
+func (e *evaluator) check(x evalValue, off int) error {
+	if x.kind != evalData || 0 <= x.v.Sign() && x.v.Cmp(evalMaxData) <= 0 {
+		return nil
+	}
+	tok := e.lx.s[off:e.lx.pos]
+	return e.lx.errorf(ReasonOutOfRange, off, tok, ErrOutOfRange, "%s: %v", tok, ErrOutOfRange)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestEvalByteCount(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s      string
+		v      infounit.ByteCount
+		reason infounit.ParseErrorReason
+		off    int
+	}{
+		{"2GiB + 512MiB", infounit.Mebibyte * 2560, 0, 0},
+		{"2GiB+512MiB", infounit.Mebibyte * 2560, 0, 0},
+		{"4 * 256 MB", infounit.Megabyte * 1024, 0, 0},
+		{"1TB / 3", 333333333333, 0, 0},
+		{"2 TB / 3", 666666666667, 0, 0},
+		{"10% of 2 TB", infounit.Gigabyte * 200, 0, 0},
+		{"10 % OF 2TB", infounit.Gigabyte * 200, 0, 0},
+		{"1.5 * (1 GiB - 512 MiB)", infounit.Mebibyte * 768, 0, 0},
+		{"  (1 kB)  ", infounit.Kilobyte, 0, 0},
+		{"1 GB / 10s * 1m", infounit.Gigabyte * 6, 0, 0},
+		{"100 Mbit/s * 1 min", infounit.Megabyte * 750, 0, 0},
+		{"2 GB / 1 GB * 3 MB", infounit.Megabyte * 6, 0, 0},
+		{"8 bit + 1 B", 2, 0, 0},
+		{"1 kbit", 125, 0, 0},
+		{"12 bit", 2, 0, 0},
+		{"-1 kB + 2 kB", 0, infounit.ReasonOutOfRange, 0},
+		{"1 kB - 2 kB", 0, infounit.ReasonOutOfRange, 5},
+		{"16 EiB - 1 B", 0, infounit.ReasonOutOfRange, 0},
+		{"(8 EiB - 1 B) * 2 + 1 B", infounit.ByteCount(1<<64 - 1), 0, 0},
+		{"16 EiB", 0, infounit.ReasonOutOfRange, 0},
+		{"10 EB * 2", 0, infounit.ReasonOutOfRange, 6},
+		{"10 EB * 2 / 4", 0, infounit.ReasonOutOfRange, 6},
+		{"1 GB / 0", 0, infounit.ReasonInvalidOperation, 5},
+		{"1 GB + 1 s", 0, infounit.ReasonInvalidOperation, 5},
+		{"1 GB * 1 GB", 0, infounit.ReasonInvalidOperation, 5},
+		{"1 GB / 1 s", 0, infounit.ReasonInvalidOperation, 0},
+		{"1024", 0, infounit.ReasonMissingUnit, 0},
+		{"1 GB GB", 0, infounit.ReasonTrailingInput, 5},
+		{"1 GB 2", 0, infounit.ReasonTrailingInput, 5},
+		{"(1 GB", 0, infounit.ReasonBadNumber, 5},
+		{"1 GB +", 0, infounit.ReasonBadNumber, 6},
+		{"1 jigobyte", 0, infounit.ReasonUnknownUnit, 2},
+		{"1TB/x", 0, infounit.ReasonUnknownUnit, 1},
+		{"1TB/%", 0, infounit.ReasonBadNumber, 4},
+		{"", 0, infounit.ReasonBadNumber, 0},
+		{strings.Repeat("(", 100) + "1 GB" + strings.Repeat(")", 100), infounit.Gigabyte, 0, 0},
+		{strings.Repeat("(", 101) + "1 GB" + strings.Repeat(")", 101), 0, infounit.ReasonBadNumber, 100},
+		{strings.Repeat("(", 1000000), 0, infounit.ReasonBadNumber, 100},
+		{strings.Repeat("+", 100) + "1 GB", infounit.Gigabyte, 0, 0},
+		{strings.Repeat("-", 101) + "1 GB", 0, infounit.ReasonBadNumber, 100},
+		{strings.Repeat("(+", 50) + "1 GB" + strings.Repeat(")", 50), infounit.Gigabyte, 0, 0},
+		{strings.Repeat("(-", 51) + "1 GB" + strings.Repeat(")", 51), 0, infounit.ReasonBadNumber, 100},
+	}
+	for _, c := range tc {
+		v, err := infounit.EvalByteCount(c.s)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%q: want: %d, got: %d, %v", c.s, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		switch {
+		case !errors.As(err, &pe):
+			t.Errorf("%q: ParseError expected, got: %d, %v", c.s, v, err)
+		case pe.Reason != c.reason || pe.Offset != c.off:
+			t.Errorf("%q: want: %v at %d, got: %v at %d: %v", c.s, c.reason, c.off, pe.Reason, pe.Offset, err)
+		}
+	}
+
+	_, err := infounit.EvalByteCount("1 GB / (2 - 2)")
+	if !errors.Is(err, infounit.ErrDivZero) {
+		t.Errorf("ErrDivZero expected, got: %v", err)
+	}
+	_, err = infounit.EvalByteCount("10 EB * 2")
+	if !errors.Is(err, infounit.ErrOutOfRange) {
+		t.Errorf("ErrOutOfRange expected, got: %v", err)
+	}
+	const es = "invalid byte count: 1 GB + 1 s: invalid operation: byte count + duration"
+	if _, err = infounit.EvalByteCount("1 GB + 1 s"); err == nil || err.Error() != es {
+		t.Errorf("want: %q, got: %v", es, err)
+	}
+}
+
+//
+func TestEvalBitRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s      string
+		v      infounit.BitRate
+		reason infounit.ParseErrorReason
+	}{
+		{"100 Mbit/s * 2", infounit.MegabitPerSecond * 200, 0},
+		{"1 GB / 10s", infounit.MegabitPerSecond * 800, 0},
+		{"1 GB / 100ms", infounit.GigabitPerSecond * 80, 0},
+		{"80% of 1 Gbit/s", infounit.MegabitPerSecond * 800, 0},
+		{"10 MB/s + 20 Mbps", infounit.MegabitPerSecond * 100, 0},
+		{"1 Mbit/s - 2 Mbit/s", -infounit.MegabitPerSecond, 0},
+		{"1 GB / 1 Gbit/s", 0, infounit.ReasonInvalidOperation},
+		{"1 GB / 0s", 0, infounit.ReasonInvalidOperation},
+		{"1 GB", 0, infounit.ReasonInvalidOperation},
+		{"100", 0, infounit.ReasonMissingUnit},
+		{strings.Repeat("-", 99) + "1 Mbit/s", -infounit.MegabitPerSecond, 0},
+		{strings.Repeat("-", 101) + "1 Mbit/s", 0, infounit.ReasonBadNumber},
+	}
+	for _, c := range tc {
+		v, err := infounit.EvalBitRate(c.s)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%q: want: %v, got: %v, %v", c.s, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		if !errors.As(err, &pe) || pe.Reason != c.reason {
+			t.Errorf("%q: %v expected, got: %v, %v", c.s, c.reason, v, err)
+		}
+	}
+}
@@ -0,0 +1,550 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// PrefixSystem specifies the family of unit prefixes used by a Formatter.
+type PrefixSystem int
+
+// Prefix systems.
+const (
+	SIPrefixes     PrefixSystem = iota // k, M, G, ...; powers of 1000
+	BinaryPrefixes                     // Ki, Mi, Gi, ...; powers of 1024
+)
+
+// PrefixScale specifies a unit prefix regardless of the prefix system. It is
+// used to limit the range of prefixes selected by a Formatter.
+type PrefixScale int
+
+// Prefix scales. The zero value PrefixAny means no limit.
+const (
+	PrefixAny  PrefixScale = iota // no limit
+	PrefixNone                    // no prefix; e.g. "B", "bit/s"
+	PrefixKilo                    // k or Ki
+	PrefixMega                    // M or Mi
+	PrefixGiga                    // G or Gi
+	PrefixTera                    // T or Ti
+	PrefixPeta                    // P or Pi
+	PrefixExa                     // E or Ei
+)
+
+// PluralMode specifies when the plural form of long unit names is used.
+type PluralMode int
+
+// Plural modes.
+const (
+	// PluralExact uses the singular form only when the value is exactly one
+	// unit, e.g. "1 byte" and "1.0 kilobyte" for 1000 bytes, but "1.0
+	// kilobytes" for 1001 bytes. This is the behavior of the Printf verbs.
+	PluralExact PluralMode = iota
+
+	// PluralDisplayed uses the singular form only when the printed number is
+	// exactly "1", e.g. "1 kilobyte", but "1.0 kilobytes".
+	PluralDisplayed
+
+	// PluralNever always uses the singular form, e.g. "2.5 kilobyte".
+	PluralNever
+)
+
+// Formatter holds a set of formatting options, as an alternative to the
+// Printf verbs and flags. This is useful when the options are chosen at
+// runtime, e.g. from a configuration:
+//
+// 	f := infounit.Formatter{
+// 		Prefixes:  infounit.BinaryPrefixes,
+// 		Precision: 2,
+// 		LongNames: true,
+// 	}
+// 	f.FormatByteCount(infounit.Gigabyte) // "953.67mebibytes"
+//
+// The digits are specified either by Precision, the number of decimal places,
+// or by SignificantDigits, the number of significant digits, e.g. "1.23GB" and
+// "123GB" for 3.
+//
+// The zero value formats with SI prefixes, precision 0, no separator, and
+// abbreviated unit names. A Formatter is safe for concurrent use as long as it
+// is not modified.
+type Formatter struct {
+	// Prefixes is the family of unit prefixes, SI or binary.
+	Prefixes PrefixSystem
+
+	// Precision is the number of digits after the decimal point. If it is
+	// negative, the default precision of the Printf verbs is used; the
+	// smallest number of digits necessary to represent the value as
+	// float64. While no prefix is used, ByteCount and BitCount values are
+	// always printed without decimal parts. Precision is ignored if
+	// SignificantDigits is positive.
+	Precision int
+
+	// Separator is inserted between the digits and the unit, e.g. " ".
+	Separator string
+
+	// LongNames uses the long unit names, e.g. "kilobytes" instead of "kB".
+	LongNames bool
+
+	// MinPrefix and MaxPrefix limit the range of selected prefixes. For
+	// example, with MinPrefix PrefixMega, 1000 bytes is formatted as
+	// "0.001MB". PrefixAny means no limit.
+	MinPrefix, MaxPrefix PrefixScale
+
+	// Plural specifies when the plural form of long unit names is used.
+	Plural PluralMode
+
+	// SignificantDigits, if positive, is the number of significant digits
+	// printed instead of Precision, like "ls -h" and "top"; e.g. "1.23GB",
+	// "12.3GB" and "123GB" for 3. The integer digits are never omitted, so
+	// 1000 KiB is printed as "1000KiB". The prefix is selected after
+	// rounding, so 999.96 MB is printed as "1.00GB", not as "1000MB". As
+	// with Precision, ByteCount and BitCount values without prefix are
+	// printed without decimal parts.
+	SignificantDigits int
+
+	// PlusSign prints the plus sign for non-negative values of the signed
+	// types, ByteDelta, BitDelta, BitRate and ByteRate.
+	PlusSign bool
+
+	// Number is the decimal mark and the digit grouping of the number, e.g.
+	// NumberFormatGerman for "1.234,5 MB". The values formatted with a
+	// NumberFormat are parsed by a Parser accepting the same NumberFormat.
+	Number NumberFormat
+
+	// Language is the BCP 47 language tag of the unit names, e.g. "fr" for
+	// "Mo" and "mégaoctets", or "ru-RU" for "МБ" and "мегабайта". The
+	// supported languages are English, German, French, Japanese and
+	// Russian. The empty tag and the unsupported languages mean English.
+	// With a language other than English, the plural forms of the long
+	// names are chosen by the CLDR plural rules of the language for the
+	// printed number, and Plural is used only to choose PluralNever. Note
+	// that the decimal mark is specified by Number, not by Language.
+	Language string
+}
+
+// unitName holds the names of a unit without prefix.
+type unitName struct {
+	abbr, full       string // e.g. "B", "byte"
+	sufAbbr, sufFull string // e.g. "/s", "per second"
+}
+
+// FormatByteCount returns the string representation of the ByteCount value.
+func (f *Formatter) FormatByteCount(v ByteCount) string {
+	var buf [fmtBufSize]byte
+	return string(f.appendUint(buf[:0], uint64(v), unitByte))
+}
+
+// FormatBitCount returns the string representation of the BitCount value.
+func (f *Formatter) FormatBitCount(v BitCount) string {
+	var buf [fmtBufSize]byte
+	return string(f.appendUint(buf[:0], uint64(v), unitBit))
+}
+
+// FormatByteDelta returns the string representation of the ByteDelta value.
+func (f *Formatter) FormatByteDelta(v ByteDelta) string {
+	var buf [fmtBufSize]byte
+	return string(f.appendInt(buf[:0], int64(v), unitByte))
+}
+
+// FormatBitDelta returns the string representation of the BitDelta value.
+func (f *Formatter) FormatBitDelta(v BitDelta) string {
+	var buf [fmtBufSize]byte
+	return string(f.appendInt(buf[:0], int64(v), unitBit))
+}
+
+// FormatBitRate returns the string representation of the BitRate value.
+func (f *Formatter) FormatBitRate(v BitRate) string {
+	var buf [fmtBufSize]byte
+	return string(f.appendFloat(buf[:0], float64(v), unitBitRate))
+}
+
+// FormatByteRate returns the string representation of the ByteRate value.
+func (f *Formatter) FormatByteRate(v ByteRate) string {
+	var buf [fmtBufSize]byte
+	return string(f.appendFloat(buf[:0], float64(v), unitByteRate))
+}
+
+// FormatByteCountIn returns the string representation of the ByteCount value in
+// the unit, such as Mebibyte, instead of the automatically selected one. The
+// unit must be Byte or one of the constants with a prefix; otherwise,
+// ErrInvalidUnit is returned.
+func (f *Formatter) FormatByteCountIn(v, unit ByteCount) (string, error) {
+	g, err := f.fixedUnit(uint64(unit))
+	if err != nil {
+		return "", err
+	}
+	var buf [fmtBufSize]byte
+	return string(g.appendUint(buf[:0], uint64(v), unitByte)), nil
+}
+
+// FormatBitCountIn returns the string representation of the BitCount value in
+// the unit, such as Kilobit. See FormatByteCountIn for details.
+func (f *Formatter) FormatBitCountIn(v, unit BitCount) (string, error) {
+	g, err := f.fixedUnit(uint64(unit))
+	if err != nil {
+		return "", err
+	}
+	var buf [fmtBufSize]byte
+	return string(g.appendUint(buf[:0], uint64(v), unitBit)), nil
+}
+
+// FormatBitRateIn returns the string representation of the BitRate value in
+// the unit, such as MegabitPerSecond. See FormatByteCountIn for details.
+func (f *Formatter) FormatBitRateIn(v, unit BitRate) (string, error) {
+	g, err := f.fixedFloatUnit(float64(unit))
+	if err != nil {
+		return "", err
+	}
+	var buf [fmtBufSize]byte
+	return string(g.appendFloat(buf[:0], float64(v), unitBitRate)), nil
+}
+
+// fixedUnit returns a copy of f which always uses the unit, a power of 1000 or
+// 1024 up to exa or exbi. If unit is not exactly such a value, ErrInvalidUnit
+// is returned.
+func (f *Formatter) fixedUnit(unit uint64) (*Formatter, error) {
+	g := *f
+	if unit == 1 {
+		g.MinPrefix, g.MaxPrefix = PrefixNone, PrefixNone
+		return &g, nil
+	}
+	for _, ps := range []PrefixSystem{SIPrefixes, BinaryPrefixes} {
+		g.Prefixes = ps
+		for i, t := range g.prefix().thresholds {
+			if unit == t {
+				g.MinPrefix = PrefixKilo + PrefixScale(i)
+				g.MaxPrefix = g.MinPrefix
+				return &g, nil
+			}
+		}
+	}
+	return nil, ErrInvalidUnit
+}
+
+// fixedFloatUnit is the same as fixedUnit, for the unit of BitRate, which must
+// be a whole number.
+func (f *Formatter) fixedFloatUnit(unit float64) (*Formatter, error) {
+	if unit < 1 || math.MaxUint64 <= unit || unit != math.Trunc(unit) {
+		return nil, ErrInvalidUnit
+	}
+	return f.fixedUnit(uint64(unit))
+}
+
+// uintUnit returns the unit in which the value v of ByteCount or BitCount is
+// formatted by f. As with the formatting, the unit is selected after rounding.
+func (f *Formatter) uintUnit(v uint64) uint64 {
+	var buf [fmtBufSize]byte
+	_, i := f.appendUintNumber(buf[:0], v)
+	return f.prefix().unit(i)
+}
+
+// floatUnit is the same as uintUnit, for the non-negative value v of BitRate
+// and ByteRate.
+func (f *Formatter) floatUnit(v float64) float64 {
+	var buf [fmtBufSize]byte
+	_, i := f.appendFloatNumber(buf[:0], v)
+	return float64(f.prefix().unit(i))
+}
+
+// fmtBufSize is the size of the buffers on the stack used for formatting,
+// which is large enough for most of the values formatted with long names.
+const fmtBufSize = 64
+
+// defaultFormatter is the Formatter for the default format, "% .1s", which
+// is used by String and the %v verb.
+var defaultFormatter = Formatter{Precision: 1, Separator: " "}
+
+// orDefault returns f, or the default Formatter if f is nil.
+func (f *Formatter) orDefault() *Formatter {
+	if f == nil {
+		return &defaultFormatter
+	}
+	return f
+}
+
+// verbFormatter returns the Formatter for the Printf verb and flags. The verbs
+// in upper case use binary prefixes, and the verbs %h and %H take the precision
+// as the number of significant digits.
+func verbFormatter(s fmt.State, verb rune) Formatter {
+	f := Formatter{Precision: -1, LongNames: s.Flag(int('#'))}
+	if prec, ok := s.Precision(); ok {
+		f.Precision = prec
+	}
+	if s.Flag(int(' ')) {
+		f.Separator = " "
+	}
+	if 'A' <= verb && verb <= 'Z' {
+		f.Prefixes = BinaryPrefixes
+	}
+	if verb == 'h' || verb == 'H' {
+		switch {
+		case f.Precision < 0:
+			f.SignificantDigits = 3
+		case f.Precision == 0:
+			f.SignificantDigits = 1
+		default:
+			f.SignificantDigits = f.Precision
+		}
+	}
+	return f
+}
+
+// precision returns the number of digits after the decimal point of the
+// number, the integer part of which is n.
+func (f *Formatter) precision(n uint64) int {
+	if f.SignificantDigits <= 0 {
+		return f.Precision
+	}
+	d := 1
+	for ; 10 <= n; n /= 10 {
+		d++
+	}
+	if f.SignificantDigits <= d {
+		return 0
+	}
+	return f.SignificantDigits - d
+}
+
+// floatPrecision is the same as precision, for the value v/p.unit(i). The
+// leading zeros of the values less than 1 are not significant; e.g. "0.500".
+func (f *Formatter) floatPrecision(v float64, p *prefix, i int) int {
+	v /= float64(p.unit(i))
+	switch {
+	case 1e19 <= v:
+		return f.precision(math.MaxUint64)
+	case 0 < f.SignificantDigits && 0 < v && v < 1:
+		return f.SignificantDigits - 1 - int(math.Floor(math.Log10(v)))
+	}
+	return f.precision(uint64(v))
+}
+
+// prefix returns the prefix table for the prefix system.
+func (f *Formatter) prefix() *prefix {
+	if f.Prefixes == BinaryPrefixes {
+		return binPrefix
+	}
+	return siPrefix
+}
+
+// bounds returns the range of prefix indexes, where -1 means no prefix.
+func (f *Formatter) bounds() (int, int) {
+	clamp := func(s PrefixScale, def int) int {
+		switch {
+		case s == PrefixAny:
+			return def
+		case s < PrefixNone:
+			return -1
+		case PrefixExa < s:
+			return 5
+		}
+		return int(s - PrefixKilo)
+	}
+	lo, hi := clamp(f.MinPrefix, -1), clamp(f.MaxPrefix, 5)
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// appendUnit appends the separator and the unit name with the i-th prefix to
+// dst. If i is negative, no prefix is appended. cat is the plural category of
+// the long name.
+func (f *Formatter) appendUnit(dst []byte, p *prefix, i int, u *unitName, cat pluralCategory) []byte {
+	if l := findLocale(f.Language); l != nil {
+		return l.appendUnit(dst, f, i, u, cat)
+	}
+	dst = append(dst, f.Separator...)
+	if f.LongNames {
+		if 0 <= i {
+			dst = append(dst, p.preFull[i]...)
+		}
+		dst = append(dst, u.full...)
+		if cat != pluralOne {
+			dst = append(dst, 's')
+		}
+		if u.sufFull != "" {
+			dst = append(dst, ' ')
+			dst = append(dst, u.sufFull...)
+		}
+		return dst
+	}
+	if 0 <= i {
+		dst = append(dst, p.preAbbr[i]...)
+	}
+	dst = append(dst, u.abbr...)
+	return append(dst, u.sufAbbr...)
+}
+
+// plural returns the plural category of the long unit name. exact is whether
+// the value is exactly one unit, and num is the printed number.
+func (f *Formatter) plural(exact bool, num []byte) pluralCategory {
+	l := findLocale(f.Language)
+	switch {
+	case f.Plural == PluralNever:
+		return pluralOne
+	case l != nil:
+		return l.plural(newPluralOperands(num))
+	case f.Plural == PluralDisplayed && string(num) == "1", f.Plural == PluralExact && exact:
+		return pluralOne
+	}
+	return pluralOther
+}
+
+// unit returns the value of the i-th prefix, or 1 if i is negative.
+func (p *prefix) unit(i int) uint64 {
+	if i < 0 {
+		return 1
+	}
+	return p.thresholds[i]
+}
+
+// appendUint is used by both ByteCount and BitCount. The value is formatted
+// exactly using integer arithmetic, so it is never affected by the precision of
+// float64.
+func (f *Formatter) appendUint(dst []byte, v uint64, u *unitName) []byte {
+	p := f.prefix()
+	start := len(dst)
+	dst, i := f.appendUintNumber(dst, v)
+	cat := f.plural(v == p.unit(i), dst[start:])
+	dst = f.Number.appendLocal(dst, start)
+	return f.appendUnit(dst, p, i, u, cat)
+}
+
+// appendUintNumber appends the number part of the value v formatted by
+// appendUint to dst, and returns the extended buffer and the index of the
+// selected prefix, which is -1 for no prefix.
+func (f *Formatter) appendUintNumber(dst []byte, v uint64) ([]byte, int) {
+	p := f.prefix()
+	lo, hi := f.bounds()
+
+	i := hi
+	for lo < i && v < p.unit(i) {
+		i--
+	}
+	if i < 0 {
+		return strconv.AppendUint(dst, v, 10), i
+	}
+	start := len(dst)
+	prec := f.precision(v / p.thresholds[i])
+	dst = appendQuotient(dst, v, p.thresholds[i], prec)
+	if i < hi && p.base <= intPart(dst[start:]) { // rounded up to the next prefix
+		i++
+		prec = f.precision(v / p.thresholds[i])
+		dst = appendQuotient(dst[:start], v, p.thresholds[i], prec)
+	}
+	if n := f.precision(intPart(dst[start:])); n < prec { // rounded up to more digits; e.g. 99.96 to 100.0
+		dst = appendQuotient(dst[:start], v, p.thresholds[i], n)
+	}
+	return dst, i
+}
+
+// appendInt is used by both ByteDelta and BitDelta. The magnitude is formatted
+// by appendUint, and the sign is prepended.
+func (f *Formatter) appendInt(dst []byte, v int64, u *unitName) []byte {
+	switch {
+	case v < 0:
+		dst = append(dst, '-')
+	case f.PlusSign:
+		dst = append(dst, '+')
+	}
+	return f.appendUint(dst, absInt64(v), u)
+}
+
+// appendFloat is used by both BitRate and ByteRate.
+func (f *Formatter) appendFloat(dst []byte, v float64, u *unitName) []byte {
+	p := f.prefix()
+
+	if math.IsNaN(v) || math.IsInf(v, +1) || math.IsInf(v, -1) {
+		dst = strconv.AppendFloat(dst, v, 'f', f.Precision, 64)
+		return f.appendUnit(dst, p, -1, u, f.plural(false, nil))
+	}
+
+	// The prefix is selected by the magnitude, and the sign is prepended.
+	switch {
+	case math.Signbit(v):
+		dst = append(dst, '-')
+		v = -v
+	case f.PlusSign:
+		dst = append(dst, '+')
+	}
+	start := len(dst)
+	dst, i := f.appendFloatNumber(dst, v)
+	cat := f.plural(v == float64(p.unit(i)), dst[start:])
+	dst = f.Number.appendLocal(dst, start)
+	return f.appendUnit(dst, p, i, u, cat)
+}
+
+// appendFloatNumber appends the number part of the non-negative value v
+// formatted by appendFloat to dst, and returns the extended buffer and the
+// index of the selected prefix, which is -1 for no prefix.
+func (f *Formatter) appendFloatNumber(dst []byte, v float64) ([]byte, int) {
+	p := f.prefix()
+	start := len(dst)
+	lo, hi := f.bounds()
+	i := p.index(v)
+	switch {
+	case i < lo:
+		i = lo
+	case hi < i:
+		i = hi
+	}
+	prec := f.floatPrecision(v, p, i)
+	dst = p.appendFloat(dst, v, i, prec)
+	if i < hi && p.base <= intPart(dst[start:]) { // rounded up to the next prefix
+		i++
+		prec = f.floatPrecision(v, p, i)
+		dst = p.appendFloat(dst[:start], v, i, prec)
+	}
+	if n := f.precision(intPart(dst[start:])); n < prec && intPart(dst[start:]) != 0 { // rounded up to more digits; e.g. 99.96 to 100.0
+		dst = p.appendFloat(dst[:start], v, i, n)
+	}
+	return dst, i
+}
+
+// fmtPad holds the characters used to pad the formatted values.
+var fmtPad = [2][16]byte{
+	{' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' '},
+	{'0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0'},
+}
+
+// writePadded writes the formatted value b to s, padded to the width specified
+// to s. The flag '-' pads with spaces on the right, and the flag '0' pads with
+// leading zeros. Unlike the %s verb in the package fmt, the leading zeros are
+// inserted after the sign, as with the numeric verbs.
+func writePadded(s fmt.State, b []byte) {
+	wid, ok := s.Width()
+	n := wid - utf8.RuneCount(b)
+	if !ok || n <= 0 {
+		_, _ = s.Write(b)
+		return
+	}
+	pad := func(c int) {
+		for ; 0 < n; n -= len(fmtPad[c]) {
+			if n < len(fmtPad[c]) {
+				_, _ = s.Write(fmtPad[c][:n])
+				break
+			}
+			_, _ = s.Write(fmtPad[c][:])
+		}
+	}
+	switch {
+	case s.Flag(int('-')):
+		_, _ = s.Write(b)
+		pad(0)
+	case s.Flag(int('0')):
+		if 0 < len(b) && (b[0] == '-' || b[0] == '+') {
+			_, _ = s.Write(b[:1])
+			b = b[1:]
+		}
+		pad(1)
+		_, _ = s.Write(b)
+	default:
+		pad(0)
+		_, _ = s.Write(b)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"fmt"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func ExampleFormatter() {
+	f := infounit.Formatter{
+		Prefixes:  infounit.BinaryPrefixes,
+		Precision: 2,
+		Separator: " ",
+		LongNames: true,
+	}
+	fmt.Println(f.FormatByteCount(infounit.Gigabyte))
+	fmt.Println(f.FormatBitCount(infounit.Kibibit))
+	fmt.Println(f.FormatBitRate(infounit.MegabitPerSecond * 100))
+
+	f = infounit.Formatter{
+		Precision: 1,
+		Separator: " ",
+		MaxPrefix: infounit.PrefixMega,
+		PlusSign:  true,
+	}
+	fmt.Println(f.FormatByteCount(infounit.Terabyte * 3))
+	fmt.Println(f.FormatByteDelta(infounit.ByteDelta(infounit.Gigabyte) * 2))
+	// Output:
+	// 953.67 mebibytes
+	// 1.00 kibibit
+	// 95.37 mebibits per second
+	// 3000000.0 MB
+	// +2000.0 MB
+}
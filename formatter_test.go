@@ -0,0 +1,266 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestFormatter_printfCompatible(t *testing.T) {
+	t.Parallel()
+
+	vals := []uint64{0, 1, 999, 1000, 1023, 1024, 999960, 123456789, 987654321, math.MaxUint64}
+	for _, v := range vals {
+		for _, pfx := range []infounit.PrefixSystem{infounit.SIPrefixes, infounit.BinaryPrefixes} {
+			for _, prec := range []int{-1, 0, 1, 3} {
+				for _, long := range []bool{false, true} {
+					verb := "s"
+					if pfx == infounit.BinaryPrefixes {
+						verb = "S"
+					}
+					flags := " "
+					if long {
+						flags += "#"
+					}
+					pfmt := "%" + flags + verb
+					if 0 <= prec {
+						pfmt = fmt.Sprintf("%%%s.%d%s", flags, prec, verb)
+					}
+					f := infounit.Formatter{Prefixes: pfx, Precision: prec, Separator: " ", LongNames: long}
+
+					if s, ex := f.FormatByteCount(infounit.ByteCount(v)), fmt.Sprintf(pfmt, infounit.ByteCount(v)); s != ex {
+						t.Errorf("ByteCount %d, %q: want: %q, got: %q", v, pfmt, ex, s)
+					}
+					if s, ex := f.FormatBitCount(infounit.BitCount(v)), fmt.Sprintf(pfmt, infounit.BitCount(v)); s != ex {
+						t.Errorf("BitCount %d, %q: want: %q, got: %q", v, pfmt, ex, s)
+					}
+					if s, ex := f.FormatBitRate(infounit.BitRate(v)), fmt.Sprintf(pfmt, infounit.BitRate(v)); s != ex {
+						t.Errorf("BitRate %d, %q: want: %q, got: %q", v, pfmt, ex, s)
+					}
+					if s, ex := f.FormatByteRate(infounit.ByteRate(v)), fmt.Sprintf(pfmt, infounit.ByteRate(v)); s != ex {
+						t.Errorf("ByteRate %d, %q: want: %q, got: %q", v, pfmt, ex, s)
+					}
+					d := -int64(v >> 1)
+					if s, ex := f.FormatByteDelta(infounit.ByteDelta(d)), fmt.Sprintf(pfmt, infounit.ByteDelta(d)); s != ex {
+						t.Errorf("ByteDelta %d, %q: want: %q, got: %q", d, pfmt, ex, s)
+					}
+					if s, ex := f.FormatBitDelta(infounit.BitDelta(d)), fmt.Sprintf(pfmt, infounit.BitDelta(d)); s != ex {
+						t.Errorf("BitDelta %d, %q: want: %q, got: %q", d, pfmt, ex, s)
+					}
+				}
+			}
+		}
+	}
+}
+
+//
+func TestFormatter_FormatByteCount(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		f infounit.Formatter
+		v uint64
+		s string
+	}{
+		{infounit.Formatter{}, 2500000000, "2GB"},
+		{infounit.Formatter{}, 3500000000, "4GB"},
+		{infounit.Formatter{Precision: -1}, 2500000000, "2.5GB"},
+		{infounit.Formatter{Precision: 2, Separator: "_"}, 2500000000, "2.50_GB"},
+		{infounit.Formatter{Prefixes: infounit.BinaryPrefixes, Precision: 2, LongNames: true}, 1000000000, "953.67mebibytes"},
+
+		{infounit.Formatter{Precision: -1, MinPrefix: infounit.PrefixMega}, 1000, "0.001MB"},
+		{infounit.Formatter{Precision: -1, MinPrefix: infounit.PrefixMega}, 0, "0MB"},
+		{infounit.Formatter{Precision: 1, MinPrefix: infounit.PrefixKilo}, 1, "0.0kB"},
+		{infounit.Formatter{Precision: -1, MaxPrefix: infounit.PrefixGiga}, 5000000000000, "5000GB"},
+		{infounit.Formatter{Precision: -1, MaxPrefix: infounit.PrefixNone}, 5000000000000, "5000000000000B"},
+		{infounit.Formatter{Precision: 1, MaxPrefix: infounit.PrefixTera}, 999999999999999, "1000.0TB"},
+		{infounit.Formatter{Precision: 1}, 999999999999999, "1.0PB"},
+		{infounit.Formatter{Precision: -1, MinPrefix: infounit.PrefixGiga, MaxPrefix: infounit.PrefixKilo}, 5000, "0.000005GB"},
+		{infounit.Formatter{Precision: -1, MinPrefix: infounit.PrefixKilo, MaxPrefix: infounit.PrefixKilo}, 5000000, "5000kB"},
+		{infounit.Formatter{Precision: -1, MinPrefix: infounit.PrefixNone, MaxPrefix: infounit.PrefixExa}, 5000000, "5MB"},
+
+		{infounit.Formatter{Precision: 1, LongNames: true}, 1000, "1.0kilobyte"},
+		{infounit.Formatter{Precision: 1, LongNames: true}, 1001, "1.0kilobytes"},
+		{infounit.Formatter{Precision: 0, LongNames: true}, 1001, "1kilobytes"},
+		{infounit.Formatter{Precision: 1, LongNames: true, Plural: infounit.PluralDisplayed}, 1000, "1.0kilobytes"},
+		{infounit.Formatter{Precision: 0, LongNames: true, Plural: infounit.PluralDisplayed}, 1001, "1kilobyte"},
+		{infounit.Formatter{Precision: 0, LongNames: true, Plural: infounit.PluralDisplayed}, 1, "1byte"},
+		{infounit.Formatter{Precision: 0, LongNames: true, Plural: infounit.PluralDisplayed}, 0, "0bytes"},
+		{infounit.Formatter{Precision: 1, LongNames: true, Plural: infounit.PluralNever}, 2500, "2.5kilobyte"},
+		{infounit.Formatter{Precision: 1, Plural: infounit.PluralExact}, 2500, "2.5kB"},
+
+		{infounit.Formatter{Precision: -1, PlusSign: true}, 2500, "2.5kB"},
+
+		{infounit.Formatter{SignificantDigits: 3}, 1234567890, "1.23GB"},
+		{infounit.Formatter{SignificantDigits: 3}, 12345678900, "12.3GB"},
+		{infounit.Formatter{SignificantDigits: 3}, 123456789000, "123GB"},
+		{infounit.Formatter{SignificantDigits: 3, Precision: 5}, 999600, "1.00MB"},
+		{infounit.Formatter{SignificantDigits: 2, Separator: " "}, 99960, "100 kB"},
+		{infounit.Formatter{SignificantDigits: 3, MaxPrefix: infounit.PrefixKilo}, 123456789, "123457kB"},
+		{infounit.Formatter{SignificantDigits: 3, Prefixes: infounit.BinaryPrefixes}, 1000 * 1024, "1000KiB"},
+	}
+
+	for _, c := range tc {
+		if s := c.f.FormatByteCount(infounit.ByteCount(c.v)); s != c.s {
+			t.Errorf(`%+v, %d: want: %q, got: %q`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestFormatter_FormatIn(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		f    infounit.Formatter
+		v    uint64
+		unit uint64
+		s    string
+	}{
+		{infounit.Formatter{Precision: 1}, 1536 * 1024 * 1024, uint64(infounit.Mebibyte), "1536.0MiB"},
+		{infounit.Formatter{Precision: 3}, 1234567, uint64(infounit.Gigabyte), "0.001GB"},
+		{infounit.Formatter{Precision: 1}, 5 * uint64(infounit.Exabyte), uint64(infounit.Kilobyte), "5000000000000000.0kB"},
+		{infounit.Formatter{Precision: 1}, 999999, uint64(infounit.Kilobyte), "1000.0kB"},
+		{infounit.Formatter{Precision: 1}, 999999, uint64(infounit.Megabyte), "1.0MB"},
+		{infounit.Formatter{Precision: 2}, 123456, uint64(infounit.Byte), "123456B"},
+		{infounit.Formatter{Precision: 1, Separator: " ", LongNames: true}, 1000, uint64(infounit.Kilobyte), "1.0 kilobyte"},
+		{infounit.Formatter{Precision: 1, Prefixes: infounit.BinaryPrefixes, MaxPrefix: infounit.PrefixKilo}, 1000, uint64(infounit.Gigabyte), "0.0GB"},
+	}
+
+	for _, c := range tc {
+		s, err := c.f.FormatByteCountIn(infounit.ByteCount(c.v), infounit.ByteCount(c.unit))
+		if err != nil || s != c.s {
+			t.Errorf(`%+v, %d in %d: want: %q, got: %q, %v`, c.f, c.v, c.unit, c.s, s, err)
+		}
+	}
+
+	for _, unit := range []uint64{
+		0, 3, 1023, 3 * uint64(infounit.Kilobyte), uint64(infounit.Kilobyte) + 1,
+		uint64(infounit.Exabyte) + 1, uint64(infounit.Exbibyte) + 1, math.MaxUint64,
+	} {
+		var f infounit.Formatter
+		if s, err := f.FormatByteCountIn(1500, infounit.ByteCount(unit)); !errors.Is(err, infounit.ErrInvalidUnit) {
+			t.Errorf("FormatByteCountIn: unit %d: unexpected result: %q, %v", unit, s, err)
+		}
+	}
+	if s, err := infounit.ByteCount(1500).FormatIn(3*infounit.Kilobyte, 1); !errors.Is(err, infounit.ErrInvalidUnit) {
+		t.Errorf("FormatIn: unexpected result: %q, %v", s, err)
+	}
+	if s, err := infounit.ByteCount(5).FormatIn(infounit.Exabyte+1, 1); !errors.Is(err, infounit.ErrInvalidUnit) {
+		t.Errorf("FormatIn: unexpected result: %q, %v", s, err)
+	}
+	if s, err := infounit.BitCount(5).FormatIn(infounit.Exbibit+1, 1); !errors.Is(err, infounit.ErrInvalidUnit) {
+		t.Errorf("FormatIn: unexpected result: %q, %v", s, err)
+	}
+	for _, unit := range []float64{0, 0.5, 1000.5, -1000, math.NaN(), math.Inf(+1)} {
+		if s, err := infounit.BitRate(5).FormatIn(infounit.BitRate(unit), 1); !errors.Is(err, infounit.ErrInvalidUnit) {
+			t.Errorf("FormatIn: unit %v: unexpected result: %q, %v", unit, s, err)
+		}
+	}
+
+	f := infounit.Formatter{Precision: 2, Separator: " ", LongNames: true}
+	if s, err := f.FormatBitCountIn(infounit.Kibibit*3, infounit.Kibibit); err != nil || s != "3.00 kibibits" {
+		t.Errorf("FormatBitCountIn: unexpected result: %q, %v", s, err)
+	}
+	if s, err := f.FormatBitRateIn(-infounit.GigabitPerSecond*1.5, infounit.MegabitPerSecond); err != nil || s != "-1500.00 megabits per second" {
+		t.Errorf("FormatBitRateIn: unexpected result: %q, %v", s, err)
+	}
+}
+
+//
+func TestBestUnit(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v    uint64
+		ps   infounit.PrefixSystem
+		prec int
+		unit uint64
+	}{
+		{0, infounit.SIPrefixes, 1, uint64(infounit.Byte)},
+		{999, infounit.SIPrefixes, 1, uint64(infounit.Byte)},
+		{999949, infounit.SIPrefixes, 1, uint64(infounit.Kilobyte)},
+		{999950, infounit.SIPrefixes, 1, uint64(infounit.Megabyte)},
+		{999999, infounit.SIPrefixes, 1, uint64(infounit.Megabyte)},
+		{999999, infounit.SIPrefixes, 3, uint64(infounit.Kilobyte)},
+		{999999, infounit.SIPrefixes, -1, uint64(infounit.Kilobyte)},
+		{999999, infounit.SIPrefixes, 0, uint64(infounit.Megabyte)},
+		{1048535, infounit.BinaryPrefixes, 1, uint64(infounit.Mebibyte)},
+		{1048535, infounit.BinaryPrefixes, 2, uint64(infounit.Kibibyte)},
+	}
+
+	for _, c := range tc {
+		vs := []infounit.ByteCount{1, infounit.ByteCount(c.v)}
+		if unit := infounit.BestByteCountUnit(vs, c.ps, c.prec); uint64(unit) != c.unit {
+			t.Errorf("%d, %v, %d: want: %d, got: %d", c.v, c.ps, c.prec, c.unit, uint64(unit))
+		}
+		bs := []infounit.BitCount{1, infounit.BitCount(c.v)}
+		if unit := infounit.BestBitCountUnit(bs, c.ps, c.prec); uint64(unit) != c.unit {
+			t.Errorf("%d, %v, %d: want: %d, got: %d", c.v, c.ps, c.prec, c.unit, uint64(unit))
+		}
+	}
+
+	unit := infounit.BestByteCountUnit([]infounit.ByteCount{999999}, infounit.SIPrefixes, 1)
+	if s, err := infounit.ByteCount(999999).FormatIn(unit, 1); err != nil || s != "1.0 MB" {
+		t.Errorf("FormatIn: unexpected result: %q, %v", s, err)
+	}
+}
+
+//
+func TestFormatter_FormatBitRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		f infounit.Formatter
+		v float64
+		s string
+	}{
+		{infounit.Formatter{}, 2500000000, "2Gbit/s"},
+		{infounit.Formatter{Precision: -1}, 2500000000, "2.5Gbit/s"},
+		{infounit.Formatter{Precision: -1, PlusSign: true}, 2500000000, "+2.5Gbit/s"},
+		{infounit.Formatter{Precision: -1, PlusSign: true}, -2500000000, "-2.5Gbit/s"},
+		{infounit.Formatter{Precision: -1, PlusSign: true}, math.Inf(+1), "+Infbit/s"},
+		{infounit.Formatter{Precision: -1, MinPrefix: infounit.PrefixKilo}, 0.005, "0.000005kbit/s"},
+		{infounit.Formatter{Precision: 0, MaxPrefix: infounit.PrefixGiga}, 5e15, "5000000Gbit/s"},
+		{infounit.Formatter{Precision: 1, MaxPrefix: infounit.PrefixKilo}, 999999, "1000.0kbit/s"},
+		{infounit.Formatter{Precision: 1, Separator: " ", LongNames: true}, 1000, "1.0 kilobit per second"},
+		{infounit.Formatter{Precision: 1, Separator: " ", LongNames: true, Plural: infounit.PluralDisplayed}, 1000, "1.0 kilobits per second"},
+		{infounit.Formatter{Prefixes: infounit.BinaryPrefixes, Precision: 2, Separator: " "}, 1 << 30, "1.00 Gibit/s"},
+	}
+
+	for _, c := range tc {
+		if s := c.f.FormatBitRate(infounit.BitRate(c.v)); s != c.s {
+			t.Errorf(`%+v, %f: want: %q, got: %q`, c.f, c.v, c.s, s)
+		}
+	}
+}
+
+//
+func TestFormatter_FormatByteDelta(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		f infounit.Formatter
+		v int64
+		s string
+	}{
+		{infounit.Formatter{Precision: 1, Separator: " "}, -1500000000, "-1.5 GB"},
+		{infounit.Formatter{Precision: 1, Separator: " ", PlusSign: true}, 1500000000, "+1.5 GB"},
+		{infounit.Formatter{Precision: 1, Separator: " ", PlusSign: true}, 0, "+0 B"},
+		{infounit.Formatter{Prefixes: infounit.BinaryPrefixes, Precision: -1, MaxPrefix: infounit.PrefixKilo}, -209715200, "-204800KiB"},
+	}
+
+	for _, c := range tc {
+		if s := c.f.FormatByteDelta(infounit.ByteDelta(c.v)); s != c.s {
+			t.Errorf(`%+v, %d: want: %q, got: %q`, c.f, c.v, c.s, s)
+		}
+	}
+}
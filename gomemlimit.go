@@ -0,0 +1,76 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"math"
+	"strconv"
+)
+
+// goMemLimitUnits is the table of the unit suffixes of GOMEMLIMIT, indexed by
+// the power of 1024.
+var goMemLimitUnits = [5]string{"B", "KiB", "MiB", "GiB", "TiB"}
+
+// ParseGoMemLimit converts a string in the syntax of the GOMEMLIMIT
+// environment variable into a ByteCount value. The syntax is an integer with
+// an optional unit suffix B, KiB, MiB, GiB or TiB, without spaces and case
+// sensitive, e.g. "512MiB". The value must not exceed math.MaxInt64, which is
+// the limit of debug.SetMemoryLimit. "off" means no limit, and is converted
+// into math.MaxInt64.
+func ParseGoMemLimit(s string) (ByteCount, error) {
+	if s == "off" {
+		return ByteCount(math.MaxInt64), nil
+	}
+	lx := lexer{s: s, typ: "ByteCount"}
+	lx.pos = digitsEnd(s, 0)
+	if lx.pos == 0 {
+		tok := s[:tokenEnd(s, 0, len(s))]
+		reason := ReasonBadNumber
+		if tok != "" && tok[0] == '-' {
+			reason = ReasonNegative
+		}
+		return 0, lx.errorf(reason, 0, tok, nil, "invalid expr: %s", tok)
+	}
+	num, unit := s[:lx.pos], s[lx.pos:]
+	exp := -1
+	for i, u := range goMemLimitUnits {
+		if u == unit {
+			exp = i
+			break
+		}
+	}
+	if exp < 0 && unit != "" {
+		return 0, lx.errorf(ReasonUnknownUnit, lx.pos, unit, nil, "unknown unit: %s", unit)
+	}
+	v, err := parseUint64(num)
+	if err == nil && 0 < exp {
+		v, err = mulUint64(v, binPrefix.thresholds[exp-1])
+	}
+	if err == nil && math.MaxInt64 < v {
+		err = ErrOutOfRange
+	}
+	if err != nil {
+		return 0, lx.errorf(numErrReason(err), 0, s, err, "%v", err)
+	}
+	return ByteCount(v), nil
+}
+
+// FormatGoMemLimit returns the representation of the ByteCount value in the
+// syntax of the GOMEMLIMIT environment variable, which is parsed by
+// ParseGoMemLimit into the same value. The largest unit that represents the
+// value as an integer is used, e.g. "1536MiB". The values of math.MaxInt64
+// or more are formatted as "off".
+func FormatGoMemLimit(v ByteCount) string {
+	if math.MaxInt64 <= v {
+		return "off"
+	}
+	n := uint64(v)
+	exp := 0
+	for n != 0 && exp+1 < len(goMemLimitUnits) && n%kibi == 0 {
+		n /= kibi
+		exp++
+	}
+	return strconv.FormatUint(n, 10) + goMemLimitUnits[exp]
+}
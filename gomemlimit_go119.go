@@ -0,0 +1,25 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+//go:build go1.19
+// +build go1.19
+
+package infounit
+
+import (
+	"math"
+	"runtime/debug"
+)
+
+// MemoryLimit returns the current soft memory limit of the Go runtime, which
+// is set by the GOMEMLIMIT environment variable or debug.SetMemoryLimit. The
+// limit is read with debug.SetMemoryLimit(-1), which does not change it. ok
+// is false if there is no limit, that is, the limit is math.MaxInt64.
+func MemoryLimit() (limit ByteCount, ok bool) {
+	v := debug.SetMemoryLimit(-1)
+	if v == math.MaxInt64 {
+		return ByteCount(v), false
+	}
+	return ByteCount(v), true
+}
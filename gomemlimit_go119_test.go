@@ -0,0 +1,35 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+//go:build go1.19
+// +build go1.19
+
+package infounit_test
+
+import (
+	"math"
+	"runtime/debug"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+// This changes the memory limit of the process, so it must not be run in
+// parallel with the other tests reading it.
+func TestMemoryLimit(t *testing.T) {
+	prev := debug.SetMemoryLimit(-1)
+	defer debug.SetMemoryLimit(prev)
+
+	debug.SetMemoryLimit(math.MaxInt64)
+	if v, ok := infounit.MemoryLimit(); ok || v != math.MaxInt64 {
+		t.Errorf("no limit: unexpected result: %d, %v", v, ok)
+	}
+	debug.SetMemoryLimit(int64(infounit.Gibibyte * 3))
+	if v, ok := infounit.MemoryLimit(); !ok || v != infounit.Gibibyte*3 {
+		t.Errorf("3 GiB: unexpected result: %d, %v", v, ok)
+	}
+	if v := debug.SetMemoryLimit(-1); v != int64(infounit.Gibibyte*3) {
+		t.Errorf("the limit has been changed: %d", v)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestParseGoMemLimit(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s      string
+		v      infounit.ByteCount
+		reason infounit.ParseErrorReason
+	}{
+		{"0", 0, 0},
+		{"100", 100, 0},
+		{"100B", 100, 0},
+		{"512MiB", infounit.Mebibyte * 512, 0},
+		{"1GiB", infounit.Gibibyte, 0},
+		{"8KiB", infounit.Kibibyte * 8, 0},
+		{"2TiB", infounit.Tebibyte * 2, 0},
+		{"off", math.MaxInt64, 0},
+		{"9223372036854775807", math.MaxInt64, 0},
+		{"9223372036854775808", 0, infounit.ReasonOutOfRange},
+		{"8388608TiB", 0, infounit.ReasonOutOfRange},
+		{"99999999999999999999", 0, infounit.ReasonOutOfRange},
+		{"1PiB", 0, infounit.ReasonUnknownUnit},
+		{"1mib", 0, infounit.ReasonUnknownUnit},
+		{"1MB", 0, infounit.ReasonUnknownUnit},
+		{"1 MiB", 0, infounit.ReasonUnknownUnit},
+		{"1.5GiB", 0, infounit.ReasonUnknownUnit},
+		{"-1", 0, infounit.ReasonNegative},
+		{"MiB", 0, infounit.ReasonBadNumber},
+		{"", 0, infounit.ReasonBadNumber},
+		{"OFF", 0, infounit.ReasonBadNumber},
+	}
+	for _, c := range tc {
+		v, err := infounit.ParseGoMemLimit(c.s)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%q: want: %d, got: %d, %v", c.s, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		if !errors.As(err, &pe) || pe.Reason != c.reason {
+			t.Errorf("%q: %v expected, got: %d, %v", c.s, c.reason, v, err)
+		}
+	}
+	const es = "invalid byte count: 8388608TiB: out of range"
+	if _, err := infounit.ParseGoMemLimit("8388608TiB"); err == nil || err.Error() != es {
+		t.Errorf("want: %q, got: %v", es, err)
+	}
+}
+
+//
+func TestFormatGoMemLimit(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v infounit.ByteCount
+		s string
+	}{
+		{0, "0B"},
+		{100, "100B"},
+		{infounit.Kibibyte, "1KiB"},
+		{infounit.Mebibyte * 1536, "1536MiB"},
+		{infounit.Gibibyte * 2, "2GiB"},
+		{infounit.Pebibyte, "1024TiB"},
+		{infounit.Megabyte, "1000000B"},
+		{math.MaxInt64 - 1, "9223372036854775806B"},
+		{math.MaxInt64, "off"},
+		{math.MaxUint64, "off"},
+	}
+	for _, c := range tc {
+		s := infounit.FormatGoMemLimit(c.v)
+		if s != c.s {
+			t.Errorf("%d: want: %q, got: %q", c.v, c.s, s)
+		}
+		want := c.v
+		if math.MaxInt64 < want {
+			want = math.MaxInt64
+		}
+		if v, err := infounit.ParseGoMemLimit(s); err != nil || v != want {
+			t.Errorf("%d: round trip %q: got: %d, %v", c.v, s, v, err)
+		}
+	}
+}
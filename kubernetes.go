@@ -0,0 +1,302 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// KubernetesFormat specifies the suffix family of the canonical form of the
+// Kubernetes resource quantities.
+type KubernetesFormat int
+
+// Kubernetes quantity formats.
+const (
+	// KubernetesBinarySI uses the binary suffixes, e.g. "512Mi". Values
+	// that are less than 1024 or not whole numbers are formatted in
+	// KubernetesDecimalSI, and values that are not a multiple of 1024 are
+	// formatted without suffix, the same as Kubernetes.
+	KubernetesBinarySI KubernetesFormat = iota
+
+	// KubernetesDecimalSI uses the decimal suffixes, e.g. "1500M", "250m".
+	KubernetesDecimalSI
+
+	// KubernetesDecimalExponent uses the decimal exponents, e.g. "1500e6".
+	KubernetesDecimalExponent
+)
+
+// kubeBinarySuffixes and kubeDecimalSuffixes are the suffixes of the Kubernetes
+// quantities. The decimal suffixes are indexed by the exponent/3+3.
+var (
+	kubeBinarySuffixes  = [7]string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+	kubeDecimalSuffixes = [10]string{"n", "u", "m", "", "k", "M", "G", "T", "P", "E"}
+)
+
+// kubeQuantity is a Kubernetes quantity split into the parts.
+type kubeQuantity struct {
+	neg     bool
+	integer string // the integer digits
+	frac    string // the fractional digits
+	off     int    // offset of the number, for errors
+	exp     int    // the decimal exponent, e.g. 3 for "k", -3 for "m"
+	bin     int    // the binary exponent, e.g. 1 for "Ki", 0 if decimal
+}
+
+// lexKubernetes splits the Kubernetes quantity string, which is
+//
+// 	<sign><digits>.<digits><suffix>
+//
+// where the suffix is a binary SI suffix (Ki, Mi, ..., Ei), a decimal SI suffix
+// (n, u, m, k, M, ..., E) or a decimal exponent (e3, E-6, ...). Spaces are not
+// allowed anywhere.
+func lexKubernetes(lx *lexer) (kubeQuantity, error) {
+	var q kubeQuantity
+	s := lx.s
+	if s == "" {
+		return q, lx.errorf(ReasonBadNumber, 0, "", nil, "no input")
+	}
+	if s[0] == '+' || s[0] == '-' {
+		q.neg = s[0] == '-'
+		lx.pos++
+	}
+	q.off = lx.pos
+	i := digitsEnd(s, lx.pos)
+	q.integer = s[lx.pos:i]
+	if i < len(s) && s[i] == '.' {
+		f := i + 1
+		i = digitsEnd(s, f)
+		q.frac = s[f:i]
+	}
+	if q.integer == "" && q.frac == "" {
+		return q, lx.errorf(ReasonBadNumber, 0, s, nil, "invalid expr: %s", s)
+	}
+	lx.pos = i
+
+	suf := s[i:]
+	if suf == "" {
+		return q, nil
+	}
+	for n := 1; n < len(kubeBinarySuffixes); n++ {
+		if kubeBinarySuffixes[n] == suf {
+			q.bin = n
+			return q, nil
+		}
+	}
+	for n, d := range kubeDecimalSuffixes {
+		if d != "" && d == suf { // "E" is exa, not an exponent
+			q.exp = (n - 3) * 3
+			return q, nil
+		}
+	}
+	if suf[0] == 'e' || suf[0] == 'E' {
+		j := 1
+		if j < len(suf) && (suf[j] == '+' || suf[j] == '-') {
+			j++
+		}
+		if j < len(suf) && digitsEnd(suf, j) == len(suf) {
+			e, err := strconv.Atoi(suf[1:])
+			if err != nil || e < -1000 || 1000 < e {
+				return q, lx.errorf(ReasonOutOfRange, i, suf, err, "exponent out of range: %s", suf)
+			}
+			q.exp = e
+			return q, nil
+		}
+	}
+	return q, lx.errorf(ReasonUnknownUnit, i, suf, nil, "unknown suffix: %s", suf)
+}
+
+// digitsEnd returns the end of the digits starting at s[start].
+func digitsEnd(s string, start int) int {
+	for start < len(s) && '0' <= s[start] && s[start] <= '9' {
+		start++
+	}
+	return start
+}
+
+// shiftDecimal moves the decimal point of the number integer.frac by k digits
+// to the right, or to the left if k is negative. The shift is limited to 40
+// digits, which is enough to make any non-zero value overflow or fractional.
+func shiftDecimal(integer, frac string, k int) (string, string) {
+	const max = 40
+	switch {
+	case max < k:
+		k = max
+	case k < -max:
+		k = -max
+	}
+	digits := integer + frac
+	point := len(integer) + k
+	switch {
+	case point <= 0:
+		return "", strings.Repeat("0", -point) + digits
+	case len(digits) <= point:
+		return digits + strings.Repeat("0", point-len(digits)), ""
+	}
+	return digits[:point], digits[point:]
+}
+
+// ParseKubernetesByteCount converts a Kubernetes resource quantity string,
+// e.g. "512Mi", "1.5G", "2e9" or "100Ki", into a ByteCount value. The
+// returned *ParseError has ReasonFractional if the quantity is not a whole
+// number of bytes, e.g. "1500m" or "0.1Ki", and ReasonNegative if it is
+// negative.
+func ParseKubernetesByteCount(s string) (ByteCount, error) {
+	lx := lexer{s: s, typ: "ByteCount"}
+	q, err := lexKubernetes(&lx)
+	if err != nil {
+		return 0, err
+	}
+	if q.neg && strings.Trim(q.integer+q.frac, "0") != "" {
+		return 0, lx.errorf(ReasonNegative, 0, s, nil, "negative value: %s", s)
+	}
+	integer, frac, unit := q.integer, q.frac, uint64(1)
+	if 0 < q.bin {
+		unit = binPrefix.thresholds[q.bin-1]
+	} else {
+		integer, frac = shiftDecimal(integer, frac, q.exp)
+	}
+	v, err := mulDecimal(integer, frac, unit, RoundExact)
+	if err != nil {
+		return 0, lx.errorf(numErrReason(err), 0, s, err, "%v", err)
+	}
+	return ByteCount(v), nil
+}
+
+// ParseKubernetesBitRate converts a Kubernetes resource quantity string, e.g.
+// "10M", "1Gi", "1500m" or "2.5e6", into a BitRate value in bits per second,
+// as used by the bandwidth annotations. Unlike ParseKubernetesByteCount, the
+// value may be negative or fractional.
+func ParseKubernetesBitRate(s string) (BitRate, error) {
+	lx := lexer{s: s, typ: "BitRate"}
+	q, err := lexKubernetes(&lx)
+	if err != nil {
+		return 0, err
+	}
+	num := s[:q.off+len(q.integer)]
+	if q.frac != "" {
+		num = s[:q.off+len(q.integer)+1+len(q.frac)]
+	}
+	if q.exp != 0 {
+		num += "e" + strconv.Itoa(q.exp)
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, lx.errorf(numErrReason(err), 0, s, err, "invalid expr: %s", s)
+	}
+	if 0 < q.bin {
+		v *= float64(binPrefix.thresholds[q.bin-1])
+	}
+	return BitRate(v), nil
+}
+
+// FormatKubernetesByteCount returns the canonical form of the ByteCount value
+// as a Kubernetes resource quantity with the suffix family specified by f. The
+// returned string is parsed by ParseKubernetesByteCount into the same value.
+//
+// 	f := infounit.FormatKubernetesByteCount
+// 	f(infounit.Mebibyte*1536, infounit.KubernetesBinarySI)       // "1536Mi"
+// 	f(infounit.Megabyte*1500, infounit.KubernetesDecimalSI)      // "1500M"
+// 	f(infounit.Gigabyte*2, infounit.KubernetesDecimalExponent)   // "2e9"
+func FormatKubernetesByteCount(v ByteCount, f KubernetesFormat) string {
+	return string(appendKubernetesUint(make([]byte, 0, 24), uint64(v), f))
+}
+
+// appendKubernetesUint appends the canonical form of the quantity v.
+func appendKubernetesUint(dst []byte, v uint64, f KubernetesFormat) []byte {
+	if f == KubernetesBinarySI && kibi <= v {
+		n := 0
+		for n < len(binPrefix.thresholds) && v%binPrefix.thresholds[n] == 0 {
+			n++
+		}
+		if 0 < n {
+			v /= binPrefix.thresholds[n-1]
+		}
+		dst = strconv.AppendUint(dst, v, 10)
+		return append(dst, kubeBinarySuffixes[n]...)
+	}
+	exp := 0
+	for v != 0 && v%1000 == 0 && exp < 18 {
+		v /= 1000
+		exp += 3
+	}
+	dst = strconv.AppendUint(dst, v, 10)
+	return appendKubernetesExp(dst, exp, f)
+}
+
+// appendKubernetesExp appends the decimal suffix or the exponent for exp, which
+// is a multiple of 3.
+func appendKubernetesExp(dst []byte, exp int, f KubernetesFormat) []byte {
+	switch {
+	case exp == 0:
+		return dst
+	case f == KubernetesDecimalExponent:
+		dst = append(dst, 'e')
+		return strconv.AppendInt(dst, int64(exp), 10)
+	}
+	return append(dst, kubeDecimalSuffixes[exp/3+3]...)
+}
+
+// FormatKubernetesBitRate returns the canonical form of the BitRate value in
+// bits per second as a Kubernetes resource quantity with the suffix family
+// specified by f, e.g. "10M" or "1500m". Like Kubernetes, the digits beyond
+// the nano precision are rounded up, away from zero. ErrOutOfRange is
+// returned for NaN and infinities, which can not be represented.
+func FormatKubernetesBitRate(v BitRate, f KubernetesFormat) (string, error) {
+	x := float64(v)
+	switch {
+	case math.IsNaN(x), math.IsInf(x, 0):
+		return "", ErrOutOfRange
+	case x == 0:
+		return "0", nil
+	}
+	dst := make([]byte, 0, 32)
+	if x < 0 {
+		dst = append(dst, '-')
+		x = -x
+	}
+	if f == KubernetesBinarySI && kibi <= x && x < 0x1p63 && x == math.Trunc(x) {
+		return string(appendKubernetesUint(dst, uint64(x), f)), nil
+	}
+	if f == KubernetesBinarySI {
+		f = KubernetesDecimalSI
+	}
+
+	// x = digits * 10^exp
+	e := strconv.FormatFloat(x, 'e', -1, 64)
+	i := strings.IndexByte(e, 'e')
+	exp, _ := strconv.Atoi(e[i+1:])
+	digits := make([]byte, 0, 24)
+	for _, c := range []byte(e[:i]) {
+		if c != '.' {
+			digits = append(digits, c)
+		}
+	}
+	exp -= len(digits) - 1
+	if exp < -9 { // round up to nano
+		keep := len(digits) + exp + 9
+		if keep < 0 {
+			keep = 0
+		}
+		rest := digits[keep:]
+		digits, exp = digits[:keep], -9
+		if strings.Trim(string(rest), "0") != "" {
+			digits = roundUpDecimal(digits, 0)
+		}
+	}
+
+	// adjust the exponent to a multiple of 3 within the range of suffixes
+	for exp%3 != 0 || 18 < exp {
+		digits = append(digits, '0')
+		exp--
+	}
+	for exp < 18 && 3 < len(digits) && string(digits[len(digits)-3:]) == "000" {
+		digits = digits[:len(digits)-3]
+		exp += 3
+	}
+	dst = append(dst, digits...)
+	return string(appendKubernetesExp(dst, exp, f)), nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestParseKubernetesByteCount(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s      string
+		v      infounit.ByteCount
+		reason infounit.ParseErrorReason
+	}{
+		{"0", 0, 0},
+		{"-0", 0, 0},
+		{"+5", 5, 0},
+		{"512Mi", infounit.Mebibyte * 512, 0},
+		{"100Ki", infounit.Kibibyte * 100, 0},
+		{"1.5Gi", infounit.Mebibyte * 1536, 0},
+		{"1.5G", infounit.Megabyte * 1500, 0},
+		{"1k", infounit.Kilobyte, 0},
+		{"2e9", infounit.Gigabyte * 2, 0},
+		{"2E9", infounit.Gigabyte * 2, 0},
+		{"2.5e+3", 2500, 0},
+		{"25e-1", 0, infounit.ReasonFractional},
+		{"2000e-3", 2, 0},
+		{"2000m", 2, 0},
+		{"1500m", 0, infounit.ReasonFractional},
+		{"0.1Ki", 0, infounit.ReasonFractional},
+		{"3000000000n", 3, 0},
+		{"5.", 5, 0},
+		{".5k", 500, 0},
+		{"16Ei", 0, infounit.ReasonOutOfRange},
+		{"18446744073709551615", math.MaxUint64, 0},
+		{"18446744073709551616", 0, infounit.ReasonOutOfRange},
+		{"1e100", 0, infounit.ReasonOutOfRange},
+		{"0e100", 0, 0},
+		{"1e-100", 0, infounit.ReasonFractional},
+		{"1e99999", 0, infounit.ReasonOutOfRange},
+		{"-1Ki", 0, infounit.ReasonNegative},
+		{"", 0, infounit.ReasonBadNumber},
+		{".", 0, infounit.ReasonBadNumber},
+		{"Mi", 0, infounit.ReasonBadNumber},
+		{"5 Mi", 0, infounit.ReasonUnknownUnit},
+		{"5MiB", 0, infounit.ReasonUnknownUnit},
+		{"5K", 0, infounit.ReasonUnknownUnit},
+		{"5ki", 0, infounit.ReasonUnknownUnit},
+		{"5e", 0, infounit.ReasonUnknownUnit},
+		{"5e3k", 0, infounit.ReasonUnknownUnit},
+		{"18E", infounit.Exabyte * 18, 0},
+		{"1E3", infounit.Kilobyte, 0},
+	}
+	for _, c := range tc {
+		v, err := infounit.ParseKubernetesByteCount(c.s)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%q: want: %d, got: %d, %v", c.s, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		if !errors.As(err, &pe) || pe.Reason != c.reason {
+			t.Errorf("%q: %v expected, got: %d, %v", c.s, c.reason, v, err)
+		}
+	}
+	const es = "invalid byte count: 1500m: fractional value"
+	if _, err := infounit.ParseKubernetesByteCount("1500m"); err == nil || err.Error() != es {
+		t.Errorf("want: %q, got: %v", es, err)
+	}
+}
+
+//
+func TestFormatKubernetesByteCount(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v             infounit.ByteCount
+		bin, dec, exp string
+	}{
+		{0, "0", "0", "0"},
+		{5, "5", "5", "5"},
+		{1000, "1k", "1k", "1e3"},
+		{1023, "1023", "1023", "1023"},
+		{1024, "1Ki", "1024", "1024"},
+		{1500, "1500", "1500", "1500"},
+		{infounit.Mebibyte * 512, "512Mi", "536870912", "536870912"},
+		{infounit.Mebibyte * 1536, "1536Mi", "1610612736", "1610612736"},
+		{infounit.Megabyte * 1500, "1500000000", "1500M", "1500e6"},
+		{infounit.Kibibyte * 3000, "3000Ki", "3072k", "3072e3"},
+		{infounit.Exbibyte * 15, "15Ei", "17293822569102704640", "17293822569102704640"},
+		{infounit.Exabyte * 18, "17578125000000000Ki", "18E", "18e18"},
+		{math.MaxUint64, "18446744073709551615", "18446744073709551615", "18446744073709551615"},
+	}
+	for _, c := range tc {
+		for i, want := range []string{c.bin, c.dec, c.exp} {
+			f := infounit.KubernetesFormat(i)
+			s := infounit.FormatKubernetesByteCount(c.v, f)
+			if s != want {
+				t.Errorf("%d %d: want: %q, got: %q", c.v, f, want, s)
+			}
+			if v, err := infounit.ParseKubernetesByteCount(s); err != nil || v != c.v {
+				t.Errorf("%d %d: round trip %q: got: %d, %v", c.v, f, s, v, err)
+			}
+		}
+	}
+}
+
+//
+func TestParseKubernetesBitRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s  string
+		v  infounit.BitRate
+		ok bool
+	}{
+		{"10M", infounit.MegabitPerSecond * 10, true},
+		{"1Gi", infounit.GibibitPerSecond, true},
+		{"1500m", 1.5, true},
+		{"-2.5e6", infounit.MegabitPerSecond * -2.5, true},
+		{"250u", 0.00025, true},
+		{"1e400", 0, false},
+		{"10 M", 0, false},
+		{"10Mbit", 0, false},
+	}
+	for _, c := range tc {
+		v, err := infounit.ParseKubernetesBitRate(c.s)
+		if (err == nil) != c.ok || v != c.v {
+			t.Errorf("%q: want: %v, %v, got: %v, %v", c.s, c.v, c.ok, v, err)
+		}
+	}
+}
+
+//
+func TestFormatKubernetesBitRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v             infounit.BitRate
+		bin, dec, exp string
+	}{
+		{0, "0", "0", "0"},
+		{1.5, "1500m", "1500m", "1500e-3"},
+		{-1.5, "-1500m", "-1500m", "-1500e-3"},
+		{infounit.MegabitPerSecond * 10, "10000000", "10M", "10e6"},
+		{infounit.GibibitPerSecond, "1Gi", "1073741824", "1073741824"},
+		{infounit.MegabitPerSecond * 2.5, "2500000", "2500k", "2500e3"},
+		{0.00025, "250u", "250u", "250e-6"},
+		{1e-12, "1n", "1n", "1e-9"},
+		{1.0000000001, "1000000001n", "1000000001n", "1000000001e-9"},
+		{1e21, "1000E", "1000E", "1000e18"},
+		{123.456, "123456m", "123456m", "123456e-3"},
+	}
+	for _, c := range tc {
+		for i, want := range []string{c.bin, c.dec, c.exp} {
+			f := infounit.KubernetesFormat(i)
+			s, err := infounit.FormatKubernetesBitRate(c.v, f)
+			if err != nil || s != want {
+				t.Errorf("%v %d: want: %q, got: %q, %v", c.v, f, want, s, err)
+			}
+		}
+	}
+	for _, v := range []infounit.BitRate{infounit.BitRate(math.NaN()), infounit.BitRate(math.Inf(1))} {
+		if _, err := infounit.FormatKubernetesBitRate(v, infounit.KubernetesDecimalSI); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf("%v: ErrOutOfRange expected, got: %v", v, err)
+		}
+	}
+}
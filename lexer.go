@@ -0,0 +1,553 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// lexer reads the space-separated tokens of human-readable representations
+// either from a fmt.ScanState, or directly from a string. The latter is used by
+// the Parse functions, which behave the same as fmt.Sscanf with the %s verb but
+// do not allocate.
+type lexer struct {
+	state  fmt.ScanState // nil to read from s
+	s      string
+	pos    int
+	last   int    // size of the last rune read, for unreadRune
+	read   []byte // text read from state, for errors
+	typ    string // name of the target type, for errors; e.g. "ByteCount"
+	signed bool   // the sign has already been read by scanInt
+}
+
+// offset returns the byte offset of the next character to read.
+func (lx *lexer) offset() int {
+	if lx.state != nil {
+		return len(lx.read)
+	}
+	return lx.pos
+}
+
+// input returns the input for errors. When reading from a fmt.ScanState, it is
+// the part read so far.
+func (lx *lexer) input() string {
+	if lx.state != nil {
+		return string(lx.read)
+	}
+	return lx.s
+}
+
+// errorf returns a *ParseError for the token at the byte offset off. The errors
+// for strings are prefixed with the type and the input, like
+// "invalid byte count: 5 foo: %s: unknown unit: foo", while the errors for
+// fmt.ScanState are not, since fmt does not know the input either.
+func (lx *lexer) errorf(reason ParseErrorReason, off int, tok string, err error, format string, a ...interface{}) error {
+	return &ParseError{
+		Input:  lx.input(),
+		Offset: off,
+		Token:  tok,
+		Type:   lx.typ,
+		Reason: reason,
+		Err:    err,
+		msg:    fmt.Sprintf(format, a...),
+		prefix: lx.state == nil,
+	}
+}
+
+// skipSpace skips the spaces before the value. Like fmt.Sscanf, newlines are
+// not treated as spaces.
+func (lx *lexer) skipSpace() error {
+	if lx.state != nil {
+		lx.state.SkipSpace()
+		return nil
+	}
+	for lx.pos < len(lx.s) {
+		r, n := utf8.DecodeRuneInString(lx.s[lx.pos:])
+		switch {
+		case r == '\n':
+			return lx.errorf(ReasonBadNumber, lx.pos, "\n", nil, "unexpected newline")
+		case !unicode.IsSpace(r):
+			return nil
+		}
+		lx.pos += n
+	}
+	return nil
+}
+
+// token returns the next token, a sequence of non-space characters. If
+// skipSpace is true, the spaces before the token are skipped.
+func (lx *lexer) token(skipSpace bool) (string, error) {
+	lx.last = 0
+	if lx.state != nil {
+		tok, err := lx.state.Token(skipSpace, nil)
+		lx.read = append(lx.read, tok...)
+		return string(tok), err
+	}
+	if skipSpace {
+		if err := lx.skipSpace(); err != nil {
+			return "", err
+		}
+	}
+	start := lx.pos
+	for lx.pos < len(lx.s) {
+		r, n := utf8.DecodeRuneInString(lx.s[lx.pos:])
+		if unicode.IsSpace(r) {
+			break
+		}
+		lx.pos += n
+	}
+	return lx.s[start:lx.pos], nil
+}
+
+// readRune reads the next rune.
+func (lx *lexer) readRune() (rune, int, error) {
+	lx.last = 0
+	if lx.state != nil {
+		r, n, err := lx.state.ReadRune()
+		if err == nil {
+			size := len(lx.read)
+			lx.read = append(lx.read, string(r)...)
+			lx.last = len(lx.read) - size
+		}
+		return r, n, err
+	}
+	if len(lx.s) <= lx.pos {
+		return 0, 0, io.EOF
+	}
+	r, n := utf8.DecodeRuneInString(lx.s[lx.pos:])
+	lx.pos += n
+	lx.last = n
+	return r, n, nil
+}
+
+// unreadRune unreads the rune returned by the last readRune.
+func (lx *lexer) unreadRune() {
+	if lx.state != nil {
+		_ = lx.state.UnreadRune()
+		lx.read = lx.read[:len(lx.read)-lx.last]
+	} else {
+		lx.pos -= lx.last
+	}
+	lx.last = 0
+}
+
+// scanSign reads the optional sign, '+' or '-', preceding a signed value, and
+// returns true if it is '-'. No space is allowed between the sign and the
+// digits.
+func (lx *lexer) scanSign(verb rune) (bool, error) {
+	if err := lx.skipSpace(); err != nil {
+		return false, err
+	}
+	r, _, err := lx.readRune()
+	if err != nil {
+		return false, nil // reported by the following scan as no input
+	}
+	neg := false
+	switch r {
+	case '-':
+		neg = true
+	case '+':
+	default:
+		lx.unreadRune()
+		return false, nil
+	}
+	if next, _, err := lx.readRune(); err == nil {
+		lx.unreadRune()
+		if unicode.IsSpace(next) {
+			off := lx.offset() - 1
+			return false, lx.errorf(ReasonBadNumber, off, string(r), nil, "%%%c: space after sign: %c", verb, r)
+		}
+	}
+	return neg, nil
+}
+
+// scanUnit is an entry of the unit tables, which maps a unit name to the
+// prefix. The names are matched case-insensitively.
+type scanUnit struct {
+	name string // in lower case; e.g. "kb", "kilobytes"
+	exp  int    // index of the prefix, or -1 for no prefix
+	bin  bool   // always a binary prefix; e.g. "kib"
+	long bool   // long unit name; e.g. "kilobytes", "bits"
+}
+
+// lookupUnit returns the value of the unit named s in the table. If binary is
+// true, the SI prefixes are treated as binary prefixes.
+func lookupUnit(units []scanUnit, s string, binary bool) (*scanUnit, uint64, bool) {
+	for i := range units {
+		u := &units[i]
+		if !equalUnitName(u.name, s) {
+			continue
+		}
+		return u, u.value(binary), true
+	}
+	return nil, 0, false
+}
+
+// value returns the value of the unit. If binary is true, the SI prefixes are
+// treated as binary prefixes.
+func (u *scanUnit) value(binary bool) uint64 {
+	switch {
+	case u.exp < 0:
+		return 1
+	case u.bin || binary:
+		return binPrefix.thresholds[u.exp]
+	}
+	return siPrefix.thresholds[u.exp]
+}
+
+// equalUnitName reports whether s equals the unit name, ignoring the case of
+// ASCII letters except the upper case letters in name, which must match
+// exactly. This distinguishes "MB/s" from "Mb/s", which usually means megabits
+// per second.
+func equalUnitName(name, s string) bool {
+	if len(name) != len(s) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' && (name[i] < 'A' || 'Z' < name[i]) {
+			c += 'a' - 'A'
+		}
+		if c != name[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// equalFoldASCII reports whether s equals lower, which is in lower case,
+// ignoring the case of ASCII letters.
+func equalFoldASCII(lower, s string) bool {
+	if len(lower) != len(s) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c != lower[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPrefixFoldASCII is the case-insensitive version of strings.HasPrefix for
+// the prefix in lower case.
+func hasPrefixFoldASCII(s, lower string) bool {
+	return len(lower) <= len(s) && equalFoldASCII(lower, s[:len(lower)])
+}
+
+// numToken is the first token of a human-readable representation, split into
+// the number and the unit suffix.
+type numToken struct {
+	num     string // the whole number; e.g. "-12.5", "NaN"
+	integer string // the sign and the integer digits; e.g. "-12"
+	frac    string // the fractional digits without the decimal point; e.g. "5"
+	unit    string // the unit suffix if any; e.g. "kB"
+}
+
+// isUnitChar returns whether c can be a part of a unit suffix. The rates may
+// contain '/', e.g. "kbit/s".
+func isUnitChar(c byte, rate bool) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || rate && c == '/'
+}
+
+// isUnitExpr returns whether s consists only of the characters that can be a
+// part of a unit suffix.
+func isUnitExpr(s string, rate bool) bool {
+	for i := 0; i < len(s); i++ {
+		if !isUnitChar(s[i], rate) {
+			return false
+		}
+	}
+	return true
+}
+
+// lexNumber splits the token into a decimal number and the following unit
+// suffix. If rate is true, the number may have a sign or be one of the special
+// values "NaN", "+Inf" and "-Inf", and the unit suffix may contain '/'. The
+// number may be empty, which is reported by the caller.
+func lexNumber(tok string, rate bool) (numToken, bool) {
+	var t numToken
+	if rate {
+		n := 0
+		switch {
+		case hasPrefixFoldASCII(tok, "nan"):
+			n = 3
+		case hasPrefixFoldASCII(tok, "+inf"), hasPrefixFoldASCII(tok, "-inf"):
+			n = 4
+		}
+		if 0 < n {
+			t.num, t.unit = tok[:n], tok[n:]
+			return t, isUnitExpr(t.unit, rate)
+		}
+	}
+	i := 0
+	if rate && i < len(tok) && (tok[i] == '+' || tok[i] == '-') {
+		i++
+	}
+	for i < len(tok) && '0' <= tok[i] && tok[i] <= '9' {
+		i++
+	}
+	t.integer = tok[:i]
+	if i+1 < len(tok) && tok[i] == '.' && '0' <= tok[i+1] && tok[i+1] <= '9' {
+		f := i + 1
+		for i = f; i < len(tok) && '0' <= tok[i] && tok[i] <= '9'; i++ {
+		}
+		t.frac = tok[f:i]
+	}
+	t.num, t.unit = tok[:i], tok[i:]
+	return t, isUnitExpr(t.unit, rate)
+}
+
+// scanNumToken reads the first token and splits it into the number and the
+// unit suffix. It also returns the byte offset of the token.
+func (lx *lexer) scanNumToken(verb rune, rate bool) (numToken, int, error) {
+	tok, err := lx.token(true)
+	off := lx.offset() - len(tok)
+	switch {
+	case err != nil:
+		return numToken{}, off, lx.errorf(ReasonBadNumber, off, "", err, "%%%c: %v", verb, err)
+	case len(tok) < 1:
+		return numToken{}, off, lx.errorf(ReasonBadNumber, off, "", nil, "%%%c: no input", verb)
+	}
+	t, ok := lexNumber(tok, rate)
+	if !ok || t.num == "" {
+		reason := ReasonBadNumber
+		if !rate && !lx.signed && tok[0] == '-' {
+			if t, _ := lexNumber(tok[1:], false); t.num != "" {
+				reason = ReasonNegative // e.g. "-5 B"
+			}
+		}
+		return numToken{}, off, lx.errorf(reason, off, tok, nil, "%%%c: invalid expr: %s", verb, tok)
+	}
+	return t, off, nil
+}
+
+// numErrReason returns the reason for the error returned by the conversion of
+// the digits into a value.
+func numErrReason(err error) ParseErrorReason {
+	switch {
+	case errors.Is(err, ErrOutOfRange), errors.Is(err, strconv.ErrRange):
+		return ReasonOutOfRange
+	case errors.Is(err, ErrFractional):
+		return ReasonFractional
+	}
+	return ReasonBadNumber
+}
+
+// scanUint scans a ByteCount or BitCount value with the verb %s, %S, %u or %U.
+// units is the table of the unit suffixes, the first entry of which is the unit
+// without prefix, and what is the name of the value in error messages.
+func (lx *lexer) scanUint(verb rune, mode RoundingMode, units []scanUnit, what string) (uint64, error) {
+	t, off, err := lx.scanNumToken(verb, false)
+	if err != nil {
+		return 0, err
+	}
+	isInt := t.integer != "" && t.frac == ""
+	binary := verb == 'S' || verb == 'U'
+
+	unitOff := off + len(t.num)
+	if t.unit == "" { // no unit suffix within the first token
+		switch verb {
+		case 'u', 'U':
+			// does not read the second token, assumed to be the unit
+			// without prefix
+			t.unit = units[0].name
+		case 's', 'S':
+			if t.unit, err = lx.scanUnitToken(verb, false); err != nil {
+				return 0, err
+			}
+			unitOff = lx.offset() - len(t.unit)
+		}
+	}
+
+	u, mul, ok := lookupUnit(units, t.unit, binary)
+	switch {
+	case !ok:
+		return 0, lx.errorf(ReasonUnknownUnit, unitOff, t.unit, nil, "%%%c: unknown unit: %s", verb, t.unit)
+	case u.exp < 0:
+		if !isInt {
+			return 0, lx.errorf(ReasonBadNumber, off, t.num, nil, "%%%c: non-integer %s: %s", verb, what, t.num)
+		}
+		v, err := parseUint64(t.num)
+		if err != nil {
+			return 0, lx.errorf(numErrReason(err), off, t.num, err, "%%%c: invalid %s: %s: %v", verb, what, t.num, err)
+		}
+		return v, nil
+	}
+	v, err := mulDecimal(t.integer, t.frac, mul, mode)
+	if err != nil {
+		expr := lx.input()[off : unitOff+len(t.unit)] // as written; e.g. "20EB"
+		return 0, lx.errorf(numErrReason(err), off, t.num, err, "%%%c: %s: %v", verb, expr, err)
+	}
+	return v, nil
+}
+
+// scanFloat scans a BitRate or ByteRate value with the verb %s, %S, %u or %U.
+// units is the table of the unit suffixes consisting of one token, e.g.
+// "kbit/s", and units3 is the table of the first word of the unit suffixes
+// consisting of three tokens, e.g. "kilobits per second".
+func (lx *lexer) scanFloat(verb rune, units, units3 []scanUnit) (float64, error) {
+	t, off, err := lx.scanNumToken(verb, true)
+	if err != nil {
+		return 0, err
+	}
+	binary := verb == 'S' || verb == 'U'
+
+	unitOff := off + len(t.num)
+	if t.unit == "" { // no unit suffix within the first token
+		switch verb {
+		case 'u', 'U':
+			// does not read the second token, assumed to be the unit
+			// without prefix
+			t.unit = units[0].name
+		case 's', 'S':
+			if t.unit, err = lx.scanUnitToken(verb, true); err != nil {
+				return 0, err
+			}
+			unitOff = lx.offset() - len(t.unit)
+		}
+	}
+
+	v, err := strconv.ParseFloat(t.num, 64)
+	if err != nil {
+		return 0, lx.errorf(numErrReason(err), off, t.num, err, "%%%c: invalid expr: %s", verb, t.num)
+	}
+	if _, mul, ok := lookupUnit(units, t.unit, binary); ok {
+		return v * float64(mul), nil
+	}
+
+	// try 3 tokens units
+	// 12.3kilobits per second
+	// 12.3 kilobits per second
+	// suf is the suffix read so far for error messages, which is sliced
+	// from s without allocation when reading from a string.
+	suf := t.unit
+	unknown := func(err error, format string, a ...interface{}) error {
+		return lx.errorf(ReasonUnknownUnit, unitOff, suf, err, format, a...)
+	}
+	for i := 0; i < 2; i++ {
+		sp, n, err := lx.readRune() // read only one space
+		switch {
+		case errors.Is(err, io.EOF):
+			return 0, unknown(err, "%%%c: unknown unit: %s", verb, suf)
+		case err != nil:
+			return 0, unknown(err, "%%%c: invalid unit suffix: %s: %v", verb, suf, err)
+		case n != 1:
+			return 0, unknown(nil, "%%%c: unknown unit: %s", verb, suf)
+		case sp != ' ':
+			return 0, unknown(nil, "%%%c: unknown unit: %s%c", verb, suf, sp)
+		}
+		tok, err := lx.token(false)
+		switch {
+		case errors.Is(err, io.EOF):
+			return 0, unknown(err, "%%%c: unknown unit: %s", verb, suf)
+		case err != nil:
+			return 0, unknown(err, "%%%c: invalid unit suffix: %s: %v", verb, suf, err)
+		case len(tok) < 1:
+			return 0, unknown(nil, "%%%c: unknown unit: %s", verb, suf)
+		case i == 0 && !equalFoldASCII("per", tok),
+			i == 1 && !equalFoldASCII("sec", tok) && !equalFoldASCII("second", tok):
+			return 0, unknown(nil, "%%%c: unknown unit: %s %s", verb, suf, tok)
+		}
+		if lx.state != nil {
+			suf += " " + tok
+		} else {
+			suf = lx.s[unitOff:lx.pos]
+		}
+	}
+	if _, mul, ok := lookupUnit(units3, t.unit, binary); ok {
+		return v * float64(mul), nil
+	}
+	return 0, unknown(nil, "%%%c: unknown unit: %s", verb, suf)
+}
+
+// scanUnitToken reads the unit suffix following the digits and a space. This
+// is used by the verbs %s and %S when the first token consists only of digits.
+func (lx *lexer) scanUnitToken(verb rune, rate bool) (string, error) {
+	off := lx.offset()
+	sp, n, err := lx.readRune() // read only one space
+	switch {
+	case err != nil:
+		return "", lx.errorf(ReasonMissingUnit, off, "", err, "%%%c: no unit suffix: %v", verb, err)
+	case n != 1:
+		return "", lx.errorf(ReasonMissingUnit, off, string(sp), nil, "%%%c: no unit suffix", verb)
+	case sp != ' ':
+		return "", lx.errorf(ReasonMissingUnit, off, string(sp), nil, "%%%c: no space after digits: [%c]", verb, sp)
+	}
+	tok, err := lx.token(false)
+	off = lx.offset() - len(tok)
+	switch {
+	case err != nil:
+		return "", lx.errorf(ReasonMissingUnit, off, "", err, "%%%c: no unit suffix: %v", verb, err)
+	case len(tok) < 1:
+		return "", lx.errorf(ReasonMissingUnit, off, "", nil, "%%%c: no unit suffix", verb)
+	case !isUnitExpr(tok, rate):
+		return "", lx.errorf(ReasonUnknownUnit, off, tok, nil, "%%%c: invalid unit expr: %s", verb, tok)
+	}
+	return tok, nil
+}
+
+// scanInt scans a ByteDelta or BitDelta value, the magnitude of which is
+// scanned by scanUint after the optional sign.
+func (lx *lexer) scanInt(verb rune, mode RoundingMode, units []scanUnit, what string) (int64, error) {
+	neg, err := lx.scanSign(verb)
+	if err != nil {
+		return 0, err
+	}
+	start := lx.offset()
+	if neg {
+		start--
+	}
+	lx.signed = true
+	mag, err := lx.scanUint(verb, mode, units, what)
+	if err != nil {
+		return 0, err
+	}
+	v, err := signInt64(mag, neg)
+	if err != nil {
+		in := lx.input()
+		return 0, lx.errorf(ReasonOutOfRange, start, in[start:lx.offset()], err, "%%%c: %v", verb, err)
+	}
+	return v, nil
+}
+
+// parseUint is the same as scanning s with fmt.Sscanf and the verb into a
+// ByteCount or BitCount value, except that it does not allocate unless an error
+// is returned. The spaces before the value are skipped, and the characters
+// after the value are ignored. typ is the name of the type for errors.
+func parseUint(s, typ string, verb rune, mode RoundingMode, units []scanUnit, what string) (uint64, error) {
+	lx := lexer{s: s, typ: typ}
+	if err := lx.skipSpace(); err != nil {
+		return 0, err
+	}
+	return lx.scanUint(verb, mode, units, what)
+}
+
+// parseInt is the same as parseUint, but for ByteDelta and BitDelta.
+func parseInt(s, typ string, verb rune, mode RoundingMode, units []scanUnit, what string) (int64, error) {
+	lx := lexer{s: s, typ: typ}
+	if err := lx.skipSpace(); err != nil {
+		return 0, err
+	}
+	return lx.scanInt(verb, mode, units, what)
+}
+
+// parseFloat is the same as parseUint, but for BitRate and ByteRate.
+func parseFloat(s, typ string, verb rune, units, units3 []scanUnit) (float64, error) {
+	lx := lexer{s: s, typ: typ}
+	if err := lx.skipSpace(); err != nil {
+		return 0, err
+	}
+	return lx.scanFloat(verb, units, units3)
+}
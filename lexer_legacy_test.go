@@ -0,0 +1,487 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+// This file holds the regexp-based implementation of Scan, which was replaced
+// by the lexer, to test the compatibility and compare the performance.
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//
+type legacyByteCountUnitEnt struct {
+	re  *regexp.Regexp
+	bcs uint64
+	bcb uint64
+}
+
+var (
+	legacyByteCountTokenRe []*regexp.Regexp
+	legacyByteCountUnitRe  []legacyByteCountUnitEnt
+)
+
+//
+func init() {
+	ent := func(s string, bcs, bcb ByteCount) legacyByteCountUnitEnt {
+		return legacyByteCountUnitEnt{
+			re:  regexp.MustCompile(`(?i)^` + s + `$`),
+			bcs: uint64(bcs),
+			bcb: uint64(bcb),
+		}
+	}
+	legacyByteCountUnitRe = []legacyByteCountUnitEnt{
+		ent("b(ytes?)?", Byte, Byte),
+		ent("kb|kilobytes?", Kilobyte, Kibibyte),
+		ent("mb|megabytes?", Megabyte, Mebibyte),
+		ent("gb|gigabytes?", Gigabyte, Gibibyte),
+		ent("tb|terabytes?", Terabyte, Tebibyte),
+		ent("pb|petabytes?", Petabyte, Pebibyte),
+		ent("eb|exabytes?", Exabyte, Exbibyte),
+		ent("kib|kibibytes?", Kibibyte, Kibibyte),
+		ent("mib|mebibytes?", Mebibyte, Mebibyte),
+		ent("gib|gibibytes?", Gibibyte, Gibibyte),
+		ent("tib|tebibytes?", Tebibyte, Tebibyte),
+		ent("pib|pebibytes?", Pebibyte, Pebibyte),
+		ent("eib|exbibytes?", Exbibyte, Exbibyte),
+	}
+	legacyByteCountTokenRe = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^(([0-9]*)(\.[0-9]+)?)([a-z]*)$`), // 1:num, 2:int, 3:frac, 4:unit
+		regexp.MustCompile(`(?i)^([a-z]*)$`),                      // 1:unit
+	}
+}
+
+// legacyByteCount is a ByteCount scanned by the regexp-based implementation.
+type legacyByteCount ByteCount
+
+func (bc *legacyByteCount) Scan(state fmt.ScanState, verb rune) error {
+	mode := RoundNearest
+
+	switch verb {
+	case 's', 'S', 'u', 'U':
+		token1Bytes, err := state.Token(true, nil)
+		switch {
+		case err != nil:
+			return fmt.Errorf("%%%c: %w", verb, err)
+		case len(token1Bytes) < 1:
+			return fmt.Errorf("%%%c: no input", verb)
+		}
+		token1Str := string(token1Bytes)
+		token1 := legacyByteCountTokenRe[0].FindStringSubmatch(token1Str)
+		if token1 == nil {
+			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
+		}
+
+		numExpr := token1[1]
+		isInt := 0 < len(token1[2]) && len(token1[3]) < 1
+		unitExpr := token1[4]
+
+		if len(numExpr) < 1 {
+			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
+		}
+
+		if unitExpr == "" { // no unit suffix within the first token
+			switch verb {
+			case 'u', 'U':
+				// does not read the second token, assumed to be bytes
+				unitExpr = "b"
+			case 's', 'S':
+				sp, n, err := state.ReadRune()
+				if err != nil {
+					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
+				}
+				if n != 1 {
+					return fmt.Errorf("%%%c: no unit suffix", verb)
+				}
+				if sp != ' ' {
+					return fmt.Errorf("%%%c: no space after digits: [%c]", verb, sp)
+				}
+				token2Bytes, err := state.Token(false, nil)
+				if err != nil {
+					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
+				}
+				if len(token2Bytes) < 1 {
+					return fmt.Errorf("%%%c: no unit suffix", verb)
+				}
+				token2Str := string(token2Bytes)
+				token2 := legacyByteCountTokenRe[1].FindStringSubmatch(token2Str)
+				if token2 == nil {
+					return fmt.Errorf("%%%c: invalid unit expr: %s", verb, token2Str)
+				}
+
+				unitExpr = token2[1]
+				if unitExpr == "" {
+					return fmt.Errorf("%%%c: no unit suffix", verb)
+				}
+			}
+		}
+
+		ptr := (*uint64)(bc)
+
+		// unit is byte
+		if legacyByteCountUnitRe[0].re.MatchString(unitExpr) {
+			if !isInt {
+				return fmt.Errorf("%%%c: non-integer byte count: %s", verb, numExpr)
+			}
+			numVal, err := parseUint64(numExpr)
+			if err != nil {
+				return fmt.Errorf("%%%c: invalid byte count: %s: %w", verb, numExpr, err)
+			}
+			*ptr = numVal
+			return nil
+		}
+
+		for _, unit := range legacyByteCountUnitRe {
+			if unit.re.MatchString(unitExpr) {
+				mul := unit.bcs
+				if verb == 'S' || verb == 'U' {
+					mul = unit.bcb
+				}
+				v, err := mulDecimal(token1[2], strings.TrimPrefix(token1[3], "."), mul, mode)
+				if err != nil {
+					return fmt.Errorf("%%%c: %s %s: %w", verb, numExpr, unitExpr, err)
+				}
+				*ptr = v
+				return nil
+			}
+		}
+		return fmt.Errorf("%%%c: unknown unit: %s", verb, unitExpr)
+
+	default:
+		return fmt.Errorf("unknown verb for ByteCount: %%%c", verb)
+	}
+}
+
+//
+type legacyBitCountUnitEnt struct {
+	re  *regexp.Regexp
+	bcs uint64
+	bcb uint64
+}
+
+var (
+	legacyBitCountTokenRe []*regexp.Regexp
+	legacyBitCountUnitRe  []legacyBitCountUnitEnt
+)
+
+//
+func init() {
+	ent := func(s string, bcs, bcb BitCount) legacyBitCountUnitEnt {
+		return legacyBitCountUnitEnt{
+			re:  regexp.MustCompile(`(?i)^` + s + `$`),
+			bcs: uint64(bcs),
+			bcb: uint64(bcb),
+		}
+	}
+	legacyBitCountUnitRe = []legacyBitCountUnitEnt{
+		ent("bits?", Bit, Bit),
+		ent("k(ilo)?bits?", Kilobit, Kibibit),
+		ent("m(ega)?bits?", Megabit, Mebibit),
+		ent("g(iga)?bits?", Gigabit, Gibibit),
+		ent("t(era)?bits?", Terabit, Tebibit),
+		ent("p(eta)?bits?", Petabit, Pebibit),
+		ent("e(xa)?bits?", Exabit, Exbibit),
+		ent("(ki|kibi)bits?", Kibibit, Kibibit),
+		ent("(mi|mebi)bits?", Mebibit, Mebibit),
+		ent("(gi|gibi)bits?", Gibibit, Gibibit),
+		ent("(ti|tebi)bits?", Tebibit, Tebibit),
+		ent("(pi|pebi)bits?", Pebibit, Pebibit),
+		ent("(ei|exbi)bits?", Exbibit, Exbibit),
+	}
+	legacyBitCountTokenRe = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^(([0-9]*)(\.[0-9]+)?)([a-z]*)$`), // 1:num, 2:int, 3:frac, 4:unit
+		regexp.MustCompile(`(?i)^([a-z]*)$`),                      // 1:unit
+	}
+}
+
+// legacyBitCount is a BitCount scanned by the regexp-based implementation.
+type legacyBitCount BitCount
+
+func (bc *legacyBitCount) Scan(state fmt.ScanState, verb rune) error {
+	mode := RoundNearest
+
+	switch verb {
+	case 's', 'S', 'u', 'U':
+		token1Bytes, err := state.Token(true, nil)
+		switch {
+		case err != nil:
+			return fmt.Errorf("%%%c: %w", verb, err)
+		case len(token1Bytes) < 1:
+			return fmt.Errorf("%%%c: no input", verb)
+		}
+		token1Str := string(token1Bytes)
+		token1 := legacyBitCountTokenRe[0].FindStringSubmatch(token1Str)
+		if token1 == nil {
+			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
+		}
+
+		numExpr, unitExpr := token1[1], token1[4]
+		isInt := 0 < len(token1[2]) && len(token1[3]) < 1
+
+		if len(numExpr) < 1 {
+			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
+		}
+
+		if unitExpr == "" { // no unit suffix within the first token
+			switch verb {
+			case 'u', 'U':
+				// does not read the second token, assumed to be bits
+				unitExpr = "bit"
+			case 's', 'S':
+				sp, n, err := state.ReadRune()
+				switch {
+				case err != nil:
+					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
+				case n != 1:
+					return fmt.Errorf("%%%c: no unit suffix", verb)
+				case sp != ' ':
+					return fmt.Errorf("%%%c: no space after digits: [%c]", verb, sp)
+				}
+				token2Bytes, err := state.Token(false, nil)
+				switch {
+				case err != nil:
+					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
+				case len(token2Bytes) < 1:
+					return fmt.Errorf("%%%c: no unit suffix", verb)
+				}
+				token2Str := string(token2Bytes)
+				token2 := legacyBitCountTokenRe[1].FindStringSubmatch(token2Str)
+				if token2 == nil {
+					return fmt.Errorf("%%%c: invalid unit expr: %s", verb, token2Str)
+				}
+
+				unitExpr = token2[1]
+				if unitExpr == "" {
+					return fmt.Errorf("%%%c: no unit suffix", verb)
+				}
+			}
+		}
+
+		ptr := (*uint64)(bc)
+
+		// unit is bit
+		if legacyBitCountUnitRe[0].re.MatchString(unitExpr) {
+			if !isInt {
+				return fmt.Errorf("%%%c: non-integer bit count: %s", verb, numExpr)
+			}
+			numVal, err := parseUint64(numExpr)
+			if err != nil {
+				return fmt.Errorf("%%%c: invalid bit count: %s: %w", verb, numExpr, err)
+			}
+			*ptr = numVal
+			return nil
+		}
+
+		for _, unit := range legacyBitCountUnitRe {
+			if unit.re.MatchString(unitExpr) {
+				mul := unit.bcs
+				if verb == 'S' || verb == 'U' {
+					mul = unit.bcb
+				}
+				v, err := mulDecimal(token1[2], strings.TrimPrefix(token1[3], "."), mul, mode)
+				if err != nil {
+					return fmt.Errorf("%%%c: %s %s: %w", verb, numExpr, unitExpr, err)
+				}
+				*ptr = v
+				return nil
+			}
+		}
+		return fmt.Errorf("%%%c: unknown unit: %s", verb, unitExpr)
+
+	default:
+		return fmt.Errorf("unknown verb for BitCount: %%%c", verb)
+	}
+}
+
+//
+type legacyBitRateUnitEnt struct {
+	re  *regexp.Regexp
+	brs float64
+	brb float64
+}
+
+var (
+	legacyBitRateTokenRe []*regexp.Regexp
+	legacyBitRateUnitRe  []legacyBitRateUnitEnt
+	legacyBitRateUnit3Re []legacyBitRateUnitEnt // 3 tokens unit suffix, e.g. "kilobits per second"
+)
+
+//
+func init() {
+	legacyBitRateTokenRe = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^(nan|[+-]inf|([+-]?[0-9]*)(\.[0-9]+)?)([a-z/]*)$`), // 1:num, 2:int, 3:frac, 4:unit
+		regexp.MustCompile(`(?i)^([a-z/]+)$`),                                       // 1:unit
+		regexp.MustCompile(`(?i)^per$`),
+		regexp.MustCompile(`(?i)^sec(ond)?$`),
+	}
+	ent := func(s string, brs, brb BitRate) legacyBitRateUnitEnt {
+		return legacyBitRateUnitEnt{
+			re:  regexp.MustCompile(`(?i)^` + s + `$`),
+			brs: float64(brs),
+			brb: float64(brb),
+		}
+	}
+	st := "(bps|bit/s)"
+	legacyBitRateUnitRe = []legacyBitRateUnitEnt{
+		ent(st, BitPerSecond, BitPerSecond),
+		ent("k"+st, KilobitPerSecond, KibibitPerSecond),
+		ent("m"+st, MegabitPerSecond, MebibitPerSecond),
+		ent("g"+st, GigabitPerSecond, GibibitPerSecond),
+		ent("t"+st, TerabitPerSecond, TebibitPerSecond),
+		ent("p"+st, PetabitPerSecond, PebibitPerSecond),
+		ent("e"+st, ExabitPerSecond, ExbibitPerSecond),
+		ent("ki"+st, KibibitPerSecond, KibibitPerSecond),
+		ent("mi"+st, MebibitPerSecond, MebibitPerSecond),
+		ent("gi"+st, GibibitPerSecond, GibibitPerSecond),
+		ent("ti"+st, TebibitPerSecond, TebibitPerSecond),
+		ent("pi"+st, PebibitPerSecond, PebibitPerSecond),
+		ent("ei"+st, ExbibitPerSecond, ExbibitPerSecond),
+	}
+	legacyBitRateUnit3Re = []legacyBitRateUnitEnt{
+		ent("bits?", BitPerSecond, BitPerSecond),
+		ent("k(ilo)?bits?", KilobitPerSecond, KibibitPerSecond),
+		ent("m(ega)?bits?", MegabitPerSecond, MebibitPerSecond),
+		ent("g(iga)?bits?", GigabitPerSecond, GibibitPerSecond),
+		ent("t(era)?bits?", TerabitPerSecond, TebibitPerSecond),
+		ent("p(eta)?bits?", PetabitPerSecond, PebibitPerSecond),
+		ent("e(xa)?bits?", ExabitPerSecond, ExbibitPerSecond),
+		ent("(ki|kibi)bits?", KibibitPerSecond, KibibitPerSecond),
+		ent("(mi|mebi)bits?", MebibitPerSecond, MebibitPerSecond),
+		ent("(gi|gibi)bits?", GibibitPerSecond, GibibitPerSecond),
+		ent("(ti|tebi)bits?", TebibitPerSecond, TebibitPerSecond),
+		ent("(pi|pebi)bits?", PebibitPerSecond, PebibitPerSecond),
+		ent("(ei|exbi)bits?", ExbibitPerSecond, ExbibitPerSecond),
+	}
+}
+
+// legacyBitRate is a BitRate scanned by the regexp-based implementation.
+type legacyBitRate BitRate
+
+func (br *legacyBitRate) Scan(state fmt.ScanState, verb rune) error {
+	switch verb {
+	case 's', 'S', 'u', 'U':
+		token1Bytes, err := state.Token(true, nil)
+		switch {
+		case err != nil:
+			return fmt.Errorf("%%%c: %w", verb, err)
+		case len(token1Bytes) < 1:
+			return fmt.Errorf("%%%c: no input", verb)
+		}
+		token1Str := string(token1Bytes)
+		token1 := legacyBitRateTokenRe[0].FindStringSubmatch(token1Str)
+		if token1 == nil {
+			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
+		}
+
+		numExpr, unitExpr := token1[1], token1[4]
+
+		if len(numExpr) < 1 {
+			return fmt.Errorf("%%%c: invalid expr: %s", verb, token1Str)
+		}
+		if unitExpr == "" { // no unit suffix within the first token
+			switch verb {
+			case 'u', 'U':
+				// does not read the second token, assumed to be bit/s
+				unitExpr = "bit/s"
+			case 's', 'S':
+				sp, n, err := state.ReadRune() // read only one space
+				switch {
+				case err != nil:
+					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
+				case n != 1:
+					return fmt.Errorf("%%%c: no unit suffix", verb)
+				case sp != ' ':
+					return fmt.Errorf("%%%c: no space after digits: [%c]", verb, sp)
+				}
+				token2Bytes, err := state.Token(false, nil)
+				switch {
+				case err != nil:
+					return fmt.Errorf("%%%c: no unit suffix: %w", verb, err)
+				case len(token2Bytes) < 1:
+					return fmt.Errorf("%%%c: no unit suffix", verb)
+				}
+				token2Str := string(token2Bytes)
+				token2 := legacyBitRateTokenRe[1].FindStringSubmatch(token2Str)
+				if token2 == nil {
+					return fmt.Errorf("%%%c: invalid unit expr: %s", verb, token2Str)
+				}
+				unitExpr = token2[1]
+				if unitExpr == "" {
+					return fmt.Errorf("%%%c: no unit suffix", verb)
+				}
+			}
+		}
+
+		numVal, err := strconv.ParseFloat(numExpr, 64)
+		if err != nil {
+			return fmt.Errorf("%%%c: invalid expr: %s", verb, numExpr)
+		}
+
+		ptr := (*float64)(br)
+
+		for _, unit := range legacyBitRateUnitRe {
+			if unit.re.MatchString(unitExpr) {
+				switch verb {
+				case 's', 'u':
+					*ptr = numVal * unit.brs
+				case 'S', 'U':
+					*ptr = numVal * unit.brb
+				}
+				return nil
+			}
+		}
+
+		// try 3 tokens units
+		// 12.3kilobits per second
+		// 12.3 kilobits per second
+		eSuf := unitExpr
+		for i := 0; i < 2; i++ {
+			sp, n, err := state.ReadRune() // read only one space
+			switch {
+			case errors.Is(err, io.EOF):
+				return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
+			case err != nil:
+				return fmt.Errorf("%%%c: invalid unit suffix: %s: %w", verb, eSuf, err)
+			case n != 1:
+				return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
+			case sp != ' ':
+				return fmt.Errorf("%%%c: unknown unit: %s%c", verb, eSuf, sp)
+			}
+			token34Bytes, err := state.Token(false, nil)
+			switch {
+			case errors.Is(err, io.EOF):
+				return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
+			case err != nil:
+				return fmt.Errorf("%%%c: invalid unit suffix: %s: %w", verb, eSuf, err)
+			case len(token34Bytes) < 1:
+				return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
+			case !legacyBitRateTokenRe[2+i].Match(token34Bytes):
+				return fmt.Errorf("%%%c: unknown unit: %s %s", verb, eSuf, string(token34Bytes))
+			}
+			eSuf += string(sp) + string(token34Bytes)
+		}
+
+		for _, unit := range legacyBitRateUnit3Re {
+			if unit.re.MatchString(unitExpr) {
+				switch verb {
+				case 's', 'u':
+					*ptr = numVal * unit.brs
+				case 'S', 'U':
+					*ptr = numVal * unit.brb
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("%%%c: unknown unit: %s", verb, eSuf)
+
+	default:
+		return fmt.Errorf("unknown verb for BitRate: %%%c", verb)
+	}
+}
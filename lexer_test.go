@@ -0,0 +1,278 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// lexerTestInputs returns the inputs to compare the lexer with the regexp-based
+// implementation.
+func lexerTestInputs() []string {
+	nums := []string{
+		"", "0", "5", "12.5", ".5", "5.", "-5", "+5", "-.5", "1e3", "x",
+		"18446744073709551615", "18446744073709551616", "0.0000000001",
+		"nan", "NaN", "+Inf", "-inf", "inf", "+", "-",
+	}
+	units := []string{
+		"", "B", "b", "kB", "KB", "kiB", "KiB", "kilobytes", "KibiBytes",
+		"byte", "bytes", "MiB", "EB", "EiB", "tibibyte", "bit", "kbit",
+		"Mbit", "kibit", "bits", "gigabits", "KIBIBITS", "bps", "kbps",
+		"Mibps", "bit/s", "Mbit/s", "GiB/s", "kB/s", "foo", "/s", "k/s",
+	}
+	seps := []string{"", " ", "  ", "\t", " "}
+	sufs := []string{
+		"", " per second", " PER Sec", " per", " per minute", " extra",
+		"\n", "  per second", " per\tsecond", " per second/",
+	}
+	var ret []string
+	for _, n := range nums {
+		for _, u := range units {
+			for _, sep := range seps {
+				for _, suf := range sufs {
+					ret = append(ret, n+sep+u+suf)
+				}
+			}
+		}
+	}
+	return append(ret, " ", "\n5 kB", " \r\n5 kB", "\t5 kB", "5\nkB", "-")
+}
+
+// legacyPrefixBug returns whether the unit was accepted as a ByteCount unit by
+// the regexp-based implementation only because the regexps lacked parentheses,
+// e.g. "^kb|kilobytes?$" matches "kbit".
+func legacyPrefixBug(s string) bool {
+	t, _ := lexNumber(strings.Fields(s + " x")[0], false)
+	u := strings.ToLower(t.unit)
+	if u == "" && 1 < len(strings.Fields(s)) {
+		u = strings.ToLower(strings.Fields(s)[1])
+	}
+	if _, _, ok := lookupUnit(byteCountUnits, u, false); ok {
+		return false
+	}
+	for _, p := range []string{"kb", "mb", "gb", "tb", "pb", "eb", "kib", "mib", "gib", "tib", "pib", "eib"} {
+		if strings.HasPrefix(u, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameError reports whether err is the same as the error lerr returned by the
+// regexp-based implementation. Unlike the regexp-based implementation, which
+// joined the number and the unit with a space, the out-of-range errors quote
+// the input as written, e.g. "20EB".
+func sameError(lerr, err string) bool {
+	if lerr == err {
+		return true
+	}
+	return strings.HasSuffix(lerr, ": out of range") &&
+		strings.ReplaceAll(lerr, " ", "") == strings.ReplaceAll(err, " ", "")
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
+
+func TestLexer_compat(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range lexerTestInputs() {
+		for _, verb := range []string{"%s", "%S", "%u", "%U"} {
+			var lbc legacyByteCount
+			var bc ByteCount
+			lerr := errString(scanOne(s, verb, &lbc))
+			err := errString(scanOne(s, verb, &bc))
+			if legacyPrefixBug(s) {
+				continue
+			}
+			if !sameError(lerr, err) || bc != ByteCount(lbc) {
+				t.Errorf("ByteCount: %q %s: want: %d, %s, got: %d, %s", s, verb, lbc, lerr, bc, err)
+			}
+
+			var lbic legacyBitCount
+			var bic BitCount
+			lerr = errString(scanOne(s, verb, &lbic))
+			err = errString(scanOne(s, verb, &bic))
+			if !sameError(lerr, err) || bic != BitCount(lbic) {
+				t.Errorf("BitCount: %q %s: want: %d, %s, got: %d, %s", s, verb, lbic, lerr, bic, err)
+			}
+
+			var lbr legacyBitRate
+			var br BitRate
+			lerr = errString(scanOne(s, verb, &lbr))
+			err = errString(scanOne(s, verb, &br))
+			if err != lerr || !sameFloat(float64(br), float64(lbr)) {
+				t.Errorf("BitRate: %q %s: want: %v, %s, got: %v, %s", s, verb, lbr, lerr, br, err)
+			}
+		}
+	}
+}
+
+func TestLexer_parse(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range lexerTestInputs() {
+		var bc, bcb ByteCount
+		want, wantb := errString(scanOne(s, "%s", &bc)), errString(scanOne(s, "%S", &bcb))
+		if v, err := ParseByteCount(s); errString(err) != errString(wrapErr("byte count", s, want)) || v != bc {
+			t.Errorf("ParseByteCount: %q: want: %d, %s, got: %d, %v", s, bc, want, v, err)
+		}
+		if v, err := ParseByteCountBinary(s); errString(err) != errString(wrapErr("byte count", s, wantb)) || v != bcb {
+			t.Errorf("ParseByteCountBinary: %q: want: %d, %s, got: %d, %v", s, bcb, wantb, v, err)
+		}
+
+		var bd ByteDelta
+		want = errString(scanOne(s, "%s", &bd))
+		if v, err := ParseByteDelta(s); errString(err) != errString(wrapErr("byte delta", s, want)) || v != bd {
+			t.Errorf("ParseByteDelta: %q: want: %d, %s, got: %d, %v", s, bd, want, v, err)
+		}
+
+		var br, brb BitRate
+		want, wantb = errString(scanOne(s, "%s", &br)), errString(scanOne(s, "%S", &brb))
+		if v, err := ParseBitRate(s); errString(err) != errString(wrapErr("bit rate", s, want)) || !sameFloat(float64(v), float64(br)) {
+			t.Errorf("ParseBitRate: %q: want: %v, %s, got: %v, %v", s, br, want, v, err)
+		}
+		if v, err := ParseBitRateBinary(s); errString(err) != errString(wrapErr("bit rate", s, wantb)) || !sameFloat(float64(v), float64(brb)) {
+			t.Errorf("ParseBitRateBinary: %q: want: %v, %s, got: %v, %v", s, brb, wantb, v, err)
+		}
+
+		var byr ByteRate
+		want = errString(scanOne(s, "%s", &byr))
+		if v, err := ParseByteRate(s); errString(err) != errString(wrapErr("byte rate", s, want)) || !sameFloat(float64(v), float64(byr)) {
+			t.Errorf("ParseByteRate: %q: want: %v, %s, got: %v, %v", s, byr, want, v, err)
+		}
+	}
+}
+
+// scanOne scans s into v with fmt.Sscanf.
+func scanOne(s, verb string, v interface{}) error {
+	_, err := fmt.Sscanf(s, verb, v)
+	return err
+}
+
+// wrapErr returns the error returned by the Parse functions for the error
+// message returned by fmt.Sscanf.
+func wrapErr(what, s, msg string) error {
+	if msg == "<nil>" {
+		return nil
+	}
+	return fmt.Errorf("invalid %s: %s: %s", what, s, msg)
+}
+
+func sameFloat(x, y float64) bool {
+	return x == y || math.IsNaN(x) && math.IsNaN(y)
+}
+
+// AllocsPerRun can not be called in parallel tests.
+func TestLexer_allocs(t *testing.T) {
+	for _, s := range []string{"1.5 GiB", "123456789 bytes", "  12kB  "} {
+		if n := testing.AllocsPerRun(100, func() {
+			_, _ = ParseByteCount(s)
+			_, _ = ParseByteCountBinary(s)
+			_, _ = ParseByteCountRound(s, RoundCeil)
+			_, _ = ParseByteDelta("-1.5GB")
+		}); n != 0 {
+			t.Errorf("ParseByteCount: %q: unexpected allocations: %v", s, n)
+		}
+	}
+	for _, s := range []string{"1.5 Gbit", "123456789 bits", "12 kibibits"} {
+		if n := testing.AllocsPerRun(100, func() {
+			_, _ = ParseBitCount(s)
+			_, _ = ParseBitCountBinary(s)
+			_, _ = ParseBitDelta(s)
+		}); n != 0 {
+			t.Errorf("ParseBitCount: %q: unexpected allocations: %v", s, n)
+		}
+	}
+	for _, s := range []string{"1.5 Gbit/s", "-12.5kbps", "10 megabits per second", "NaN bit/s"} {
+		if n := testing.AllocsPerRun(100, func() {
+			_, _ = ParseBitRate(s)
+			_, _ = ParseBitRateBinary(s)
+		}); n != 0 {
+			t.Errorf("ParseBitRate: %q: unexpected allocations: %v", s, n)
+		}
+	}
+	for _, s := range []string{"1.5 GB/s", "2 kilobytes per second"} {
+		if n := testing.AllocsPerRun(100, func() {
+			_, _ = ParseByteRate(s)
+		}); n != 0 {
+			t.Errorf("ParseByteRate: %q: unexpected allocations: %v", s, n)
+		}
+	}
+}
+
+var benchmarkLexerInputs = []string{
+	"1.5 GiB", "123456789 bytes", "12kB", "0.3 kilobytes", "15 EiB",
+}
+
+func BenchmarkParseByteCount(b *testing.B) {
+	b.Run("lexer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = ParseByteCount(benchmarkLexerInputs[i%len(benchmarkLexerInputs)])
+		}
+	})
+	b.Run("lexer-Sscanf", func(b *testing.B) {
+		b.ReportAllocs()
+		var v ByteCount
+		for i := 0; i < b.N; i++ {
+			_, _ = fmt.Sscanf(benchmarkLexerInputs[i%len(benchmarkLexerInputs)], "%s", &v)
+		}
+	})
+	b.Run("regexp-Sscanf", func(b *testing.B) {
+		b.ReportAllocs()
+		var v legacyByteCount
+		for i := 0; i < b.N; i++ {
+			_, _ = fmt.Sscanf(benchmarkLexerInputs[i%len(benchmarkLexerInputs)], "%s", &v)
+		}
+	})
+}
+
+var benchmarkLexerBitInputs = []string{
+	"1.5 Gibit", "123456789 bits", "12kbit", "0.3 kilobits", "15 Eibit",
+}
+
+func BenchmarkParseBitCount(b *testing.B) {
+	b.Run("lexer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = ParseBitCount(benchmarkLexerBitInputs[i%len(benchmarkLexerBitInputs)])
+		}
+	})
+	b.Run("regexp-Sscanf", func(b *testing.B) {
+		b.ReportAllocs()
+		var v legacyBitCount
+		for i := 0; i < b.N; i++ {
+			_, _ = fmt.Sscanf(benchmarkLexerBitInputs[i%len(benchmarkLexerBitInputs)], "%s", &v)
+		}
+	})
+}
+
+var benchmarkLexerRateInputs = []string{
+	"1.5 Gbit/s", "-12.5kbps", "100 Mibit/s", "10 megabits per second", "NaN bit/s",
+}
+
+func BenchmarkParseBitRate(b *testing.B) {
+	b.Run("lexer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = ParseBitRate(benchmarkLexerRateInputs[i%len(benchmarkLexerRateInputs)])
+		}
+	})
+	b.Run("regexp-Sscanf", func(b *testing.B) {
+		b.ReportAllocs()
+		var v legacyBitRate
+		for i := 0; i < b.N; i++ {
+			_, _ = fmt.Sscanf(benchmarkLexerRateInputs[i%len(benchmarkLexerRateInputs)], "%s", &v)
+		}
+	})
+}
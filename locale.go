@@ -0,0 +1,346 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// pluralCategory is a plural category of the CLDR plural rules.
+type pluralCategory int
+
+// Plural categories. The categories zero and two are not used by the supported
+// languages.
+const (
+	pluralOther pluralCategory = iota
+	pluralOne
+	pluralFew
+	pluralMany
+)
+
+// pluralOperands holds the operands of the CLDR plural rules of a number.
+type pluralOperands struct {
+	i   uint64 // the integer digits modulo 1000000
+	big bool   // the integer digits are 1000000 or more
+	v   int    // the number of visible fraction digits, with trailing zeros
+}
+
+// newPluralOperands returns the operands of the printed number, e.g. "1.50".
+func newPluralOperands(num []byte) pluralOperands {
+	var o pluralOperands
+	frac := false
+	for _, c := range num {
+		switch {
+		case c == '.':
+			frac = true
+		case c < '0' || '9' < c:
+		case frac:
+			o.v++
+		default:
+			o.i = o.i*10 + uint64(c-'0')
+			if 1000000 <= o.i {
+				o.i %= 1000000
+				o.big = true
+			}
+		}
+	}
+	return o
+}
+
+// pluralEnglish is the plural rule of English and German: one for "1".
+func pluralEnglish(o pluralOperands) pluralCategory {
+	if !o.big && o.i == 1 && o.v == 0 {
+		return pluralOne
+	}
+	return pluralOther
+}
+
+// pluralFrench is the plural rule of French: one for less than 2, e.g. "1,5".
+func pluralFrench(o pluralOperands) pluralCategory {
+	if !o.big && o.i <= 1 {
+		return pluralOne
+	}
+	return pluralOther
+}
+
+// pluralJapanese is the plural rule of Japanese, which has no plural forms.
+func pluralJapanese(pluralOperands) pluralCategory {
+	return pluralOther
+}
+
+// pluralRussian is the plural rule of Russian: one for 1, 21, ...; few for 2-4,
+// 22-24, ...; many for 0, 5-20, 25-30, ...; and other for fractional numbers.
+func pluralRussian(o pluralOperands) pluralCategory {
+	i10, i100 := o.i%10, o.i%100
+	switch {
+	case o.v != 0:
+		return pluralOther
+	case i10 == 1 && i100 != 11:
+		return pluralOne
+	case 2 <= i10 && i10 <= 4 && (i100 < 12 || 14 < i100):
+		return pluralFew
+	}
+	return pluralMany
+}
+
+// pluralForms holds the forms of a unit name indexed by the plural category.
+type pluralForms [4]string
+
+// forms returns pluralForms for the languages with the singular and the plural.
+func forms(one, other string) pluralForms {
+	return pluralForms{pluralOther: other, pluralOne: one, pluralFew: other, pluralMany: other}
+}
+
+// locale holds the unit names of a language.
+type locale struct {
+	plural func(pluralOperands) pluralCategory
+
+	// abbreviated names
+	siAbbr, binAbbr   [6]string // prefixes
+	byteSIAbbr        [6]string // SI prefixes of byteAbbr, if not siAbbr
+	byteAbbr, bitAbbr string    // units without prefix
+	rateAbbr          string    // appended to the rates, e.g. "/s"
+
+	// long names; byteFull[0] is used without prefix, byteFull[1] after a
+	// prefix, e.g. "Byte" and "Kilobyte" in German.
+	siFull, binFull   [6]string
+	byteFull, bitFull [2]pluralForms
+	rateFull          string // appended to the rates, e.g. " per second"
+}
+
+// Locales of the supported languages. English is not listed, since it is the
+// default.
+var (
+	localeGerman = &locale{
+		plural:   pluralEnglish,
+		siAbbr:   siPrefix.preAbbr,
+		binAbbr:  binPrefix.preAbbr,
+		byteAbbr: "B",
+		bitAbbr:  "bit",
+		rateAbbr: "/s",
+		siFull:   [6]string{"Kilo", "Mega", "Giga", "Tera", "Peta", "Exa"},
+		binFull:  [6]string{"Kibi", "Mebi", "Gibi", "Tebi", "Pebi", "Exbi"},
+		byteFull: [2]pluralForms{forms("Byte", "Byte"), forms("byte", "byte")},
+		bitFull:  [2]pluralForms{forms("Bit", "Bit"), forms("bit", "bit")},
+		rateFull: " pro Sekunde",
+	}
+	localeFrench = &locale{
+		plural:     pluralFrench,
+		siAbbr:     siPrefix.preAbbr,
+		binAbbr:    [6]string{"Ki", "Mi", "Gi", "Ti", "Pi", "Ei"},
+		byteSIAbbr: [6]string{"K", "M", "G", "T", "P", "E"}, // "Ko", but "kbit"
+		byteAbbr:   "o",
+		bitAbbr:    "bit",
+		rateAbbr:   "/s",
+		siFull:     [6]string{"kilo", "méga", "giga", "téra", "péta", "exa"},
+		binFull:    [6]string{"kibi", "mébi", "gibi", "tébi", "pébi", "exbi"},
+		byteFull:   [2]pluralForms{forms("octet", "octets"), forms("octet", "octets")},
+		bitFull:    [2]pluralForms{forms("bit", "bits"), forms("bit", "bits")},
+		rateFull:   " par seconde",
+	}
+	localeJapanese = &locale{
+		plural:   pluralJapanese,
+		siAbbr:   siPrefix.preAbbr,
+		binAbbr:  binPrefix.preAbbr,
+		byteAbbr: "B",
+		bitAbbr:  "bit",
+		rateAbbr: "/s",
+		siFull:   [6]string{"キロ", "メガ", "ギガ", "テラ", "ペタ", "エクサ"},
+		binFull:  [6]string{"キビ", "メビ", "ギビ", "テビ", "ペビ", "エクスビ"},
+		byteFull: [2]pluralForms{forms("バイト", "バイト"), forms("バイト", "バイト")},
+		bitFull:  [2]pluralForms{forms("ビット", "ビット"), forms("ビット", "ビット")},
+		rateFull: "毎秒",
+	}
+	localeRussian = &locale{
+		plural:   pluralRussian,
+		siAbbr:   [6]string{"к", "М", "Г", "Т", "П", "Э"},
+		binAbbr:  [6]string{"Ки", "Ми", "Ги", "Ти", "Пи", "Эи"},
+		byteAbbr: "Б",
+		bitAbbr:  "бит",
+		rateAbbr: "/с",
+		siFull:   [6]string{"кило", "мега", "гига", "тера", "пета", "экса"},
+		binFull:  [6]string{"киби", "меби", "гиби", "теби", "пеби", "эксби"},
+		byteFull: [2]pluralForms{
+			{pluralOther: "байта", pluralOne: "байт", pluralFew: "байта", pluralMany: "байт"},
+			{pluralOther: "байта", pluralOne: "байт", pluralFew: "байта", pluralMany: "байт"},
+		},
+		bitFull: [2]pluralForms{
+			{pluralOther: "бита", pluralOne: "бит", pluralFew: "бита", pluralMany: "бит"},
+			{pluralOther: "бита", pluralOne: "бит", pluralFew: "бита", pluralMany: "бит"},
+		},
+		rateFull: " в секунду",
+	}
+)
+
+// locales maps the primary language subtags to the locales.
+var locales = []struct {
+	lang string
+	loc  *locale
+}{
+	{"de", localeGerman},
+	{"fr", localeFrench},
+	{"ja", localeJapanese},
+	{"ru", localeRussian},
+}
+
+// findLocale returns the locale for the BCP 47 language tag, e.g. "fr" or
+// "de-CH". Only the primary language subtag is used. It returns nil for
+// English, the empty tag and the unsupported languages.
+func findLocale(tag string) *locale {
+	if tag == "" {
+		return nil
+	}
+	lang := tag
+	if i := strings.IndexAny(tag, "-_"); 0 <= i {
+		lang = tag[:i]
+	}
+	for _, l := range locales {
+		if equalFoldASCII(l.lang, lang) {
+			return l.loc
+		}
+	}
+	return nil
+}
+
+// appendUnit appends the unit name with the i-th prefix to dst, in the same
+// way as Formatter.appendUnit.
+func (l *locale) appendUnit(dst []byte, f *Formatter, i int, u *unitName, cat pluralCategory) []byte {
+	dst = append(dst, f.Separator...)
+	bytes, rate := u.full == "byte", u.sufFull != ""
+	if f.LongNames {
+		full, names := l.siFull, l.bitFull
+		if f.Prefixes == BinaryPrefixes {
+			full = l.binFull
+		}
+		if bytes {
+			names = l.byteFull
+		}
+		if 0 <= i {
+			dst = append(dst, full[i]...)
+			dst = append(dst, names[1][cat]...)
+		} else {
+			dst = append(dst, names[0][cat]...)
+		}
+		if rate {
+			dst = append(dst, l.rateFull...)
+		}
+		return dst
+	}
+	if 0 <= i {
+		if f.Prefixes == BinaryPrefixes {
+			dst = append(dst, l.binAbbr[i]...)
+		} else {
+			dst = append(dst, l.siAbbrs(bytes)[i]...)
+		}
+	}
+	if bytes {
+		dst = append(dst, l.byteAbbr...)
+	} else {
+		dst = append(dst, l.bitAbbr...)
+	}
+	if rate {
+		dst = append(dst, l.rateAbbr...)
+	}
+	return dst
+}
+
+// siAbbrs returns the abbreviated SI prefixes of the bytes or the bits.
+func (l *locale) siAbbrs(bytes bool) *[6]string {
+	if bytes && l.byteSIAbbr[0] != "" {
+		return &l.byteSIAbbr
+	}
+	return &l.siAbbr
+}
+
+// localUnit is an entry of the table of the localized unit names for parsing.
+type localUnit struct {
+	name string
+	unit scanUnit
+}
+
+// parseTables returns the tables of the localized unit names for parsing,
+// indexed by localUnitKind. Every form of the abbreviated and the long names
+// is listed.
+func (l *locale) parseTables() [4][]localUnit {
+	var tables [4][]localUnit
+	for k := range tables {
+		bytes, rate := k&1 == 0, k&2 != 0
+		abbr, suf, sufFull, names := l.bitAbbr, "", "", l.bitFull
+		if bytes {
+			abbr, names = l.byteAbbr, l.byteFull
+		}
+		if rate {
+			suf, sufFull = l.rateAbbr, l.rateFull
+		}
+		add := func(name string, exp int, bin, long bool) {
+			for _, t := range tables[k] {
+				if t.name == name {
+					return
+				}
+			}
+			tables[k] = append(tables[k], localUnit{name, scanUnit{exp: exp, bin: bin, long: long}})
+		}
+		add(abbr+suf, -1, false, false)
+		for _, form := range names[0] {
+			add(form+sufFull, -1, false, true)
+		}
+		for i := 0; i < 6; i++ {
+			add(l.siAbbrs(bytes)[i]+abbr+suf, i, false, false)
+			add(l.siAbbr[i]+abbr+suf, i, false, false)
+			add(l.binAbbr[i]+abbr+suf, i, true, false)
+			for _, form := range names[1] {
+				add(l.siFull[i]+form+sufFull, i, false, true)
+				add(l.binFull[i]+form+sufFull, i, true, true)
+			}
+		}
+	}
+	return tables
+}
+
+// localUnitKind returns the index of the parse table for the type; bit counts
+// are odd, and rates are 2 or more.
+func localUnitKind(typ string) int {
+	switch typ {
+	case "BitCount", "BitDelta":
+		return 1
+	case "ByteRate":
+		return 2
+	case "BitRate":
+		return 3
+	}
+	return 0
+}
+
+// localParseTables holds the parse tables of the locales.
+var localParseTables = func() map[*locale][4][]localUnit {
+	m := make(map[*locale][4][]localUnit, len(locales))
+	for _, l := range locales {
+		m[l.loc] = l.loc.parseTables()
+	}
+	return m
+}()
+
+// lookupUnit returns the localized unit at the beginning of s, which is
+// followed by the end or a space, and the length of its name. The longest one
+// is used, and the case is ignored.
+func (l *locale) lookupUnit(s, typ string) (*scanUnit, int, bool) {
+	table := localParseTables[l][localUnitKind(typ)]
+	var found *scanUnit
+	n := 0
+	for i := range table {
+		t := &table[i]
+		m := len(t.name)
+		if m <= n || len(s) < m || !strings.EqualFold(t.name, s[:m]) {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(s[m:]); m < len(s) && !unicode.IsSpace(r) {
+			continue
+		}
+		found, n = &t.unit, m
+	}
+	return found, n, found != nil
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestFormatter_Language(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fr     = infounit.Formatter{Precision: 1, Separator: " ", Language: "fr", Number: infounit.NumberFormatFrench}
+		frLong = infounit.Formatter{Precision: 1, Separator: " ", Language: "fr-CA", LongNames: true}
+		frBin  = infounit.Formatter{Prefixes: infounit.BinaryPrefixes, Language: "fr"}
+		de     = infounit.Formatter{Precision: 1, Separator: " ", Language: "de", LongNames: true}
+		ja     = infounit.Formatter{Precision: 1, Separator: " ", Language: "ja", LongNames: true}
+		ru     = infounit.Formatter{Precision: 0, Separator: " ", Language: "ru_RU", LongNames: true}
+		ruAbbr = infounit.Formatter{Precision: 1, Separator: " ", Language: "RU"}
+		never  = infounit.Formatter{Separator: " ", Language: "fr", LongNames: true, Plural: infounit.PluralNever}
+		xx     = infounit.Formatter{Separator: " ", Language: "xx", LongNames: true}
+	)
+	tc := []struct {
+		f *infounit.Formatter
+		v infounit.ByteCount
+		s string
+	}{
+		{&fr, infounit.Megabyte * 1500, "1,5 Go"},
+		{&fr, 100, "100 o"},
+		{&fr, 1500, "1,5 Ko"},
+		{&frBin, infounit.Mebibyte * 3, "3Mio"},
+		{&frLong, 1, "1 octet"},
+		{&frLong, 0, "0 octet"},
+		{&frLong, 2, "2 octets"},
+		{&frLong, infounit.Kilobyte * 1500, "1.5 mégaoctet"},
+		{&frLong, infounit.Megabyte * 25, "25.0 mégaoctets"},
+		{&de, 1, "1 Byte"},
+		{&de, 2, "2 Byte"},
+		{&de, infounit.Gigabyte * 2, "2.0 Gigabyte"},
+		{&ja, infounit.Gigabyte * 2, "2.0 ギガバイト"},
+		{&ja, 5, "5 バイト"},
+		{&ru, 1, "1 байт"},
+		{&ru, 2, "2 байта"},
+		{&ru, 5, "5 байт"},
+		{&ru, 11, "11 байт"},
+		{&ru, 21, "21 байт"},
+		{&ru, 22, "22 байта"},
+		{&ru, 112, "112 байт"},
+		{&ru, infounit.Megabyte * 3, "3 мегабайта"},
+		{&ruAbbr, infounit.Megabyte * 3, "3.0 МБ"},
+		{&never, 5, "5 octet"},
+		{&xx, 5, "5 bytes"},
+	}
+	for _, c := range tc {
+		if s := c.f.FormatByteCount(c.v); s != c.s {
+			t.Errorf("%q %d: want: %q, got: %q", c.f.Language, c.v, c.s, s)
+		}
+	}
+
+	f := infounit.Formatter{Precision: 1, Separator: " ", Language: "ru"}
+	if s := f.FormatBitRate(infounit.MegabitPerSecond * 100); s != "100.0 Мбит/с" {
+		t.Errorf("BitRate: unexpected result: %q", s)
+	}
+	f = infounit.Formatter{Precision: 1, Separator: " ", Language: "fr", LongNames: true, Number: infounit.NumberFormatFrench}
+	if s := f.FormatBitRate(infounit.MegabitPerSecond * 2.5); s != "2,5 mégabits par seconde" {
+		t.Errorf("BitRate: unexpected result: %q", s)
+	}
+	if s := fr.FormatBitCount(3000); s != "3,0 kbit" {
+		t.Errorf("BitCount: unexpected result: %q", s)
+	}
+	if s := fr.FormatBitRate(infounit.KilobitPerSecond * 3); s != "3,0 kbit/s" {
+		t.Errorf("BitRate: unexpected result: %q", s)
+	}
+	if s := fr.FormatByteRate(infounit.KilobytePerSecond * 1.5); s != "1,5 Ko/s" {
+		t.Errorf("ByteRate: unexpected result: %q", s)
+	}
+	f = infounit.Formatter{Separator: " ", Language: "ja", LongNames: true}
+	if s := f.FormatByteRate(infounit.KilobytePerSecond * 3); s != "3 キロバイト毎秒" {
+		t.Errorf("ByteRate: unexpected result: %q", s)
+	}
+}
+
+//
+func TestParser_Language(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fr = &infounit.Parser{Language: "fr", AllowLongNames: true, Numbers: []infounit.NumberFormat{infounit.NumberFormatFrench}}
+		ru = &infounit.Parser{Language: "ru", AllowLongNames: true, Numbers: []infounit.NumberFormat{{DecimalMark: ','}}}
+		ja = &infounit.Parser{Language: "ja"}
+	)
+	tc := []struct {
+		p      *infounit.Parser
+		src    string
+		v      infounit.ByteCount
+		reason infounit.ParseErrorReason
+	}{
+		{fr, "1,5 Go", infounit.Megabyte * 1500, 0},
+		{fr, "1,5Go", infounit.Megabyte * 1500, 0},
+		{fr, "3 Mio", infounit.Mebibyte * 3, 0},
+		{fr, "1,5 Ko", 1500, 0},
+		{fr, "2 Kio", infounit.Kibibyte * 2, 0},
+		{fr, "2 mégaoctets", infounit.Megabyte * 2, 0},
+		{fr, "2 MÉGAOCTETS", infounit.Megabyte * 2, 0},
+		{fr, "1 octet", 1, 0},
+		{fr, "1 kB", infounit.Kilobyte, 0},
+		{fr, "1 kilobyte", infounit.Kilobyte, 0},
+		{fr, "1 Go de plus", 0, infounit.ReasonTrailingInput},
+		{fr, "1 Xo", 0, infounit.ReasonUnknownUnit},
+		{ru, "3 МБ", infounit.Megabyte * 3, 0},
+		{ru, "5 мегабайт", infounit.Megabyte * 5, 0},
+		{ru, "2,5 килобайта", 2500, 0},
+		{ru, "2 КиБ", infounit.Kibibyte * 2, 0},
+		{ja, "2 GB", infounit.Gigabyte * 2, 0},
+		{ja, "2 ギガバイト", 0, infounit.ReasonDisallowed},
+		{&infounit.Parser{}, "2 Go", 0, infounit.ReasonUnknownUnit},
+	}
+	for _, c := range tc {
+		v, err := c.p.ParseByteCount(c.src)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%q %q: want: %d, got: %d, %v", c.p.Language, c.src, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		if !errors.As(err, &pe) || pe.Reason != c.reason {
+			t.Errorf("%q %q: %v expected, got: %d, %v", c.p.Language, c.src, c.reason, v, err)
+		}
+	}
+
+	if v, err := ru.ParseBitRate("100 Мбит/с"); err != nil || v != infounit.MegabitPerSecond*100 {
+		t.Errorf("BitRate: unexpected result: %v, %v", v, err)
+	}
+	if v, err := fr.ParseBitRate("2,5 mégabits par seconde"); err != nil || v != infounit.MegabitPerSecond*2.5 {
+		t.Errorf("BitRate: unexpected result: %v, %v", v, err)
+	}
+	if v, err := ru.ParseBitCount("3 килобита"); err != nil || v != infounit.Kilobit*3 {
+		t.Errorf("BitCount: unexpected result: %v, %v", v, err)
+	}
+}
+
+//
+func TestParser_Language_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	values := []infounit.ByteCount{
+		1, 2, 5, 21,
+		infounit.Kilobyte * 3, infounit.Megabyte * 1500, infounit.Gigabyte * 25,
+		infounit.Kibibyte * 2, infounit.Mebibyte * 1536, infounit.Tebibyte,
+	}
+	for _, lang := range []string{"de", "fr", "ja", "ru"} {
+		p := &infounit.Parser{Language: lang, AllowLongNames: true}
+		for _, f := range []infounit.Formatter{
+			{Precision: -1, Separator: " ", Language: lang},
+			{Precision: -1, Language: lang, Prefixes: infounit.BinaryPrefixes},
+			{Precision: -1, Separator: " ", Language: lang, LongNames: true},
+			{Precision: -1, Separator: " ", Language: lang, LongNames: true, Prefixes: infounit.BinaryPrefixes},
+		} {
+			for _, v := range values {
+				s := f.FormatByteCount(v)
+				if got, err := p.ParseByteCount(s); err != nil || got != v {
+					t.Errorf("%q: %d formatted as %q, parsed into %d, %v", lang, v, s, got, err)
+				}
+				r := infounit.BitRate(v)
+				s = f.FormatBitRate(r)
+				if got, err := p.ParseBitRate(s); err != nil || got != r {
+					t.Errorf("%q: %v formatted as %q, parsed into %v, %v", lang, float64(r), s, got, err)
+				}
+				b := infounit.BitCount(v)
+				s = f.FormatBitCount(b)
+				if got, err := p.ParseBitCount(s); err != nil || got != b {
+					t.Errorf("%q: %d formatted as %q, parsed into %d, %v", lang, b, s, got, err)
+				}
+				br := infounit.ByteRate(v)
+				s = f.FormatByteRate(br)
+				if got, err := p.ParseByteRate(s); err != nil || got != br {
+					t.Errorf("%q: %v formatted as %q, parsed into %v, %v", lang, float64(br), s, got, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// NumberFormat specifies the decimal mark and the digit grouping of numbers. It
+// is used by Parser to accept localized numbers such as "1.234,5 MB", and by
+// Formatter to print them. The zero value is the plain format, "1234.5".
+type NumberFormat struct {
+	// DecimalMark is the decimal separator, e.g. ','. Zero means '.'.
+	DecimalMark rune
+
+	// GroupSeparator is inserted between the groups of three digits in the
+	// integer part, e.g. ',', '.', ' ', '\u2009' (thin space), '_' or '\''.
+	// Zero means no grouping. It must differ from the decimal mark.
+	GroupSeparator rune
+}
+
+// Number formats commonly used.
+var (
+	NumberFormatEnglish = NumberFormat{'.', ','}      // 1,234,567.5
+	NumberFormatGerman  = NumberFormat{',', '.'}      // 1.234.567,5
+	NumberFormatFrench  = NumberFormat{',', '\u202f'} // 1 234 567,5 with narrow no-break spaces
+	NumberFormatSI      = NumberFormat{'.', '\u2009'} // 1 234 567.5 with thin spaces
+	NumberFormatSwiss   = NumberFormat{'.', '\''}     // 1'234'567.5
+	NumberFormatGo      = NumberFormat{'.', '_'}      // 1_234_567.5
+)
+
+// decimalMark returns the decimal mark, '.' if not specified.
+func (nf NumberFormat) decimalMark() rune {
+	if nf.DecimalMark == 0 {
+		return '.'
+	}
+	return nf.DecimalMark
+}
+
+// isPlain returns whether the format is the same as the plain format.
+func (nf NumberFormat) isPlain() bool {
+	return nf.decimalMark() == '.' && nf.GroupSeparator == 0
+}
+
+// lexNumber reads a number in the format at the beginning of s, and returns it
+// as a numToken in the plain format, and the number of bytes read. The number
+// must be followed by the end, a space, a letter or a unit suffix. If sign is
+// true, the number may have a sign. ok is false if s does not begin with a
+// number in the format, and then n is the offset of the error.
+func (nf NumberFormat) lexNumber(s string, sign bool) (t numToken, n int, ok bool) {
+	buf := make([]byte, 0, len(s)+1)
+	if sign && n < len(s) && (s[n] == '+' || s[n] == '-') {
+		buf = append(buf, s[n])
+		n++
+	}
+	sep, dm := nf.GroupSeparator, nf.decimalMark()
+	start := n
+	first := digitsEnd(s, start)
+	if first == start {
+		return t, n, false
+	}
+	buf = append(buf, s[start:first]...)
+	n = first
+	for sep != 0 && sep != dm && n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r != sep {
+			break
+		}
+		end := digitsEnd(s, n+size)
+		if end == n+size { // not followed by digits, e.g. "5 kB"
+			break
+		}
+		if end-n-size != 3 || 3 < first-start { // e.g. "1,5", "1000,000"
+			return t, n, false
+		}
+		buf = append(buf, s[n+size:end]...)
+		n = end
+	}
+	t.integer = string(buf)
+	if r, size := utf8.DecodeRuneInString(s[n:]); r == dm && digitsEnd(s, n+size) != n+size {
+		end := digitsEnd(s, n+size)
+		t.frac = s[n+size : end]
+		buf = append(buf, '.')
+		buf = append(buf, t.frac...)
+		n = end
+	}
+	if n < len(s) {
+		r, _ := utf8.DecodeRuneInString(s[n:])
+		if !unicode.IsSpace(r) && !unicode.IsLetter(r) && !isUnitChar(s[n], true) {
+			return t, n, false
+		}
+	}
+	t.num = string(buf)
+	return t, n, true
+}
+
+// appendLocal rewrites the plain number in dst[start:] in the format. The sign
+// is kept as is.
+func (nf NumberFormat) appendLocal(dst []byte, start int) []byte {
+	if nf.isPlain() {
+		return dst
+	}
+	var buf [fmtBufSize]byte
+	num := append(buf[:0], dst[start:]...)
+	dst = dst[:start]
+	i := 0
+	for i < len(num) && (num[i] < '0' || '9' < num[i]) {
+		i++
+	}
+	dst = append(dst, num[:i]...)
+	end := i
+	for end < len(num) && '0' <= num[end] && num[end] <= '9' {
+		end++
+	}
+	for j := i; j < end; j++ {
+		if nf.GroupSeparator != 0 && j != i && (end-j)%3 == 0 {
+			dst = appendRune(dst, nf.GroupSeparator)
+		}
+		dst = append(dst, num[j])
+	}
+	if end < len(num) && num[end] == '.' {
+		dst = appendRune(dst, nf.decimalMark())
+		end++
+	}
+	return append(dst, num[end:]...)
+}
+
+// appendRune appends the UTF-8 encoding of r to dst.
+func appendRune(dst []byte, r rune) []byte {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	return append(dst, buf[:n]...)
+}
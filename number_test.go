@@ -0,0 +1,122 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestParser_Numbers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		en    = &infounit.Parser{Numbers: []infounit.NumberFormat{infounit.NumberFormatEnglish}}
+		de    = &infounit.Parser{Numbers: []infounit.NumberFormat{infounit.NumberFormatGerman}}
+		both  = &infounit.Parser{Numbers: []infounit.NumberFormat{infounit.NumberFormatEnglish, infounit.NumberFormatGerman}}
+		space = &infounit.Parser{Numbers: []infounit.NumberFormat{{GroupSeparator: ' '}}, AllowMissingUnit: true}
+		misc  = &infounit.Parser{Numbers: []infounit.NumberFormat{
+			infounit.NumberFormatSI, infounit.NumberFormatSwiss, infounit.NumberFormatGo,
+		}}
+	)
+	tc := []struct {
+		p      *infounit.Parser
+		src    string
+		v      infounit.ByteCount
+		reason infounit.ParseErrorReason
+	}{
+		{en, "1,234,567 B", 1234567, 0},
+		{en, "1234567 B", 1234567, 0},
+		{en, "1,234.5 kB", 1234500, 0},
+		{en, "1.5 GB", infounit.Megabyte * 1500, 0},
+		{en, "1,5 GB", 0, infounit.ReasonBadNumber},
+		{en, "1,2345 GB", 0, infounit.ReasonBadNumber},
+		{en, "1234,567 B", 0, infounit.ReasonBadNumber},
+		{en, "1,234,56 B", 0, infounit.ReasonBadNumber},
+		{de, "1,5 GB", infounit.Megabyte * 1500, 0},
+		{de, "1.234,5 MB", infounit.Kilobyte * 1234500, 0},
+		{de, "1.5 GB", 0, infounit.ReasonBadNumber},
+		{de, "1.234.5 MB", 0, infounit.ReasonBadNumber},
+		{both, "1,5 GB", infounit.Megabyte * 1500, 0},
+		{both, "1.5 GB", infounit.Megabyte * 1500, 0},
+		{both, "1.234,5 MB", infounit.Kilobyte * 1234500, 0},
+		{both, "1,234.5 MB", infounit.Kilobyte * 1234500, 0},
+		{both, "1000 B", 1000, 0},
+		{both, "1,234 MB", 0, infounit.ReasonAmbiguous},
+		{both, "1.234 MB", 0, infounit.ReasonAmbiguous},
+		{space, "1 000 000 B", 1000000, 0},
+		{space, "1 000 000", 1000000, 0},
+		{space, "5 kB", infounit.Kilobyte * 5, 0},
+		{space, "5 000", 5000, 0},
+		{misc, "1\u2009000\u2009000 B", 1000000, 0},
+		{misc, "1'000'000 B", 1000000, 0},
+		{misc, "1_000_000 B", 1000000, 0},
+		{misc, "1_000 000 B", 0, infounit.ReasonUnknownUnit},
+	}
+	for _, c := range tc {
+		v, err := c.p.ParseByteCount(c.src)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%v %q: want: %d, got: %d, %v", c.p.Numbers, c.src, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		if !errors.As(err, &pe) || pe.Reason != c.reason {
+			t.Errorf("%v %q: %v expected, got: %d, %v", c.p.Numbers, c.src, c.reason, v, err)
+		}
+	}
+
+	const es = "invalid byte count: 1,234 MB: ambiguous number: 1,234"
+	if _, err := both.ParseByteCount("1,234 MB"); err == nil || err.Error() != es {
+		t.Errorf("want: %q, got: %v", es, err)
+	}
+	if v, err := both.ParseBitRate("-1.234,5 kbit/s"); err != nil || v != -1234500 {
+		t.Errorf("BitRate: unexpected result: %v, %v", v, err)
+	}
+	if v, err := both.ParseByteDelta("-1,5 kB"); err != nil || v != -1500 {
+		t.Errorf("ByteDelta: unexpected result: %v, %v", v, err)
+	}
+}
+
+//
+func TestFormatter_Number(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		f infounit.Formatter
+		v infounit.ByteCount
+		s string
+	}{
+		{infounit.Formatter{Precision: 1, Separator: " ", Number: infounit.NumberFormatGerman}, infounit.Megabyte * 1500, "1,5 GB"},
+		{infounit.Formatter{Precision: 1, Separator: " ", Number: infounit.NumberFormatGerman, MaxPrefix: infounit.PrefixMega}, infounit.Kilobyte * 1234500, "1.234,5 MB"},
+		{infounit.Formatter{Number: infounit.NumberFormatEnglish, MaxPrefix: infounit.PrefixNone}, 1234567, "1,234,567B"},
+		{infounit.Formatter{Number: infounit.NumberFormatEnglish, MaxPrefix: infounit.PrefixNone}, 123456, "123,456B"},
+		{infounit.Formatter{Number: infounit.NumberFormatSI, MaxPrefix: infounit.PrefixNone}, 1000000, "1 000 000B"},
+		{infounit.Formatter{Number: infounit.NumberFormatGo, MaxPrefix: infounit.PrefixNone}, 999, "999B"},
+		{infounit.Formatter{Precision: 2, Number: infounit.NumberFormatFrench}, 1500, "1,50kB"},
+	}
+	for _, c := range tc {
+		s := c.f.FormatByteCount(c.v)
+		if s != c.s {
+			t.Errorf("%+v %d: want: %q, got: %q", c.f, c.v, c.s, s)
+		}
+		p := &infounit.Parser{Numbers: []infounit.NumberFormat{c.f.Number}}
+		if v, err := p.ParseByteCount(s); err != nil || v != c.v {
+			t.Errorf("%+v %q: round trip: got: %d, %v", c.f, s, v, err)
+		}
+	}
+
+	f := infounit.Formatter{Precision: 1, Separator: " ", Number: infounit.NumberFormatGerman, PlusSign: true, MaxPrefix: infounit.PrefixKilo}
+	if s := f.FormatBitRate(-1234567.8); s != "-1.234,6 kbit/s" {
+		t.Errorf("BitRate: unexpected result: %q", s)
+	}
+	if s := f.FormatByteDelta(1234567); s != "+1.234,6 kB" {
+		t.Errorf("ByteDelta: unexpected result: %q", s)
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SpacePolicy specifies whether a Parser accepts a space between the digits and
+// the unit suffix.
+type SpacePolicy int
+
+// Space policies.
+const (
+	SpaceOptional  SpacePolicy = iota // both "5kB" and "5 kB"
+	SpaceRequired                     // "5 kB" only
+	SpaceForbidden                    // "5kB" only
+)
+
+// Parser holds a set of parsing options for strict parsing. Unlike
+// ParseByteCount and the other Parse functions, which stop after the value
+// like fmt.Sscanf, the methods of Parser require the whole input to be a
+// single value, and reject inputs such as "10 GB please" and "5 kB, 6 kB".
+// This is useful to validate configurations:
+//
+// 	p := infounit.Parser{AllowLongNames: true}
+// 	p.ParseByteCount("1.5 GiB")       // 1610612736 B
+// 	p.ParseByteCount("1.5 gibibytes") // 1610612736 B
+// 	p.ParseByteCount("1.5 GiB RAM")   // trailing input
+//
+// The zero value accepts only the abbreviated unit names with an optional
+// single space before them, and rejects the leading and trailing spaces. A
+// Parser is safe for concurrent use as long as it is not modified.
+type Parser struct {
+	// AllowMissingUnit accepts a number without unit suffix as a number
+	// of the unit without prefix, e.g. "100" as 100 bytes.
+	AllowMissingUnit bool
+
+	// Space specifies whether a space between the digits and the unit
+	// suffix is accepted. Only a single U+0020 is accepted as the space.
+	Space SpacePolicy
+
+	// AllowLongNames accepts the long unit names, e.g. "kilobytes" and
+	// "kilobits per second", as well as the abbreviated ones.
+	AllowLongNames bool
+
+	// SIAsBinary treats the SI prefixes as binary prefixes, the same as the
+	// %S verb of Scan. That is, "100 kB" is parsed as 100 KiB (=102400 B).
+	SIAsBinary bool
+
+	// TrimSpace ignores the leading and trailing white spaces.
+	TrimSpace bool
+
+	// Rounding is the rounding mode used when a ByteCount, BitCount,
+	// ByteDelta or BitDelta value is not a whole number of bytes or bits.
+	Rounding RoundingMode
+
+	// Numbers is the list of the accepted number formats, e.g.
+	// NumberFormatGerman to accept "1.234,5 MB". If it is empty, only the
+	// plain format "1234.5" is accepted. If more than one format is listed,
+	// the number must be read as the same value by all the formats that
+	// accept it. Otherwise, the input is rejected as ambiguous. For example,
+	// with NumberFormatEnglish and NumberFormatGerman, "1,5 GB" and
+	// "1.234,5 MB" are accepted, but "1,234 MB" is rejected.
+	Numbers []NumberFormat
+
+	// Language is the BCP 47 language tag of the localized unit names that
+	// are accepted in addition to the English ones, e.g. "fr" for "1,5 Mo"
+	// and "2 mégaoctets", the same as Formatter. The long names are accepted
+	// only with AllowLongNames. The case is ignored.
+	Language string
+}
+
+// unmarshalParser is the Parser used by the Unmarshal methods to decode
+// strings, which accepts all the forms accepted by the Parse functions except
+// the trailing input.
+var unmarshalParser = &Parser{AllowLongNames: true, TrimSpace: true}
+
+// ParseByteCount converts the whole string into a ByteCount value.
+func (p *Parser) ParseByteCount(s string) (ByteCount, error) {
+	v, err := p.parseUint(s, "ByteCount", byteCountUnits, "byte count")
+	return ByteCount(v), err
+}
+
+// ParseBitCount converts the whole string into a BitCount value.
+func (p *Parser) ParseBitCount(s string) (BitCount, error) {
+	v, err := p.parseUint(s, "BitCount", bitCountUnits, "bit count")
+	return BitCount(v), err
+}
+
+// ParseByteDelta converts the whole string into a ByteDelta value.
+func (p *Parser) ParseByteDelta(s string) (ByteDelta, error) {
+	v, err := p.parseInt(s, "ByteDelta", byteCountUnits, "byte count")
+	return ByteDelta(v), err
+}
+
+// ParseBitDelta converts the whole string into a BitDelta value.
+func (p *Parser) ParseBitDelta(s string) (BitDelta, error) {
+	v, err := p.parseInt(s, "BitDelta", bitCountUnits, "bit count")
+	return BitDelta(v), err
+}
+
+// ParseBitRate converts the whole string into a BitRate value.
+func (p *Parser) ParseBitRate(s string) (BitRate, error) {
+	v, err := p.parseFloat(s, "BitRate", bitRateUnits, bitCountUnits)
+	return BitRate(v), err
+}
+
+// ParseByteRate converts the whole string into a ByteRate value.
+func (p *Parser) ParseByteRate(s string) (ByteRate, error) {
+	v, err := p.parseFloat(s, "ByteRate", byteRateUnits, byteRateUnits3)
+	return ByteRate(v), err
+}
+
+// strictValue is a value lexed by a Parser.
+type strictValue struct {
+	numToken
+	off  int    // offset of the number, after the sign
+	neg  bool   // preceded by '-'
+	unit string // the unit suffix as written, for errors
+	exp  int    // index of the prefix, or -1 for no prefix
+	mul  uint64 // value of the unit
+}
+
+// parseUint parses a ByteCount or BitCount value.
+func (p *Parser) parseUint(s, typ string, units []scanUnit, what string) (uint64, error) {
+	lx := lexer{s: s, typ: typ}
+	v, err := p.lex(&lx, false, false, units, nil)
+	if err != nil {
+		return 0, err
+	}
+	return p.count(&lx, v, what)
+}
+
+// parseInt parses a ByteDelta or BitDelta value.
+func (p *Parser) parseInt(s, typ string, units []scanUnit, what string) (int64, error) {
+	lx := lexer{s: s, typ: typ}
+	v, err := p.lex(&lx, true, false, units, nil)
+	if err != nil {
+		return 0, err
+	}
+	mag, err := p.count(&lx, v, what)
+	if err != nil {
+		return 0, err
+	}
+	i, err := signInt64(mag, v.neg)
+	if err != nil {
+		start := v.off
+		if v.neg {
+			start--
+		}
+		return 0, lx.errorf(ReasonOutOfRange, start, s[start:lx.pos], err, "%v", err)
+	}
+	return i, nil
+}
+
+// count converts the lexed value into a count of bytes or bits.
+func (p *Parser) count(lx *lexer, v strictValue, what string) (uint64, error) {
+	if v.exp < 0 {
+		if v.integer == "" || v.frac != "" {
+			return 0, lx.errorf(ReasonBadNumber, v.off, v.num, nil, "non-integer %s: %s", what, v.num)
+		}
+		n, err := parseUint64(v.num)
+		if err != nil {
+			return 0, lx.errorf(numErrReason(err), v.off, v.num, err, "invalid %s: %s: %v", what, v.num, err)
+		}
+		return n, nil
+	}
+	n, err := mulDecimal(v.integer, v.frac, v.mul, p.Rounding)
+	if err != nil {
+		return 0, lx.errorf(numErrReason(err), v.off, v.num, err, "%s %s: %v", v.num, v.unit, err)
+	}
+	return n, nil
+}
+
+// parseFloat parses a BitRate or ByteRate value.
+func (p *Parser) parseFloat(s, typ string, units, units3 []scanUnit) (float64, error) {
+	lx := lexer{s: s, typ: typ}
+	v, err := p.lex(&lx, false, true, units, units3)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(v.num, 64)
+	if err != nil {
+		return 0, lx.errorf(numErrReason(err), v.off, v.num, err, "invalid expr: %s", v.num)
+	}
+	return f * float64(v.mul), nil
+}
+
+// lex splits the whole input into the number and the unit suffix according to
+// the options. lx.pos is left at the end of the value.
+func (p *Parser) lex(lx *lexer, signed, rate bool, units, units3 []scanUnit) (strictValue, error) {
+	s, end := lx.s, len(lx.s)
+	if p.TrimSpace {
+		for lx.pos < end {
+			r, n := utf8.DecodeRuneInString(s[lx.pos:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			lx.pos += n
+		}
+		for lx.pos < end {
+			r, n := utf8.DecodeLastRuneInString(s[:end])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			end -= n
+		}
+	}
+	if end <= lx.pos {
+		return strictValue{}, lx.errorf(ReasonBadNumber, lx.pos, "", nil, "no input")
+	}
+
+	var v strictValue
+	loc := findLocale(p.Language)
+	start := lx.pos
+	if signed && (s[lx.pos] == '+' || s[lx.pos] == '-') {
+		v.neg = s[lx.pos] == '-'
+		lx.pos++
+	}
+	v.off = lx.pos
+	width := 0
+	if len(p.Numbers) == 0 {
+		v.numToken, _ = lexNumber(s[lx.pos:end], rate)
+		width = len(v.num)
+	} else {
+		var err error
+		if v.numToken, width, err = p.lexLocal(lx, end, rate); err != nil {
+			return v, err
+		}
+	}
+	if v.num == "" {
+		tok := s[start:tokenEnd(s, start, end)]
+		if tok == "" {
+			_, n := utf8.DecodeRuneInString(s[start:])
+			return v, lx.errorf(ReasonBadNumber, start, s[start:start+n], nil, "leading space")
+		}
+		reason := ReasonBadNumber
+		if !signed && !rate && tok[0] == '-' {
+			if t, _ := lexNumber(tok[1:], false); t.num != "" {
+				reason = ReasonNegative // e.g. "-5 B"
+			}
+		}
+		return v, lx.errorf(reason, start, tok, nil, "invalid expr: %s", tok)
+	}
+	lx.pos += width
+
+	// separator
+	if lx.pos == end {
+		if !p.AllowMissingUnit {
+			return v, lx.errorf(ReasonMissingUnit, lx.pos, "", nil, "no unit suffix")
+		}
+		v.exp, v.mul = -1, 1
+		return v, nil
+	}
+	r, n := utf8.DecodeRuneInString(s[lx.pos:end])
+	switch {
+	case r == ' ':
+		if p.Space == SpaceForbidden {
+			return v, lx.errorf(ReasonDisallowed, lx.pos, " ", nil, "space before unit")
+		}
+		lx.pos++
+	case unicode.IsSpace(r):
+		return v, lx.errorf(ReasonDisallowed, lx.pos, s[lx.pos:lx.pos+n], nil, "invalid space before unit: %q", r)
+	case !isUnitChar(s[lx.pos], rate) && (loc == nil || !unicode.IsLetter(r)):
+		tok := s[start:tokenEnd(s, start, end)]
+		return v, lx.errorf(ReasonBadNumber, start, tok, nil, "invalid expr: %s", tok)
+	case p.Space == SpaceRequired:
+		return v, lx.errorf(ReasonDisallowed, lx.pos, "", nil, "no space before unit")
+	}
+
+	// unit suffix
+	uoff := lx.pos
+	var (
+		u   *scanUnit
+		mul uint64
+		ok  bool
+	)
+	if loc != nil {
+		var n int
+		if u, n, ok = loc.lookupUnit(s[uoff:end], lx.typ); ok {
+			lx.pos += n
+			mul = u.value(p.SIAsBinary)
+		}
+	}
+	if !ok {
+		word := s[uoff:tokenEnd(s, uoff, end)]
+		switch {
+		case word == "":
+			return v, lx.errorf(ReasonMissingUnit, uoff, "", nil, "no unit suffix")
+		case !isUnitExpr(word, rate):
+			return v, lx.errorf(ReasonUnknownUnit, uoff, word, nil, "invalid unit expr: %s", word)
+		}
+		u, mul, ok = lookupUnit(units, word, p.SIAsBinary)
+		lx.pos += len(word)
+		if !ok && units3 != nil {
+			if u, mul, ok = lookupUnit(units3, word, p.SIAsBinary); ok {
+				if lx.pos, ok = perSecond(s, lx.pos, end); ok {
+					u = &scanUnit{exp: u.exp, long: true}
+				}
+			}
+		}
+	}
+	v.unit = s[uoff:lx.pos]
+	switch {
+	case !ok:
+		return v, lx.errorf(ReasonUnknownUnit, uoff, v.unit, nil, "unknown unit: %s", v.unit)
+	case u.long && !p.AllowLongNames:
+		return v, lx.errorf(ReasonDisallowed, uoff, v.unit, nil, "long unit name: %s", v.unit)
+	case lx.pos < end:
+		return v, lx.errorf(ReasonTrailingInput, lx.pos, s[lx.pos:end], nil, "trailing input: %q", s[lx.pos:end])
+	}
+	v.exp, v.mul = u.exp, mul
+	return v, nil
+}
+
+// lexLocal reads a number in one of the formats in p.Numbers at lx.pos, and
+// returns it in the plain format with the number of bytes read. The longest one
+// is used if the formats read different lengths, e.g. "1 000" and "1" of
+// "1 000 B", since the shorter one can not be followed by a unit. The returned
+// number is empty if none of the formats accepts the input.
+func (p *Parser) lexLocal(lx *lexer, end int, rate bool) (numToken, int, error) {
+	s := lx.s[lx.pos:end]
+	if t, _ := lexNumber(s, rate); t.num != "" && t.integer == "" && t.frac == "" {
+		return t, len(t.num), nil // NaN or Inf
+	}
+	var (
+		toks   = make([]numToken, len(p.Numbers))
+		widths = make([]int, len(p.Numbers))
+		width  = 0
+	)
+	for i, nf := range p.Numbers {
+		var ok bool
+		if toks[i], widths[i], ok = nf.lexNumber(s, rate); !ok {
+			widths[i] = -1
+		} else if width < widths[i] {
+			width = widths[i] // the shorter ones are followed by digits
+		}
+	}
+	var t numToken
+	for i, u := range toks {
+		switch {
+		case widths[i] != width:
+		case t.num == "":
+			t = u
+		case u.num != t.num:
+			return t, 0, lx.errorf(ReasonAmbiguous, lx.pos, s[:width], nil, "ambiguous number: %s", s[:width])
+		}
+	}
+	return t, width, nil
+}
+
+// perSecond reads " per second" or " per sec" in s[pos:end], and returns the
+// position after it. The words are separated by a single U+0020.
+func perSecond(s string, pos, end int) (int, bool) {
+	for _, words := range [][]string{{"per"}, {"second", "sec"}} {
+		if end <= pos || s[pos] != ' ' {
+			return pos, false
+		}
+		next := tokenEnd(s, pos+1, end)
+		ok := false
+		for _, w := range words {
+			ok = ok || equalFoldASCII(w, s[pos+1:next])
+		}
+		if !ok {
+			return pos, false
+		}
+		pos = next
+	}
+	return pos, true
+}
+
+// tokenEnd returns the end of the token starting at s[start], which is
+// terminated by a space or end.
+func tokenEnd(s string, start, end int) int {
+	for i := start; i < end; {
+		r, n := utf8.DecodeRuneInString(s[i:end])
+		if unicode.IsSpace(r) {
+			return i
+		}
+		i += n
+	}
+	return end
+}
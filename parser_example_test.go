@@ -0,0 +1,37 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"fmt"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func ExampleParser() {
+	p := infounit.Parser{AllowLongNames: true}
+	for _, s := range []string{"1.5 GiB", "1.5 gibibytes", "10 GB please", "5 kB, 6 kB"} {
+		v, err := p.ParseByteCount(s)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(uint64(v))
+	}
+
+	p = infounit.Parser{AllowMissingUnit: true, SIAsBinary: true}
+	v, _ := p.ParseByteCount("4096")
+	fmt.Printf("%S\n", v)
+	v, _ = p.ParseByteCount("4 kB")
+	fmt.Printf("%S\n", v)
+	// Output:
+	// 1610612736
+	// 1610612736
+	// invalid byte count: 10 GB please: trailing input: " please"
+	// invalid byte count: 5 kB, 6 kB: invalid unit expr: kB,
+	// 4KiB
+	// 4KiB
+}
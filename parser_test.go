@@ -0,0 +1,200 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+	"gopkg.in/yaml.v2"
+)
+
+//
+func TestParser_ParseByteCount(t *testing.T) {
+	t.Parallel()
+
+	var (
+		strict  = &infounit.Parser{}
+		lenient = &infounit.Parser{AllowMissingUnit: true, AllowLongNames: true, TrimSpace: true}
+		spaced  = &infounit.Parser{Space: infounit.SpaceRequired}
+		compact = &infounit.Parser{Space: infounit.SpaceForbidden}
+		binary  = &infounit.Parser{SIAsBinary: true}
+		exact   = &infounit.Parser{Rounding: infounit.RoundExact}
+	)
+	tc := []struct {
+		p      *infounit.Parser
+		src    string
+		v      infounit.ByteCount
+		reason infounit.ParseErrorReason
+		off    int
+	}{
+		{strict, "1.5 GiB", infounit.Mebibyte * 1536, 0, 0},
+		{strict, "1.5GiB", infounit.Mebibyte * 1536, 0, 0},
+		{strict, "100 kB", infounit.Kilobyte * 100, 0, 0},
+		{strict, "100 B", 100, 0, 0},
+		{strict, "10 GB please", 0, infounit.ReasonTrailingInput, 5},
+		{strict, "5 kB, 6 kB", 0, infounit.ReasonUnknownUnit, 2},
+		{strict, "5 kB 6 kB", 0, infounit.ReasonTrailingInput, 4},
+		{strict, " 5 kB", 0, infounit.ReasonBadNumber, 0},
+		{strict, "5 kB ", 0, infounit.ReasonTrailingInput, 4},
+		{strict, "5  kB", 0, infounit.ReasonMissingUnit, 2},
+		{strict, "5\tkB", 0, infounit.ReasonDisallowed, 1},
+		{strict, "5", 0, infounit.ReasonMissingUnit, 1},
+		{strict, "5 ", 0, infounit.ReasonMissingUnit, 2},
+		{strict, "", 0, infounit.ReasonBadNumber, 0},
+		{strict, "-5 kB", 0, infounit.ReasonNegative, 0},
+		{strict, "5.5.5 kB", 0, infounit.ReasonBadNumber, 0},
+		{strict, "5 kilobytes", 0, infounit.ReasonDisallowed, 2},
+		{strict, "5 bytes", 0, infounit.ReasonDisallowed, 2},
+		{strict, "5 kbit", 0, infounit.ReasonUnknownUnit, 2},
+		{strict, "1.5 B", 0, infounit.ReasonBadNumber, 0},
+		{strict, "20 EB", 0, infounit.ReasonOutOfRange, 0},
+		{lenient, "  5 kilobytes\n", infounit.Kilobyte * 5, 0, 0},
+		{lenient, "100", 100, 0, 0},
+		{lenient, "1.5", 0, infounit.ReasonBadNumber, 0},
+		{lenient, "5 kB x", 0, infounit.ReasonTrailingInput, 4},
+		{spaced, "5 kB", infounit.Kilobyte * 5, 0, 0},
+		{spaced, "5kB", 0, infounit.ReasonDisallowed, 1},
+		{compact, "5kB", infounit.Kilobyte * 5, 0, 0},
+		{compact, "5 kB", 0, infounit.ReasonDisallowed, 1},
+		{binary, "100 kB", infounit.Kibibyte * 100, 0, 0},
+		{binary, "100 KiB", infounit.Kibibyte * 100, 0, 0},
+		{exact, "0.3 KiB", 0, infounit.ReasonFractional, 0},
+		{exact, "0.5 KiB", 512, 0, 0},
+	}
+	for _, c := range tc {
+		v, err := c.p.ParseByteCount(c.src)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%+v %q: want: %d, got: %d, %v", *c.p, c.src, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		switch {
+		case !errors.As(err, &pe):
+			t.Errorf("%+v %q: ParseError expected, got: %d, %v", *c.p, c.src, v, err)
+		case pe.Reason != c.reason || pe.Offset != c.off || pe.Input != c.src:
+			t.Errorf("%+v %q: want: %v at %d, got: %v at %d: %v", *c.p, c.src, c.reason, c.off, pe.Reason, pe.Offset, err)
+		}
+	}
+}
+
+//
+func TestParser_ParseByteDelta(t *testing.T) {
+	t.Parallel()
+
+	p := &infounit.Parser{}
+	tc := []struct {
+		src string
+		v   infounit.ByteDelta
+		es  string
+	}{
+		{"-1.5 GB", -infounit.ByteDelta(infounit.Megabyte * 1500), ""},
+		{"+5kB", 5000, ""},
+		{"5 KiB", 5120, ""},
+		{"--5 KiB", 0, "invalid byte delta: --5 KiB: invalid expr: --5"},
+		{"- 5 KiB", 0, "invalid byte delta: - 5 KiB: invalid expr: -"},
+		{"-8 EiB", infounit.ByteDelta(-1 << 63), ""},
+		{"8 EiB", 0, "invalid byte delta: 8 EiB: out of range"},
+		{"-8 EiB!", 0, "invalid byte delta: -8 EiB!: invalid unit expr: EiB!"},
+	}
+	for _, c := range tc {
+		v, err := p.ParseByteDelta(c.src)
+		switch {
+		case c.es == "" && (err != nil || v != c.v):
+			t.Errorf("%q: want: %d, got: %d, %v", c.src, c.v, v, err)
+		case c.es != "" && (err == nil || err.Error() != c.es):
+			t.Errorf("%q: error %q expected, got: %d, %v", c.src, c.es, v, err)
+		}
+	}
+}
+
+//
+func TestParser_ParseBitRate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		strict = &infounit.Parser{}
+		long   = &infounit.Parser{AllowLongNames: true, AllowMissingUnit: true}
+	)
+	tc := []struct {
+		p      *infounit.Parser
+		src    string
+		v      infounit.BitRate
+		reason infounit.ParseErrorReason
+	}{
+		{strict, "100 Mbit/s", infounit.MegabitPerSecond * 100, 0},
+		{strict, "-2.5kbps", infounit.KilobitPerSecond * -2.5, 0},
+		{strict, "1 Gibit/s", infounit.GibibitPerSecond, 0},
+		{strict, "100 Mbit/s up", 0, infounit.ReasonTrailingInput},
+		{strict, "5 kilobits per second", 0, infounit.ReasonDisallowed},
+		{strict, "5 kbit", 0, infounit.ReasonUnknownUnit},
+		{strict, "5", 0, infounit.ReasonMissingUnit},
+		{long, "5 kilobits per second", infounit.KilobitPerSecond * 5, 0},
+		{long, "5 kbit per sec", infounit.KilobitPerSecond * 5, 0},
+		{long, "5 kilobits per second!", 0, infounit.ReasonUnknownUnit},
+		{long, "5 kilobits per minute", 0, infounit.ReasonUnknownUnit},
+		{long, "5 kilobits  per second", 0, infounit.ReasonUnknownUnit},
+		{long, "5 kilobits per second now", 0, infounit.ReasonTrailingInput},
+		{long, "5", 5, 0},
+	}
+	for _, c := range tc {
+		v, err := c.p.ParseBitRate(c.src)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%q: want: %v, got: %v, %v", c.src, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		if !errors.As(err, &pe) || pe.Reason != c.reason {
+			t.Errorf("%q: %v expected, got: %v, %v", c.src, c.reason, v, err)
+		}
+	}
+	if v, err := long.ParseByteRate("2 kilobytes per second"); err != nil || v != infounit.KilobytePerSecond*2 {
+		t.Errorf("ParseByteRate: unexpected result: %v, %v", v, err)
+	}
+}
+
+//
+func TestParser_unmarshal(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"10 GB please", "5 kB, 6 kB", "5 kB 6 kB"} {
+		var bc infounit.ByteCount
+		if err := bc.UnmarshalText([]byte(s)); !errors.Is(err, infounit.ErrMalformedRepresentation) {
+			t.Errorf("Text %q: ErrMalformedRepresentation expected, got: %v, %v", s, bc, err)
+		}
+		if err := json.Unmarshal([]byte(`"`+s+`"`), &bc); !errors.Is(err, infounit.ErrMalformedRepresentation) {
+			t.Errorf("JSON %q: ErrMalformedRepresentation expected, got: %v, %v", s, bc, err)
+		}
+		if err := yaml.Unmarshal([]byte("- "+s), &[]infounit.ByteCount{bc}); !errors.Is(err, infounit.ErrMalformedRepresentation) {
+			t.Errorf("YAML %q: ErrMalformedRepresentation expected, got: %v", s, err)
+		}
+		var br infounit.BitRate
+		if err := br.UnmarshalText([]byte(s)); !errors.Is(err, infounit.ErrMalformedRepresentation) {
+			t.Errorf("BitRate Text %q: ErrMalformedRepresentation expected, got: %v, %v", s, br, err)
+		}
+	}
+	var bd infounit.ByteDelta
+	if err := json.Unmarshal([]byte(`" -5 kilobytes "`), &bd); err != nil || bd != -5000 {
+		t.Errorf("JSON: unexpected result: %v, %v", bd, err)
+	}
+}
+
+// AllocsPerRun can not be called in parallel tests.
+func TestParser_allocs(t *testing.T) {
+	p := &infounit.Parser{AllowLongNames: true}
+	if n := testing.AllocsPerRun(100, func() {
+		_, _ = p.ParseByteCount("1.5 GiB")
+		_, _ = p.ParseByteDelta("-12kB")
+		_, _ = p.ParseBitRate("10 megabits per second")
+	}); n != 0 {
+		t.Errorf("unexpected allocations: %v", n)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit
+
+import (
+	"math"
+	"strconv"
+)
+
+// tcRateUnits is the unit table of the rates of the Linux tc command, the same
+// as the one in iproute2 except that the names are in lower case, since they
+// are case-insensitive. The units ending with "bps" are bytes per second, not
+// bits. Each value is in bits per second.
+var tcRateUnits = []struct {
+	name  string
+	scale float64
+}{
+	{"bit", 1},
+	{"kibit", float64(kibi)},
+	{"kbit", float64(kilo)},
+	{"mibit", float64(mebi)},
+	{"mbit", float64(mega)},
+	{"gibit", float64(gibi)},
+	{"gbit", float64(giga)},
+	{"tibit", float64(tebi)},
+	{"tbit", float64(tera)},
+	{"bps", 8},
+	{"kibps", 8 * float64(kibi)},
+	{"kbps", 8 * float64(kilo)},
+	{"mibps", 8 * float64(mebi)},
+	{"mbps", 8 * float64(mega)},
+	{"gibps", 8 * float64(gibi)},
+	{"gbps", 8 * float64(giga)},
+	{"tibps", 8 * float64(tebi)},
+	{"tbps", 8 * float64(tera)},
+}
+
+// ParseTCBitRate converts a rate string in the syntax of the Linux tc command
+// (iproute2) into a BitRate value, e.g. "100mbit", "1gibit" or "10mbps". The
+// syntax is a non-negative decimal number followed by a unit of tc without
+// space. A number without unit is in bits per second. The units are
+// case-insensitive, and "bps" means bytes per second, as tc does. That is,
+// "10mbps" is 80 Mbit/s, not 10 Mbit/s.
+func ParseTCBitRate(s string) (BitRate, error) {
+	lx := lexer{s: s, typ: "BitRate"}
+	t, _ := lexNumber(s, false)
+	if t.num == "" {
+		tok := s[:tokenEnd(s, 0, len(s))]
+		reason := ReasonBadNumber
+		if tok != "" && tok[0] == '-' {
+			if t, _ := lexNumber(tok[1:], false); t.num != "" {
+				reason = ReasonNegative // e.g. "-5mbit"
+			}
+		}
+		return 0, lx.errorf(reason, 0, tok, nil, "invalid expr: %s", tok)
+	}
+	lx.pos = len(t.num)
+	scale := 1.0
+	if t.unit != "" {
+		scale = 0
+		for _, u := range tcRateUnits {
+			if equalFoldASCII(u.name, t.unit) {
+				scale = u.scale
+				break
+			}
+		}
+		if scale == 0 {
+			return 0, lx.errorf(ReasonUnknownUnit, lx.pos, t.unit, nil, "unknown unit for tc: %s", t.unit)
+		}
+	}
+	v, err := strconv.ParseFloat(t.num, 64)
+	if err == nil && math.IsInf(v*scale, 0) {
+		err = ErrOutOfRange
+	}
+	if err != nil {
+		return 0, lx.errorf(numErrReason(err), 0, t.num, err, "%v", err)
+	}
+	return BitRate(v * scale), nil
+}
+
+// FormatTCBitRate returns the representation of the BitRate value in the same
+// way as the Linux tc command prints rates, e.g. "100Mbit" and "1500Kbit" as
+// in the output of "tc -s qdisc". If iec is true, the binary prefixes are used
+// as the -iec option of tc, e.g. "1Gibit". The value is truncated to an
+// integer number of bits per second, and the larger units are used only if
+// the value is a multiple of them or is large enough, so the result may lose
+// precision, e.g. "1234Kbit" for 1234567 bit/s, the same as tc. ErrOutOfRange
+// is returned for negative values, NaN and infinities.
+func FormatTCBitRate(v BitRate, iec bool) (string, error) {
+	x := float64(v)
+	if math.IsNaN(x) || x < 0 || 0x1p64 <= x {
+		return "", ErrOutOfRange
+	}
+	rate := uint64(x)
+	base, infix := uint64(kilo), ""
+	if iec {
+		base, infix = kibi, "i"
+	}
+	units := [5]string{"", "K", "M", "G", "T"}
+	i := 0
+	for ; i < len(units)-1; i++ {
+		if rate < base || (rate%base != 0 && rate < 1000*base) {
+			break
+		}
+		rate /= base
+	}
+	dst := make([]byte, 0, 24)
+	dst = strconv.AppendUint(dst, rate, 10)
+	dst = append(dst, units[i]...)
+	if i != 0 {
+		dst = append(dst, infix...)
+	}
+	return string(append(dst, "bit"...)), nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Hirotsuna Mizuno. All rights reserved.
+// Use of this source code is governed by the MIT license that can be found in
+// the LICENSE file.
+
+package infounit_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tunabay/go-infounit"
+)
+
+//
+func TestParseTCBitRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		s      string
+		v      infounit.BitRate
+		reason infounit.ParseErrorReason
+	}{
+		{"100mbit", infounit.MegabitPerSecond * 100, 0},
+		{"100Mbit", infounit.MegabitPerSecond * 100, 0},
+		{"1gbit", infounit.GigabitPerSecond, 0},
+		{"512kbit", infounit.KilobitPerSecond * 512, 0},
+		{"1gibit", infounit.GibibitPerSecond, 0},
+		{"1.5Mibit", infounit.MebibitPerSecond * 1.5, 0},
+		{"10mbps", infounit.MegabitPerSecond * 80, 0},
+		{"10MBps", infounit.MegabitPerSecond * 80, 0},
+		{"1kibps", infounit.KibibitPerSecond * 8, 0},
+		{"3Bps", 24, 0},
+		{"1000bit", 1000, 0},
+		{"1000", 1000, 0},
+		{"2tbit", infounit.TerabitPerSecond * 2, 0},
+		{"1pbit", 0, infounit.ReasonUnknownUnit},
+		{"1mb", 0, infounit.ReasonUnknownUnit},
+		{"10 mbit", 0, infounit.ReasonUnknownUnit},
+		{"10mbit/s", 0, infounit.ReasonUnknownUnit},
+		{"-10mbit", 0, infounit.ReasonNegative},
+		{"mbit", 0, infounit.ReasonBadNumber},
+		{"", 0, infounit.ReasonBadNumber},
+	}
+	for _, c := range tc {
+		v, err := infounit.ParseTCBitRate(c.s)
+		if c.reason == 0 {
+			if err != nil || v != c.v {
+				t.Errorf("%q: want: %v, got: %v, %v", c.s, c.v, v, err)
+			}
+			continue
+		}
+		var pe *infounit.ParseError
+		if !errors.As(err, &pe) || pe.Reason != c.reason {
+			t.Errorf("%q: %v expected, got: %v, %v", c.s, c.reason, v, err)
+		}
+	}
+	huge := "1" + strings.Repeat("0", 300) + "tbit"
+	es := "invalid bit rate: " + huge + ": out of range"
+	if _, err := infounit.ParseTCBitRate(huge); err == nil || err.Error() != es {
+		t.Errorf("want: %q, got: %v", es, err)
+	}
+}
+
+//
+func TestFormatTCBitRate(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		v   infounit.BitRate
+		iec bool
+		s   string
+	}{
+		{0, false, "0bit"},
+		{800, false, "800bit"},
+		{8000, false, "8Kbit"},
+		{infounit.MegabitPerSecond * 100, false, "100Mbit"},
+		{infounit.GigabitPerSecond, false, "1Gbit"},
+		{infounit.KilobitPerSecond * 1500, false, "1500Kbit"},
+		{infounit.MegabitPerSecond * 1.5, false, "1500Kbit"},
+		{1234567, false, "1234Kbit"},
+		{999999, false, "999999bit"},
+		{infounit.TerabitPerSecond * 5000, false, "5000Tbit"},
+		{12.9, false, "12bit"},
+		{800, true, "800bit"},
+		{infounit.GibibitPerSecond, true, "1Gibit"},
+		{infounit.MebibitPerSecond * 100, true, "100Mibit"},
+		{infounit.MegabitPerSecond * 100, true, "97656Kibit"},
+	}
+	for _, c := range tc {
+		s, err := infounit.FormatTCBitRate(c.v, c.iec)
+		if err != nil || s != c.s {
+			t.Errorf("%v iec=%v: want: %q, got: %q, %v", c.v, c.iec, c.s, s, err)
+		}
+	}
+	for _, v := range []infounit.BitRate{-1, infounit.BitRate(math.NaN()), infounit.BitRate(math.Inf(1))} {
+		if s, err := infounit.FormatTCBitRate(v, false); !errors.Is(err, infounit.ErrOutOfRange) {
+			t.Errorf("%v: ErrOutOfRange expected, got: %q, %v", v, s, err)
+		}
+	}
+}
@@ -5,8 +5,12 @@
 package infounit
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"math/bits"
 	"strconv"
+	"strings"
 )
 
 //
@@ -27,6 +31,7 @@ const (
 
 //
 type prefix struct {
+	base       uint64
 	thresholds [6]uint64
 	preAbbr    [6]string
 	preFull    [6]string
@@ -35,83 +40,249 @@ type prefix struct {
 //
 var (
 	siPrefix = &prefix{
+		base:       1000,
 		thresholds: [6]uint64{kilo, mega, giga, tera, peta, exa},
 		preAbbr:    [6]string{"k", "M", "G", "T", "P", "E"},
 		preFull:    [6]string{"kilo", "mega", "giga", "tera", "peta", "exa"},
 	}
 	binPrefix = &prefix{
+		base:       1024,
 		thresholds: [6]uint64{kibi, mebi, gibi, tebi, pebi, exbi},
 		preAbbr:    [6]string{"Ki", "Mi", "Gi", "Ti", "Pi", "Ei"},
 		preFull:    [6]string{"kibi", "mebi", "gibi", "tebi", "pebi", "exbi"},
 	}
 )
 
-// formatUint is used by both ByteCount and BitCount.
-func (p *prefix) formatUint(v uint64, precision int, full, space bool, uAbbr, uFull string) string {
-	pre, unit, pls := p.preAbbr, uAbbr, ""
-	if full {
-		pre, unit, pls = p.preFull, uFull, "s"
+// absInt64 returns the absolute value of v as uint64. Unlike the negation in
+// int64, this also works for math.MinInt64.
+func absInt64(v int64) uint64 {
+	if v < 0 {
+		return -uint64(v)
 	}
-	sp := ""
-	if space {
-		sp = " "
+	return uint64(v)
+}
+
+// signInt64 returns the int64 value with the magnitude mag, negated if neg is
+// true. ErrOutOfRange is returned if the value can not be represented as int64.
+func signInt64(mag uint64, neg bool) (int64, error) {
+	switch {
+	case neg && mag <= 1<<63:
+		return int64(-mag), nil
+	case !neg && mag <= math.MaxInt64:
+		return int64(mag), nil
 	}
+	return 0, ErrOutOfRange
+}
 
-	if v == 1 {
-		pls = ""
+// index returns the index of the largest prefix that does not exceed v, or -1
+// if v is less than the smallest prefix.
+func (p *prefix) index(v float64) int {
+	for i := 5; 0 <= i; i-- {
+		if float64(p.thresholds[i]) <= v {
+			return i
+		}
 	}
-	if v < p.thresholds[0] {
-		return strconv.FormatUint(v, 10) + sp + unit + pls
+	return -1
+}
+
+// intPart returns the integer part of the non-negative decimal number num.
+func intPart(num []byte) uint64 {
+	var v uint64
+	for _, c := range num {
+		if c < '0' || '9' < c {
+			break
+		}
+		v = v*10 + uint64(c-'0')
 	}
-	var ret string
-	for i := 0; i < 6; i++ {
-		if i < 5 && p.thresholds[i+1] <= v {
-			continue
+	return v
+}
+
+// appendQuotient appends the decimal representation of n/d to dst, computed
+// with integer arithmetic only. The number of digits after the decimal point
+// is specified by precision, and the last digit is rounded half to even. If
+// precision is negative, the shortest representation of the float64 quotient
+// is appended instead, e.g. "941.900559425354" rather than all the 23 digits.
+// d must not exceed math.MaxUint64/10.
+func appendQuotient(dst []byte, n, d uint64, precision int) []byte {
+	if precision < 0 {
+		return strconv.AppendFloat(dst, float64(n)/float64(d), 'f', -1, 64)
+	}
+	q, r := n/d, n%d
+	start := len(dst)
+	dst = strconv.AppendUint(dst, q, 10)
+	if 0 < precision {
+		dst = append(dst, '.')
+		for i := 0; i < precision; i++ {
+			r *= 10
+			dst = append(dst, byte('0'+r/d))
+			r %= d
 		}
-		if v == p.thresholds[i] {
-			pls = ""
+	}
+	if h := d - r; h < r || (h == r && (dst[len(dst)-1]-'0')&1 == 1) {
+		dst = roundUpDecimal(dst, start)
+	}
+	return dst
+}
+
+// roundUpDecimal adds one to the last digit of the decimal number in
+// dst[start:], propagating the carry. A leading '1' is inserted when the carry
+// goes beyond the first digit, e.g. "99.9" becomes "100.0".
+func roundUpDecimal(dst []byte, start int) []byte {
+	for i := len(dst) - 1; start <= i; i-- {
+		switch dst[i] {
+		case '.':
+			continue
+		case '9':
+			dst[i] = '0'
+			continue
 		}
-		bv := float64(v) / float64(p.thresholds[i])
-		ret = strconv.FormatFloat(bv, 'f', precision, 64) + sp + pre[i] + unit + pls
-		break
+		dst[i]++
+		return dst
+	}
+	dst = append(dst, 0)
+	copy(dst[start+1:], dst[start:])
+	dst[start] = '1'
+	return dst
+}
+
+// appendFloat appends v divided by the i-th prefix to dst. If i is negative, v
+// is appended as is.
+func (p *prefix) appendFloat(dst []byte, v float64, i, precision int) []byte {
+	if 0 <= i {
+		v /= float64(p.thresholds[i])
 	}
-	return ret
+	return strconv.AppendFloat(dst, v, 'f', precision, 64)
 }
 
-// formatFloat is used by ByteRate.
-func (p *prefix) formatFloat(v float64, precision int, full, space bool, uAbbr, sufAbbr string) string {
-	pre, unit, pls, suf := p.preAbbr, uAbbr, "", sufAbbr
-	if full {
-		pre, unit, pls, suf = p.preFull, unitBitRateFull, "s", " "+unitBitRateLongSuffix
+// jsonNULL is the null expression in JSON.
+const jsonNULL = "null"
+
+// parseUint64 parses a string of decimal digits as a uint64 value. Unlike
+// strconv.ParseUint, the returned error wraps ErrOutOfRange if the value
+// exceeds the range of uint64.
+func parseUint64(s string) (uint64, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, ErrOutOfRange
+		}
+		return 0, err
 	}
-	sp := ""
-	if space {
-		sp = " "
+	return v, nil
+}
+
+// mulUint64 returns x * y, or ErrOutOfRange if the product overflows uint64.
+func mulUint64(x, y uint64) (uint64, error) {
+	hi, lo := bits.Mul64(x, y)
+	if hi != 0 {
+		return 0, ErrOutOfRange
 	}
+	return lo, nil
+}
 
-	if v == 1.00 {
-		pls = ""
+// checkUintExpr returns ErrOutOfRange if s is a numeric literal, an integer or
+// a floating-point number with an optional sign, whose value can not be
+// represented as uint64. Otherwise it returns nil. This is used to report
+// out-of-range numeric values in JSON and YAML.
+func checkUintExpr(s string) error {
+	if len(s) < 1 || !strings.ContainsRune("+-.0123456789", rune(s[0])) {
+		return nil
 	}
-	if math.IsNaN(v) || math.IsInf(v, +1) || math.IsInf(v, -1) {
-		return strconv.FormatFloat(v, 'f', precision, 64) + sp + unit + pls + suf
+	d, neg := s, false
+	switch d[0] {
+	case '+':
+		d = d[1:]
+	case '-':
+		d, neg = d[1:], true
 	}
-	if v < float64(p.thresholds[0]) {
-		return strconv.FormatFloat(v, 'f', precision, 64) + sp + unit + pls + suf
+	if len(d) < 1 {
+		return nil
 	}
-	var ret string
-	for i := 0; i < 6; i++ {
-		if i < 5 && float64(p.thresholds[i+1]) <= v {
-			continue
+	if strings.Trim(d, "0123456789") == "" { // integer
+		if neg && strings.TrimLeft(d, "0") != "" {
+			return ErrOutOfRange
 		}
-		if v == float64(p.thresholds[i]) {
-			pls = ""
+		if _, err := parseUint64(d); err != nil {
+			return err
 		}
-		bv := v / float64(p.thresholds[i])
-		ret = strconv.FormatFloat(bv, 'f', precision, 64) + sp + pre[i] + unit + pls + suf
-		break
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return nil
 	}
-	return ret
+	if f < 0 || 0x1p64 <= f {
+		return ErrOutOfRange
+	}
+	return nil
 }
 
-// jsonNULL is the null expression in JSON.
-const jsonNULL = "null"
+// checkIntExpr is the same as checkUintExpr except that it checks the range of
+// int64 instead of uint64.
+func checkIntExpr(s string) error {
+	if len(s) < 1 || !strings.ContainsRune("+-.0123456789", rune(s[0])) {
+		return nil
+	}
+	d := s
+	if d[0] == '+' || d[0] == '-' {
+		d = d[1:]
+	}
+	if len(d) < 1 {
+		return nil
+	}
+	if strings.Trim(d, "0123456789") == "" { // integer
+		if _, err := strconv.ParseInt(s, 10, 64); errors.Is(err, strconv.ErrRange) {
+			return ErrOutOfRange
+		}
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return nil
+	}
+	if f < -0x1p63 || 0x1p63 <= f {
+		return ErrOutOfRange
+	}
+	return nil
+}
+
+// rangeError returns the error for the numeric literal s, found in a JSON or
+// YAML field, that can not be represented as the type typ. This is called when
+// checkUintExpr or checkIntExpr returns ErrOutOfRange.
+func rangeError(typ, s string, signed bool) error {
+	reason := ReasonOutOfRange
+	if !signed && strings.HasPrefix(s, "-") {
+		reason = ReasonNegative
+	}
+	return &ParseError{
+		Input:  s,
+		Token:  s,
+		Type:   typ,
+		Reason: reason,
+		Err:    ErrOutOfRange,
+		msg:    s + ": " + ErrOutOfRange.Error(),
+	}
+}
+
+// yamlInput returns the YAML field decoded by unmarshal as a string, which is
+// the input of the error returned by UnmarshalYAML.
+func yamlInput(unmarshal func(interface{}) error) string {
+	var v interface{}
+	if unmarshal(&v) != nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// typeError returns the error for the JSON or YAML field, the raw input of
+// which is s, that is neither a number nor a string, e.g. true or an array.
+func typeError(typ, s string) error {
+	return &ParseError{
+		Input:  s,
+		Token:  s,
+		Type:   typ,
+		Reason: ReasonBadNumber,
+		Err:    ErrMalformedRepresentation,
+		msg:    ErrMalformedRepresentation.Error() + ": unexpected type",
+	}
+}